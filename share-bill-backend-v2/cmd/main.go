@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
@@ -45,14 +46,22 @@ func main() {
 
 
 	userService := services.NewUserService(store, tokenMaker, cfg, redisClient, emailSender, uploadService)
-	eventService := services.NewEventService(store)
+	eventService := services.NewEventService(store, cfg.TrashRetention)
 	participantService := services.NewParticipantService(store)
-	expenseService := services.NewExpenseService(store)
+	expenseService := services.NewExpenseService(store, cfg.TrashRetention)
 	settlementService := services.NewSettlementService(store)
 	paymentService := services.NewPaymentService(store)
 
 	paymentRequestService := services.NewPaymentRequestService(connPool)
 	passwordService := services.NewPasswordService(connPool)
+	inviteService := services.NewInviteService(store, cfg.InviteBaseURL)
+	exchangeRateService := services.NewExchangeRateService(store, cfg.ExchangeRatesFile)
+	categoryService := services.NewCategoryService(store)
+	attachmentService := services.NewAttachmentService(store, uploadService)
+
+	// Job xoa han expense/event qua han trong thung rac
+	trashPurger := services.NewTrashPurger(store, cfg.TrashRetention, uploadService)
+	go trashPurger.Run(context.Background())
 
 	userHandler := handlers.NewUserHandler(userService)
 	eventHandler := handlers.NewEventHandler(eventService)
@@ -63,6 +72,10 @@ func main() {
 
 	paymentRequestHandler := handlers.NewPaymentRequestHandler(paymentRequestService)
 	passwordHandler := handlers.NewPasswordHandler(passwordService)
+	inviteHandler := handlers.NewInviteHandler(inviteService)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
 
 	app := fiber.New(fiber.Config{
 		AppName:   "Sharever API",
@@ -75,9 +88,10 @@ func main() {
 
 	app.Use(logger.New())
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, HEAD, PUT, DELETE, PATCH",
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, If-Match",
+		AllowMethods:  "GET, POST, HEAD, PUT, DELETE, PATCH",
+		ExposeHeaders: "ETag",
 	}))
 
 	routes.SetupRoutes(
@@ -94,6 +108,10 @@ func main() {
 
 	routes.SetupPaymentRequestRoutes(app, tokenMaker, paymentRequestHandler)
 	routes.SetupPasswordRoutes(app, tokenMaker, passwordHandler)
+	routes.SetupInviteRoutes(app, tokenMaker, inviteHandler)
+	routes.SetupExchangeRateRoutes(app, tokenMaker, exchangeRateHandler)
+	routes.SetupCategoryRoutes(app, tokenMaker, categoryHandler)
+	routes.SetupAttachmentRoutes(app, tokenMaker, attachmentHandler)
 
 	log.Printf("Server is running on %s", cfg.ServerAddress)
 	if err := app.Listen(cfg.ServerAddress); err != nil {
@@ -25,6 +25,15 @@ type Config struct {
 	EmailSenderPassword string `mapstructure:"EMAIL_SENDER_PASSWORD"`
 
 	CloudinaryURL string `mapstructure:"CLOUDINARY_URL"`
+
+	// Link moi tham gia event, token duoc noi vao cuoi (vd: https://sharever.app/join)
+	InviteBaseURL string `mapstructure:"INVITE_BASE_URL"`
+
+	// Thoi gian giu expense/event trong thung rac truoc khi xoa han (mac dinh 720h)
+	TrashRetention time.Duration `mapstructure:"TRASH_RETENTION"`
+
+	// File JSON ty gia local ({"base": "USD", "rates": {...}}), bo trong thi chi nhap tay
+	ExchangeRatesFile string `mapstructure:"EXCHANGE_RATES_FILE"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -44,6 +53,9 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.BindEnv("EMAIL_SENDER_ADDRESS")
 	viper.BindEnv("EMAIL_SENDER_PASSWORD")
 	viper.BindEnv("CLOUDINARY_URL")
+	viper.BindEnv("INVITE_BASE_URL")
+	viper.BindEnv("TRASH_RETENTION")
+	viper.BindEnv("EXCHANGE_RATES_FILE")
 
 	err = viper.ReadInConfig()
 	if err != nil {
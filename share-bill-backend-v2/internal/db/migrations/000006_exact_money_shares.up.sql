@@ -0,0 +1,44 @@
+-- Luu so tien chia cho tung nguoi huong loi, tong luon bang expenses.total_amount
+ALTER TABLE expense_beneficiaries
+ADD COLUMN share_amount NUMERIC(14,2) NOT NULL DEFAULT 0 CHECK (share_amount >= 0);
+
+-- Backfill tu split_ratio cu: lam tron theo currency cua event
+UPDATE expense_beneficiaries eb
+SET share_amount = ROUND(
+    e.total_amount * eb.split_ratio / NULLIF(t.total_ratio, 0),
+    CASE WHEN ev.currency IN ('VND', 'JPY', 'KRW') THEN 0 ELSE 2 END
+)
+FROM expenses e
+JOIN events ev ON ev.event_id = e.event_id
+JOIN (
+    SELECT expense_id, SUM(split_ratio) AS total_ratio
+    FROM expense_beneficiaries
+    GROUP BY expense_id
+) t ON t.expense_id = e.expense_id
+WHERE eb.expense_id = e.expense_id;
+
+-- Don phan chenh lech do lam tron vao phan lon nhat cua moi expense
+UPDATE expense_beneficiaries eb
+SET share_amount = eb.share_amount + d.diff
+FROM (
+    SELECT DISTINCT ON (eb2.expense_id)
+        eb2.beneficiary_id,
+        e.total_amount - SUM(eb2.share_amount) OVER (PARTITION BY eb2.expense_id) AS diff
+    FROM expense_beneficiaries eb2
+    JOIN expenses e ON e.expense_id = eb2.expense_id
+    ORDER BY eb2.expense_id, eb2.share_amount DESC, eb2.beneficiary_id
+) d
+WHERE eb.beneficiary_id = d.beneficiary_id AND d.diff <> 0;
+
+-- Tuong tu cho payers (truoc day chia deu bang float)
+UPDATE expense_payers ep
+SET paid_amount = ep.paid_amount + d.diff
+FROM (
+    SELECT DISTINCT ON (ep2.expense_id)
+        ep2.payer_id,
+        e.total_amount - SUM(ep2.paid_amount) OVER (PARTITION BY ep2.expense_id) AS diff
+    FROM expense_payers ep2
+    JOIN expenses e ON e.expense_id = ep2.expense_id
+    ORDER BY ep2.expense_id, ep2.paid_amount DESC, ep2.payer_id
+) d
+WHERE ep.payer_id = d.payer_id AND d.diff <> 0;
@@ -0,0 +1,11 @@
+-- Luu cach chia tien ma nguoi dung da chon cho moi expense
+ALTER TABLE expenses
+ADD COLUMN split_mode TEXT NOT NULL DEFAULT 'shares'
+    CHECK (split_mode IN ('equal', 'shares', 'percentage', 'exact', 'adjustment'));
+
+-- Gia tri nguoi dung nhap cho tung beneficiary (weight / % / so tien / dieu chinh)
+ALTER TABLE expense_beneficiaries
+ADD COLUMN split_input NUMERIC(14,4);
+
+-- Du lieu cu chi con ratio da chuan hoa, dung lam weight
+UPDATE expense_beneficiaries SET split_input = split_ratio;
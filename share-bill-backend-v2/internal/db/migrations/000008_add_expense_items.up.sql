@@ -0,0 +1,39 @@
+-- Chia tien theo tung mon tren hoa don
+ALTER TABLE expenses DROP CONSTRAINT IF EXISTS expenses_split_mode_check;
+ALTER TABLE expenses ADD CONSTRAINT expenses_split_mode_check
+    CHECK (split_mode IN ('equal', 'shares', 'percentage', 'exact', 'adjustment', 'itemized'));
+
+CREATE TABLE IF NOT EXISTS expense_items (
+    item_id BIGSERIAL PRIMARY KEY,
+    item_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    expense_id BIGINT NOT NULL REFERENCES expenses(expense_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    amount NUMERIC(14,2) NOT NULL CHECK (amount >= 0)
+);
+
+-- Ai an mon nao, weight tuong doi va so tien sau khi da phan bo phu thu/giam gia
+CREATE TABLE IF NOT EXISTS expense_item_beneficiaries (
+    item_id BIGINT NOT NULL REFERENCES expense_items(item_id) ON DELETE CASCADE,
+    participant_id BIGINT NOT NULL REFERENCES participants(participant_id) ON DELETE CASCADE,
+    weight NUMERIC(10,4) NOT NULL CHECK (weight >= 0),
+    share_amount NUMERIC(14,2) NOT NULL CHECK (share_amount >= 0),
+    PRIMARY KEY (item_id, participant_id)
+);
+
+-- Phu thu (VAT, phi phuc vu, tip) va giam gia cua ca hoa don, phan bo theo gia tung mon.
+-- percent NULL nghia la nguoi dung nhap so tien co dinh.
+CREATE TABLE IF NOT EXISTS expense_adjustments (
+    adjustment_id BIGSERIAL PRIMARY KEY,
+    adjustment_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    expense_id BIGINT NOT NULL REFERENCES expenses(expense_id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('surcharge', 'discount')),
+    name TEXT NOT NULL,
+    percent NUMERIC(7,4) CHECK (percent >= 0),
+    amount NUMERIC(14,2) NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_items_expense_id ON expense_items(expense_id);
+CREATE INDEX IF NOT EXISTS idx_expense_adjustments_expense_id ON expense_adjustments(expense_id);
+
+-- Share cua tung nguoi van duoc cong don vao expense_beneficiaries.share_amount
+-- nen GetEventBalances khong can doi.
@@ -0,0 +1,9 @@
+-- Ai ghi nhan settlement va vi sao; but toan dao nguoc tro ve settlement goc qua reversal_of
+ALTER TABLE settlements
+ADD COLUMN created_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+ADD COLUMN reason TEXT,
+ADD COLUMN reversal_of BIGINT REFERENCES settlements(settlement_id) ON DELETE CASCADE;
+
+-- Moi settlement chi duoc dao nguoc 1 lan
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settlements_reversal_of ON settlements(reversal_of) WHERE reversal_of IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_settlements_event_id ON settlements(event_id);
@@ -0,0 +1,14 @@
+-- Settlement ghi tay phai duoc nguoi nhan (hoac chu event neu nguoi nhan la khach) xac nhan.
+-- Chi settlement 'confirmed' moi duoc tinh vao balance; du lieu cu coi nhu da xac nhan.
+ALTER TABLE settlements
+ADD COLUMN status TEXT NOT NULL DEFAULT 'confirmed' CHECK (status IN ('pending', 'confirmed', 'rejected')),
+ADD COLUMN resolved_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+ADD COLUMN resolved_at TIMESTAMPTZ,
+ADD COLUMN reject_reason TEXT;
+
+UPDATE settlements SET resolved_at = created_at;
+
+-- But toan dao nguoc bi tu choi thi duoc phep dao nguoc lai lan nua
+DROP INDEX IF EXISTS idx_settlements_reversal_of;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settlements_reversal_of ON settlements(reversal_of)
+    WHERE reversal_of IS NOT NULL AND status <> 'rejected';
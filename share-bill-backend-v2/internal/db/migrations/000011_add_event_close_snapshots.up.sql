@@ -0,0 +1,17 @@
+-- Vong doi event: chi con 'active' va 'closed', dong bo voi is_closed
+UPDATE events SET status = CASE WHEN is_closed THEN 'closed' ELSE 'active' END;
+ALTER TABLE events
+ADD CONSTRAINT events_status_check CHECK (status IN ('active', 'closed'));
+
+-- Moi lan dong event luu lai summary cuoi cung (balances + settlement plan) tai thoi diem dong
+CREATE TABLE IF NOT EXISTS event_snapshots(
+    snapshot_id BIGSERIAL PRIMARY KEY,
+    snapshot_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    event_id BIGINT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    closed_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+    summary JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reopened_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_snapshots_event_id ON event_snapshots(event_id, created_at DESC);
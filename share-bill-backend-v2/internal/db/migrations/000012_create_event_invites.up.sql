@@ -0,0 +1,15 @@
+-- Link moi tham gia event: chi ai co token hop le moi join duoc (khong con join bang event UUID)
+CREATE TABLE IF NOT EXISTS event_invites(
+    invite_id BIGSERIAL PRIMARY KEY,
+    invite_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    event_id BIGINT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE,
+    created_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ,
+    max_uses INT CHECK (max_uses IS NULL OR max_uses > 0),
+    use_count INT NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_invites_event_id ON event_invites(event_id);
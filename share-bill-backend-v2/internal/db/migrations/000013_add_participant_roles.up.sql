@@ -0,0 +1,12 @@
+-- Quyen theo event: owner > admin > member > viewer (viewer chi duoc xem)
+ALTER TABLE participants
+ADD COLUMN role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member', 'viewer'));
+
+UPDATE participants p
+SET role = 'owner'
+FROM events e
+WHERE p.event_id = e.event_id AND p.user_id = e.creator_id;
+
+-- Nguoi tao expense: member chi duoc sua/xoa expense cua minh, admin tro len sua/xoa tat ca
+ALTER TABLE expenses
+ADD COLUMN created_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL;
@@ -0,0 +1,20 @@
+-- Xoa tai khoan thi creator_id ve NULL (owner moi duoc chuyen tu dong truoc khi xoa)
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_creator_id_fkey;
+ALTER TABLE events
+ADD CONSTRAINT events_creator_id_fkey FOREIGN KEY (creator_id) REFERENCES users(user_id) ON DELETE SET NULL;
+
+-- Yeu cau chuyen quyen owner: chi co hieu luc khi nguoi nhan chap nhan
+CREATE TABLE IF NOT EXISTS event_ownership_transfers(
+    transfer_id BIGSERIAL PRIMARY KEY,
+    transfer_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    event_id BIGINT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    from_participant_id BIGINT NOT NULL REFERENCES participants(participant_id) ON DELETE CASCADE,
+    to_participant_id BIGINT NOT NULL REFERENCES participants(participant_id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMPTZ
+);
+
+-- Moi event chi co toi da 1 yeu cau dang cho
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ownership_transfers_pending
+ON event_ownership_transfers(event_id) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+-- Link nhan khach: invite gan voi 1 participant khach, user join bang link se thay the khach do
+ALTER TABLE event_invites
+ADD COLUMN participant_id BIGINT REFERENCES participants(participant_id) ON DELETE CASCADE;
@@ -0,0 +1,29 @@
+-- Xoa mem participant: an khoi expense moi nhung giu nguyen lich su payer/beneficiary/settlement
+ALTER TABLE participants
+ADD COLUMN removed_at TIMESTAMPTZ;
+
+-- total_participants chi dem participant chua bi xoa
+CREATE OR REPLACE FUNCTION update_event_participant_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        UPDATE events SET total_participants = total_participants + 1 WHERE event_id = NEW.event_id;
+    ELSIF (TG_OP = 'DELETE') THEN
+        IF OLD.removed_at IS NULL THEN
+            UPDATE events SET total_participants = total_participants - 1 WHERE event_id = OLD.event_id;
+        END IF;
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF OLD.removed_at IS NULL AND NEW.removed_at IS NOT NULL THEN
+            UPDATE events SET total_participants = total_participants - 1 WHERE event_id = NEW.event_id;
+        ELSIF OLD.removed_at IS NOT NULL AND NEW.removed_at IS NULL THEN
+            UPDATE events SET total_participants = total_participants + 1 WHERE event_id = NEW.event_id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_update_participant_stats ON participants;
+CREATE TRIGGER trg_update_participant_stats
+AFTER INSERT OR UPDATE OF removed_at OR DELETE ON participants
+FOR EACH ROW EXECUTE FUNCTION update_event_participant_stats();
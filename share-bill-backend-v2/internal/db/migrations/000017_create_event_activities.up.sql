@@ -0,0 +1,34 @@
+-- Nhat ky hoat dong cua event (chi ghi them): ai lam gi, tren doi tuong nao, du lieu truoc/sau
+CREATE TABLE IF NOT EXISTS event_activities(
+    activity_id BIGSERIAL PRIMARY KEY,
+    activity_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    event_id BIGINT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    actor_id BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_uuid UUID NOT NULL,
+    data JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_activities_event_id ON event_activities(event_id, activity_id DESC);
+CREATE INDEX IF NOT EXISTS idx_event_activities_entity ON event_activities(entity_type, entity_uuid, activity_id);
+
+-- Khong cho sua log da ghi, tru actor_id ve NULL khi xoa tai khoan (ON DELETE SET NULL)
+CREATE OR REPLACE FUNCTION prevent_event_activity_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.actor_id IS NULL
+       AND (NEW.activity_id, NEW.activity_uuid, NEW.event_id, NEW.action, NEW.entity_type, NEW.entity_uuid, NEW.created_at)
+           IS NOT DISTINCT FROM
+           (OLD.activity_id, OLD.activity_uuid, OLD.event_id, OLD.action, OLD.entity_type, OLD.entity_uuid, OLD.created_at)
+       AND NEW.data IS NOT DISTINCT FROM OLD.data THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'event_activities is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_prevent_event_activity_update
+BEFORE UPDATE ON event_activities
+FOR EACH ROW EXECUTE FUNCTION prevent_event_activity_update();
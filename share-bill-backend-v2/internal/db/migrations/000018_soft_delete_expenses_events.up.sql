@@ -0,0 +1,48 @@
+-- Xoa mem expense va event: nam trong thung rac den khi het han luu tru thi job purge xoa han
+ALTER TABLE expenses
+ADD COLUMN deleted_at TIMESTAMPTZ;
+
+ALTER TABLE events
+ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_deleted_at ON expenses(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_events_deleted_at ON events(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- total_transactions/total_expenses chi tinh expense chua bi xoa
+CREATE OR REPLACE FUNCTION update_event_expense_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        UPDATE events 
+        SET total_transactions = total_transactions + 1,
+            total_expenses = total_expenses + NEW.total_amount
+        WHERE event_id = NEW.event_id;
+        
+    ELSIF (TG_OP = 'DELETE') THEN
+        IF OLD.deleted_at IS NULL THEN
+            UPDATE events 
+            SET total_transactions = total_transactions - 1,
+                total_expenses = total_expenses - OLD.total_amount
+            WHERE event_id = OLD.event_id;
+        END IF;
+        
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            UPDATE events 
+            SET total_transactions = total_transactions - 1,
+                total_expenses = total_expenses - OLD.total_amount
+            WHERE event_id = NEW.event_id;
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            UPDATE events 
+            SET total_transactions = total_transactions + 1,
+                total_expenses = total_expenses + NEW.total_amount
+            WHERE event_id = NEW.event_id;
+        ELSIF NEW.deleted_at IS NULL AND OLD.total_amount <> NEW.total_amount THEN
+            UPDATE events 
+            SET total_expenses = total_expenses - OLD.total_amount + NEW.total_amount
+            WHERE event_id = NEW.event_id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
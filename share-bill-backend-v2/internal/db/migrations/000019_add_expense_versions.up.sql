@@ -0,0 +1,14 @@
+-- Optimistic concurrency cho expense: moi lan sua tang version, client gui If-Match de tranh ghi de
+ALTER TABLE expenses
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- Ban chup day du cua tung version (payers, shares, mon) de xem lai hoac khoi phuc
+CREATE TABLE IF NOT EXISTS expense_revisions(
+    revision_id BIGSERIAL PRIMARY KEY,
+    expense_id BIGINT NOT NULL REFERENCES expenses(expense_id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    edited_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (expense_id, version)
+);
@@ -0,0 +1,43 @@
+-- Moi expense co currency va ty gia rieng. total_amount, paid_amount, share_amount van luu theo
+-- currency cua event (da quy doi) nen balances/stats khong doi; so tien goc giu o cac cot original_*.
+-- expense_items, expense_adjustments va split_input luu theo currency cua expense.
+ALTER TABLE expenses
+ADD COLUMN currency TEXT,
+ADD COLUMN exchange_rate NUMERIC(20,10) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0),
+ADD COLUMN original_amount NUMERIC(14,2);
+
+UPDATE expenses x
+SET currency = e.currency, original_amount = x.total_amount
+FROM events e
+WHERE x.event_id = e.event_id;
+
+ALTER TABLE expenses
+ALTER COLUMN currency SET NOT NULL,
+ALTER COLUMN original_amount SET NOT NULL;
+
+ALTER TABLE expense_payers
+ADD COLUMN original_amount NUMERIC(14,2);
+
+UPDATE expense_payers SET original_amount = paid_amount;
+
+ALTER TABLE expense_payers
+ALTER COLUMN original_amount SET NOT NULL;
+
+ALTER TABLE expense_beneficiaries
+ADD COLUMN original_share NUMERIC(14,2);
+
+UPDATE expense_beneficiaries SET original_share = share_amount;
+
+ALTER TABLE expense_beneficiaries
+ALTER COLUMN original_share SET NOT NULL;
+
+-- Bang ty gia cua event: 1 don vi currency = rate don vi currency cua event
+CREATE TABLE IF NOT EXISTS event_exchange_rates (
+    event_id BIGINT NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    currency TEXT NOT NULL,
+    rate NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    source TEXT NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'file')),
+    updated_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, currency)
+);
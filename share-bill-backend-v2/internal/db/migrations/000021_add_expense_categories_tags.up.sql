@@ -0,0 +1,44 @@
+-- Danh muc chi tieu: event_id NULL la danh muc co san (dung chung moi event, co code co dinh),
+-- con lai la danh muc rieng do thanh vien tao trong event
+CREATE TABLE IF NOT EXISTS expense_categories (
+    category_id BIGSERIAL PRIMARY KEY,
+    category_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    event_id BIGINT REFERENCES events(event_id) ON DELETE CASCADE,
+    code TEXT UNIQUE,
+    name TEXT NOT NULL,
+    icon TEXT,
+    created_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((event_id IS NULL) = (code IS NOT NULL))
+);
+
+-- Ten danh muc rieng khong trung nhau trong 1 event (khong phan biet hoa thuong)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_expense_categories_event_name
+    ON expense_categories(event_id, LOWER(name)) WHERE event_id IS NOT NULL;
+
+INSERT INTO expense_categories (code, name, icon) VALUES
+    ('food', 'Food', 'utensils'),
+    ('drinks', 'Drinks', 'coffee'),
+    ('groceries', 'Groceries', 'shopping-basket'),
+    ('transport', 'Transport', 'car'),
+    ('lodging', 'Lodging', 'bed'),
+    ('tickets', 'Tickets', 'ticket'),
+    ('entertainment', 'Entertainment', 'music'),
+    ('shopping', 'Shopping', 'shopping-bag'),
+    ('other', 'Other', 'tag')
+ON CONFLICT (code) DO NOTHING;
+
+-- Xoa danh muc rieng thi expense tro ve chua phan loai
+ALTER TABLE expenses
+ADD COLUMN category_id BIGINT REFERENCES expense_categories(category_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_category_id ON expenses(category_id);
+
+-- Tag tu do, da chuan hoa ve chu thuong
+CREATE TABLE IF NOT EXISTS expense_tags (
+    expense_id BIGINT NOT NULL REFERENCES expenses(expense_id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (expense_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_tags_tag ON expense_tags(tag);
@@ -0,0 +1,15 @@
+-- Mui gio cua event, dung de hieu va hien thi ngay chi tieu (IANA, vd Asia/Ho_Chi_Minh)
+ALTER TABLE events
+ADD COLUMN timezone TEXT NOT NULL DEFAULT 'Asia/Ho_Chi_Minh';
+
+-- Thoi diem chi tieu thuc te do nguoi dung nhap; created_at chi con la thoi diem ghi vao he thong
+ALTER TABLE expenses
+ADD COLUMN spent_at TIMESTAMPTZ;
+
+UPDATE expenses SET spent_at = COALESCE(created_at, NOW());
+
+ALTER TABLE expenses
+ALTER COLUMN spent_at SET NOT NULL,
+ALTER COLUMN spent_at SET DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_expenses_event_spent_at ON expenses(event_id, spent_at DESC);
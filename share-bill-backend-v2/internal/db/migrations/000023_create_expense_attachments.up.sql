@@ -0,0 +1,17 @@
+-- File hoa don dinh kem transaction. File luu private tren storage (Cloudinary type authenticated),
+-- chi tai qua API sau khi kiem tra thanh vien event nen url khong tra ra ngoai
+CREATE TABLE IF NOT EXISTS expense_attachments (
+    attachment_id BIGSERIAL PRIMARY KEY,
+    attachment_uuid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    expense_id BIGINT NOT NULL REFERENCES expenses(expense_id) ON DELETE CASCADE,
+    uploaded_by BIGINT REFERENCES users(user_id) ON DELETE SET NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    file_url TEXT NOT NULL,
+    thumbnail_url TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_attachments_expense_id ON expense_attachments(expense_id, attachment_id);
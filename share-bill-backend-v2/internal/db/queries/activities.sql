@@ -0,0 +1,37 @@
+-- name: CreateActivity :exec
+INSERT INTO event_activities (
+    event_id, actor_id, action, entity_type, entity_uuid, data
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: GetEventActivityID :one
+-- Kiem tra con tro phan trang thuoc dung event
+SELECT activity_id FROM event_activities
+WHERE event_id = $1 AND activity_uuid = $2;
+
+-- name: ListEventActivities :many
+-- Moi nhat truoc, phan trang theo con tro: sqlc.narg('before') la activity_uuid cuoi cung cua trang truoc
+SELECT
+    a.activity_uuid, a.action, a.entity_type, a.entity_uuid, a.data, a.created_at,
+    u.user_uuid AS actor_uuid,
+    u.name AS actor_name
+FROM event_activities a
+LEFT JOIN users u ON a.actor_id = u.user_id
+WHERE a.event_id = $1
+  AND (sqlc.narg('before')::uuid IS NULL OR a.activity_id < (
+      SELECT b.activity_id FROM event_activities b WHERE b.event_id = a.event_id AND b.activity_uuid = sqlc.narg('before')::uuid
+  ))
+ORDER BY a.activity_id DESC
+LIMIT $2;
+
+-- name: ListEntityActivities :many
+-- Lich su cua 1 doi tuong (vd 1 expense), cu nhat truoc
+SELECT
+    a.activity_uuid, a.action, a.entity_type, a.entity_uuid, a.data, a.created_at,
+    u.user_uuid AS actor_uuid,
+    u.name AS actor_name
+FROM event_activities a
+LEFT JOIN users u ON a.actor_id = u.user_id
+WHERE a.event_id = $1 AND a.entity_type = $2 AND a.entity_uuid = $3
+ORDER BY a.activity_id ASC;
@@ -0,0 +1,32 @@
+-- name: CreateExpenseAttachment :one
+INSERT INTO expense_attachments (
+    expense_id, uploaded_by, file_name, content_type, size_bytes, storage_key, file_url, thumbnail_url, attachment_uuid
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, gen_random_uuid()
+) RETURNING *;
+
+-- name: ListExpenseAttachments :many
+-- Kem ten nguoi upload (NULL neu tai khoan da bi xoa)
+SELECT a.*, u.user_uuid as uploader_uuid, u.name as uploader_name
+FROM expense_attachments a
+LEFT JOIN users u ON a.uploaded_by = u.user_id
+WHERE a.expense_id = $1
+ORDER BY a.attachment_id;
+
+-- name: GetExpenseAttachmentByUUID :one
+SELECT * FROM expense_attachments WHERE attachment_uuid = $1;
+
+-- name: CountExpenseAttachments :one
+SELECT COUNT(*) FROM expense_attachments WHERE expense_id = $1;
+
+-- name: DeleteExpenseAttachment :execrows
+DELETE FROM expense_attachments WHERE attachment_id = $1;
+
+-- name: ListPurgeableAttachmentKeys :many
+-- File cua expense/event trong thung rac qua han, xoa tren storage truoc khi purge (cascade chi xoa ban ghi)
+SELECT a.storage_key
+FROM expense_attachments a
+JOIN expenses x ON a.expense_id = x.expense_id
+JOIN events e ON x.event_id = e.event_id
+WHERE (x.deleted_at IS NOT NULL AND x.deleted_at < sqlc.arg('cutoff')::timestamptz)
+   OR (e.deleted_at IS NOT NULL AND e.deleted_at < sqlc.arg('cutoff')::timestamptz);
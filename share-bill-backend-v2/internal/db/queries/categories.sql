@@ -0,0 +1,67 @@
+-- name: ListEventCategories :many
+-- Danh muc co san truoc, sau do la danh muc rieng cua event
+SELECT * FROM expense_categories
+WHERE event_id IS NULL OR event_id = $1
+ORDER BY event_id NULLS FIRST, category_id;
+
+-- name: GetCategoryByID :one
+SELECT * FROM expense_categories WHERE category_id = $1;
+
+-- name: GetCategoryByUUID :one
+SELECT * FROM expense_categories WHERE category_uuid = $1;
+
+-- name: GetBuiltinCategoryByCode :one
+SELECT * FROM expense_categories WHERE event_id IS NULL AND code = sqlc.arg('code')::text;
+
+-- name: CreateEventCategory :one
+INSERT INTO expense_categories (
+    event_id, name, icon, created_by, category_uuid
+) VALUES (
+    $1, $2, $3, $4, gen_random_uuid()
+) RETURNING *;
+
+-- name: UpdateEventCategory :one
+UPDATE expense_categories
+SET name = $2, icon = $3
+WHERE category_id = $1 AND event_id IS NOT NULL
+RETURNING *;
+
+-- name: DeleteEventCategory :execrows
+-- Chi xoa duoc danh muc rieng, expense dang dung se ve chua phan loai (ON DELETE SET NULL)
+DELETE FROM expense_categories
+WHERE category_id = $1 AND event_id IS NOT NULL;
+
+-- name: ListExpenseTags :many
+SELECT tag FROM expense_tags WHERE expense_id = $1 ORDER BY tag;
+
+-- name: CreateExpenseTag :exec
+INSERT INTO expense_tags (expense_id, tag) VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteExpenseTags :exec
+DELETE FROM expense_tags WHERE expense_id = $1;
+
+-- name: ListEventTags :many
+-- Cac tag dang dung trong event, tag dung nhieu truoc (goi y khi nhap)
+SELECT t.tag, COUNT(*) as transactions
+FROM expense_tags t
+JOIN expenses x ON t.expense_id = x.expense_id
+WHERE x.event_id = $1 AND x.deleted_at IS NULL
+GROUP BY t.tag
+ORDER BY transactions DESC, t.tag;
+
+-- name: ListEventCategoryTotals :many
+-- Tong chi theo danh muc (da quy doi sang currency cua event), category_id NULL la chua phan loai
+SELECT x.category_id, COUNT(*) as transactions, SUM(x.total_amount)::numeric as total
+FROM expenses x
+WHERE x.event_id = $1 AND x.deleted_at IS NULL
+GROUP BY x.category_id;
+
+-- name: ListParticipantCategoryShares :many
+-- Phan moi participant phai chiu theo danh muc
+SELECT eb.participant_id, x.category_id, COUNT(DISTINCT x.expense_id) as transactions, SUM(eb.share_amount)::numeric as total
+FROM expense_beneficiaries eb
+JOIN expenses x ON eb.expense_id = x.expense_id
+WHERE x.event_id = $1 AND x.deleted_at IS NULL AND eb.participant_id IS NOT NULL
+GROUP BY eb.participant_id, x.category_id
+ORDER BY eb.participant_id;
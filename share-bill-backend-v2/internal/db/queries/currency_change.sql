@@ -0,0 +1,39 @@
+-- Doi currency cua event: quy doi moi so tien dang luu theo currency cua event trong 1 transaction.
+-- So tien goc (original_*) khong doi, chi tinh lai phan da quy doi.
+
+-- name: ListEventExpensesForCurrencyChange :many
+-- Ca expense trong thung rac de khoi phuc sau nay van dung currency
+SELECT * FROM expenses WHERE event_id = $1 ORDER BY expense_id;
+
+-- name: ListExpensePayerOriginals :many
+SELECT payer_id, original_amount FROM expense_payers WHERE expense_id = $1 ORDER BY payer_id;
+
+-- name: ListExpenseBeneficiaryOriginals :many
+SELECT beneficiary_id, original_share FROM expense_beneficiaries WHERE expense_id = $1 ORDER BY beneficiary_id;
+
+-- name: SetExpensePayerAmount :exec
+UPDATE expense_payers SET paid_amount = $2 WHERE payer_id = $1;
+
+-- name: SetExpenseBeneficiaryShare :exec
+UPDATE expense_beneficiaries SET share_amount = $2 WHERE beneficiary_id = $1;
+
+-- name: ConvertExpense :one
+-- Tang version de client dang sua ban cu bi conflict
+UPDATE expenses
+SET total_amount = $2, exchange_rate = $3, version = version + 1
+WHERE expense_id = $1
+RETURNING *;
+
+-- name: ListEventSettlementAmounts :many
+SELECT settlement_id, amount FROM settlements WHERE event_id = $1 ORDER BY settlement_id;
+
+-- name: SetSettlementAmount :exec
+UPDATE settlements SET amount = $2 WHERE settlement_id = $1;
+
+-- name: ListPendingPaymentRequestAmounts :many
+SELECT payment_request_id, amount FROM payment_requests
+WHERE event_id = $1 AND status = 'pending'
+ORDER BY payment_request_id;
+
+-- name: SetPaymentRequestAmount :exec
+UPDATE payment_requests SET amount = $2, updated_at = NOW() WHERE payment_request_id = $1;
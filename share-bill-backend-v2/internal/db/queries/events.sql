@@ -1,24 +1,24 @@
 -- name: CreateEvent :one
 INSERT INTO events (
-    name, currency, description, creator_id, event_uuid, created_at, last_updated_at, is_closed
+    name, currency, description, creator_id, timezone, event_uuid, created_at, last_updated_at, is_closed
 ) VALUES (
-    $1, $2, sqlc.narg('description'), $3, gen_random_uuid(), NOW(), NOW(), FALSE
+    $1, $2, sqlc.narg('description'), $3, $4, gen_random_uuid(), NOW(), NOW(), FALSE
 ) RETURNING *;
 
 -- name: GetEventByUUID :one
 SELECT * FROM events
-WHERE event_uuid = $1 LIMIT 1;
+WHERE event_uuid = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: GetEventByID :one
 SELECT * FROM events
-WHERE event_id = $1 LIMIT 1;
+WHERE event_id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListEventsByUserID :many
 -- Lấy danh sách event mà user đã tham gia
 SELECT e.*
 FROM events e
 JOIN participants p ON e.event_id = p.event_id
-WHERE p.user_id = $1
+WHERE p.user_id = $1 AND p.removed_at IS NULL AND e.deleted_at IS NULL
 ORDER BY e.last_updated_at DESC;
 
 -- name: UpdateEvent :one
@@ -28,6 +28,7 @@ SET
     description = COALESCE(sqlc.narg('description'), description),
     status = COALESCE(sqlc.narg('status'), status),
     currency = COALESCE(sqlc.narg('currency'), currency),
+    timezone = COALESCE(sqlc.narg('timezone'), timezone),
     is_closed = COALESCE(sqlc.narg('is_closed'), is_closed),
     last_updated_at = NOW()
 WHERE event_id = $1
@@ -35,4 +36,70 @@ RETURNING *;
 
 -- name: DeleteEvent :exec
 DELETE FROM events
-WHERE event_id = $1;
\ No newline at end of file
+WHERE event_id = $1;
+-- name: SetEventClosed :one
+UPDATE events
+SET
+    is_closed = $2,
+    status = CASE WHEN $2::boolean THEN 'closed' ELSE 'active' END,
+    last_updated_at = NOW()
+WHERE event_id = $1 AND is_closed <> $2
+RETURNING *;
+
+-- name: CreateEventSnapshot :one
+INSERT INTO event_snapshots (
+    event_id, closed_by, summary
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetLatestEventSnapshot :one
+SELECT * FROM event_snapshots
+WHERE event_id = $1
+ORDER BY created_at DESC, snapshot_id DESC
+LIMIT 1;
+
+-- name: MarkEventSnapshotReopened :exec
+UPDATE event_snapshots
+SET reopened_at = NOW()
+WHERE event_id = $1 AND reopened_at IS NULL;
+
+-- name: SetEventCreator :exec
+UPDATE events
+SET creator_id = $2, last_updated_at = NOW()
+WHERE event_id = $1;
+
+-- name: ListOwnedEventsByUserID :many
+-- Cac event user dang la owner (dung khi xoa tai khoan)
+SELECT e.*
+FROM events e
+JOIN participants p ON e.event_id = p.event_id
+WHERE p.user_id = $1 AND p.role = 'owner' AND e.deleted_at IS NULL;
+
+-- name: SoftDeleteEvent :execrows
+-- Dua event vao thung rac, job purge xoa han sau khi het han luu tru
+UPDATE events
+SET deleted_at = NOW(), last_updated_at = NOW()
+WHERE event_id = $1 AND deleted_at IS NULL;
+
+-- name: GetDeletedEventByUUID :one
+SELECT * FROM events
+WHERE event_uuid = $1 AND deleted_at IS NOT NULL LIMIT 1;
+
+-- name: RestoreEvent :one
+UPDATE events
+SET deleted_at = NULL, last_updated_at = NOW()
+WHERE event_id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListDeletedEventsByOwner :many
+-- Thung rac: event da xoa ma user la owner
+SELECT e.*
+FROM events e
+JOIN participants p ON e.event_id = p.event_id
+WHERE p.user_id = $1 AND p.role = 'owner' AND e.deleted_at IS NOT NULL
+ORDER BY e.deleted_at DESC;
+
+-- name: PurgeDeletedEvents :execrows
+DELETE FROM events
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('cutoff')::timestamptz;
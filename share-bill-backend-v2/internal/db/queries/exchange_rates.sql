@@ -0,0 +1,25 @@
+-- name: UpsertEventExchangeRate :one
+INSERT INTO event_exchange_rates (
+    event_id, currency, rate, source, updated_by, updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, NOW()
+)
+ON CONFLICT (event_id, currency) DO UPDATE
+SET rate = EXCLUDED.rate,
+    source = EXCLUDED.source,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetEventExchangeRate :one
+SELECT * FROM event_exchange_rates
+WHERE event_id = $1 AND currency = $2;
+
+-- name: ListEventExchangeRates :many
+SELECT * FROM event_exchange_rates
+WHERE event_id = $1
+ORDER BY currency;
+
+-- name: DeleteEventExchangeRate :execrows
+DELETE FROM event_exchange_rates
+WHERE event_id = $1 AND currency = $2;
@@ -0,0 +1,40 @@
+-- name: CreateExpenseItem :one
+INSERT INTO expense_items (
+    expense_id, name, amount, item_uuid
+) VALUES (
+    $1, $2, $3, gen_random_uuid()
+) RETURNING *;
+
+-- name: CreateExpenseItemBeneficiary :exec
+INSERT INTO expense_item_beneficiaries (
+    item_id, participant_id, weight, share_amount
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: CreateExpenseAdjustment :exec
+INSERT INTO expense_adjustments (
+    expense_id, kind, name, percent, amount, adjustment_uuid
+) VALUES (
+    $1, $2, $3, $4, $5, gen_random_uuid()
+);
+
+-- name: GetExpenseItems :many
+SELECT * FROM expense_items WHERE expense_id = $1 ORDER BY item_id;
+
+-- name: GetExpenseItemBeneficiaries :many
+SELECT ib.item_id, ib.weight, ib.share_amount, p.participant_uuid, p.name
+FROM expense_item_beneficiaries ib
+JOIN expense_items i ON ib.item_id = i.item_id
+JOIN participants p ON ib.participant_id = p.participant_id
+WHERE i.expense_id = $1
+ORDER BY ib.item_id, p.participant_id;
+
+-- name: GetExpenseAdjustments :many
+SELECT * FROM expense_adjustments WHERE expense_id = $1 ORDER BY adjustment_id;
+
+-- name: DeleteExpenseItems :exec
+DELETE FROM expense_items WHERE expense_id = $1;
+
+-- name: DeleteExpenseAdjustments :exec
+DELETE FROM expense_adjustments WHERE expense_id = $1;
@@ -0,0 +1,18 @@
+-- name: CreateExpenseRevision :exec
+-- Expense tao truoc khi co bang nay chua co revision cho version hien tai: ghi bu, trung thi bo qua
+INSERT INTO expense_revisions (
+    expense_id, version, snapshot, edited_by
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (expense_id, version) DO NOTHING;
+
+-- name: GetExpenseRevision :one
+SELECT * FROM expense_revisions
+WHERE expense_id = $1 AND version = $2;
+
+-- name: ListExpenseRevisions :many
+SELECT r.version, r.created_at, u.user_uuid AS editor_uuid, u.name AS editor_name
+FROM expense_revisions r
+LEFT JOIN users u ON r.edited_by = u.user_id
+WHERE r.expense_id = $1
+ORDER BY r.version DESC;
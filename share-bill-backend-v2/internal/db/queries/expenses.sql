@@ -1,45 +1,53 @@
 -- name: CreateExpense :one
 INSERT INTO expenses (
-    event_id, description, total_amount, created_at, expense_uuid
+    event_id, description, total_amount, split_mode, created_by, currency, exchange_rate, original_amount, category_id, spent_at, created_at, expense_uuid
 ) VALUES (
-    $1, $2, $3, NOW(), gen_random_uuid()
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), gen_random_uuid()
 ) RETURNING *;
 
 -- name: CreateExpensePayer :exec
 INSERT INTO expense_payers (
-    expense_id, participant_id, paid_amount, payer_uuid
+    expense_id, participant_id, paid_amount, original_amount, payer_uuid
 ) VALUES (
-    $1, $2, $3, gen_random_uuid()
+    $1, $2, $3, $4, gen_random_uuid()
 );
 
 -- name: CreateExpenseBeneficiary :exec
 INSERT INTO expense_beneficiaries (
-    expense_id, participant_id, split_ratio, beneficiary_uuid
+    expense_id, participant_id, split_ratio, share_amount, split_input, original_share, beneficiary_uuid
 ) VALUES (
-    $1, $2, $3, gen_random_uuid()
+    $1, $2, $3, $4, $5, $6, gen_random_uuid()
 );
 
 -- name: GetExpenseByUUID :one
-SELECT * FROM expenses WHERE expense_uuid = $1;
+SELECT * FROM expenses WHERE expense_uuid = $1 AND deleted_at IS NULL;
 
 -- name: GetExpensePayers :many
-SELECT ep.paid_amount, p.participant_uuid, p.name
+SELECT ep.paid_amount, ep.original_amount, p.participant_uuid, p.name
 FROM expense_payers ep
 JOIN participants p ON ep.participant_id = p.participant_id
 WHERE ep.expense_id = $1;
 
 -- name: GetExpenseBeneficiaries :many
-SELECT eb.split_ratio, p.participant_uuid, p.name
+SELECT eb.split_ratio, eb.share_amount, eb.split_input, eb.original_share, p.participant_uuid, p.name
 FROM expense_beneficiaries eb
 JOIN participants p ON eb.participant_id = p.participant_id
 WHERE eb.expense_id = $1;
 
 -- name: UpdateExpense :one
+-- Chi sua khi version khop voi ban client da doc, khong khop thi khong co dong nao (pgx.ErrNoRows)
 UPDATE expenses
 SET 
     description = $2,
-    total_amount = $3
-WHERE expense_id = $1
+    total_amount = $3,
+    split_mode = $4,
+    currency = $5,
+    exchange_rate = $6,
+    original_amount = $7,
+    category_id = $8,
+    spent_at = $9,
+    version = version + 1
+WHERE expense_id = $1 AND version = sqlc.arg('expected_version')
 RETURNING *;
 
 -- name: DeleteExpensePayers :exec
@@ -52,9 +60,83 @@ DELETE FROM expense_beneficiaries WHERE expense_id = $1;
 DELETE FROM expenses WHERE expense_id = $1;
 
 -- name: ListExpensesByEventID :many
+-- Loc theo danh muc (uncategorized = chua phan loai), tag va khoang [spent_from, spent_to), bo trong thi lay tat ca
 SELECT 
-    x.expense_id, x.expense_uuid, x.description, x.total_amount, x.created_at,
-    (SELECT p.name FROM expense_payers ep JOIN participants p ON ep.participant_id = p.participant_id WHERE ep.expense_id = x.expense_id LIMIT 1) as payer_name
+    x.expense_id, x.expense_uuid, x.description, x.total_amount, x.currency, x.original_amount, x.category_id, x.spent_at, x.created_at,
+    (SELECT p.name FROM expense_payers ep JOIN participants p ON ep.participant_id = p.participant_id WHERE ep.expense_id = x.expense_id LIMIT 1) as payer_name,
+    ARRAY(SELECT t.tag FROM expense_tags t WHERE t.expense_id = x.expense_id ORDER BY t.tag)::text[] as tags
 FROM expenses x
-WHERE x.event_id = $1
-ORDER BY x.created_at DESC;
\ No newline at end of file
+WHERE x.event_id = sqlc.arg('event_id') AND x.deleted_at IS NULL
+  AND (sqlc.narg('category_id')::bigint IS NULL OR x.category_id = sqlc.narg('category_id'))
+  AND (NOT sqlc.arg('uncategorized')::boolean OR x.category_id IS NULL)
+  AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+    SELECT 1 FROM expense_tags t WHERE t.expense_id = x.expense_id AND t.tag = sqlc.narg('tag')
+  ))
+  AND (sqlc.narg('spent_from')::timestamptz IS NULL OR x.spent_at >= sqlc.narg('spent_from'))
+  AND (sqlc.narg('spent_to')::timestamptz IS NULL OR x.spent_at < sqlc.narg('spent_to'))
+ORDER BY x.spent_at DESC, x.created_at DESC;
+
+-- name: ListEventExpensePayers :many
+SELECT ep.expense_id, p.participant_uuid, ep.paid_amount
+FROM expense_payers ep
+JOIN expenses e ON ep.expense_id = e.expense_id
+JOIN participants p ON ep.participant_id = p.participant_id
+WHERE e.event_id = $1 AND e.deleted_at IS NULL
+ORDER BY ep.expense_id, ep.payer_id;
+
+-- name: ListEventExpenseBeneficiaries :many
+SELECT eb.expense_id, p.participant_uuid, eb.share_amount
+FROM expense_beneficiaries eb
+JOIN expenses e ON eb.expense_id = e.expense_id
+JOIN participants p ON eb.participant_id = p.participant_id
+WHERE e.event_id = $1 AND e.deleted_at IS NULL
+ORDER BY eb.expense_id, eb.beneficiary_id;
+
+-- name: ListParticipantExpenseEntries :many
+SELECT
+    x.expense_id, x.expense_uuid, x.description, x.total_amount, x.spent_at,
+    COALESCE((SELECT SUM(ep.paid_amount) FROM expense_payers ep WHERE ep.expense_id = x.expense_id AND ep.participant_id = $2), 0)::numeric as paid_amount,
+    COALESCE((SELECT SUM(eb.share_amount) FROM expense_beneficiaries eb WHERE eb.expense_id = x.expense_id AND eb.participant_id = $2), 0)::numeric as share_amount
+FROM expenses x
+WHERE x.event_id = $1 AND x.deleted_at IS NULL
+  AND (
+    EXISTS (SELECT 1 FROM expense_payers ep WHERE ep.expense_id = x.expense_id AND ep.participant_id = $2)
+    OR EXISTS (SELECT 1 FROM expense_beneficiaries eb WHERE eb.expense_id = x.expense_id AND eb.participant_id = $2)
+  )
+ORDER BY x.spent_at, x.expense_id;
+
+-- name: SoftDeleteExpense :execrows
+-- Dua expense vao thung rac, chi tiet payer/beneficiary giu nguyen de khoi phuc. expected_version NULL thi khong kiem tra
+UPDATE expenses SET deleted_at = NOW()
+WHERE expense_id = $1 AND deleted_at IS NULL
+  AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version'));
+
+-- name: GetDeletedExpenseByUUID :one
+SELECT * FROM expenses WHERE expense_uuid = $1 AND deleted_at IS NOT NULL;
+
+-- name: RestoreExpense :one
+UPDATE expenses SET deleted_at = NULL
+WHERE expense_id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListDeletedExpensesByEventID :many
+SELECT x.expense_uuid, x.description, x.total_amount, x.spent_at, x.deleted_at
+FROM expenses x
+WHERE x.event_id = $1 AND x.deleted_at IS NOT NULL
+ORDER BY x.deleted_at DESC;
+
+-- name: ListEventCurrencyTotals :many
+-- Tong chi theo tung currency goc va gia tri da quy doi sang currency cua event
+SELECT
+    x.currency,
+    COUNT(*) as transactions,
+    SUM(x.original_amount)::numeric as original_total,
+    SUM(x.total_amount)::numeric as converted_total
+FROM expenses x
+WHERE x.event_id = $1 AND x.deleted_at IS NULL
+GROUP BY x.currency
+ORDER BY x.currency;
+
+-- name: PurgeDeletedExpenses :execrows
+DELETE FROM expenses
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('cutoff')::timestamptz;
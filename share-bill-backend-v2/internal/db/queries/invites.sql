@@ -0,0 +1,40 @@
+-- name: CreateEventInvite :one
+INSERT INTO event_invites (
+    event_id, token, created_by, expires_at, max_uses, participant_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetEventInviteByToken :one
+SELECT * FROM event_invites
+WHERE token = $1 LIMIT 1;
+
+-- name: GetEventInviteByUUID :one
+SELECT * FROM event_invites
+WHERE invite_uuid = $1 LIMIT 1;
+
+-- name: ListEventInvites :many
+SELECT * FROM event_invites
+WHERE event_id = $1
+ORDER BY created_at DESC;
+
+-- name: RevokeEventInvite :execrows
+UPDATE event_invites
+SET revoked_at = NOW()
+WHERE invite_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeEventInvites :exec
+-- Thu hoi moi invite con hieu luc cua event (event khong con owner)
+UPDATE event_invites
+SET revoked_at = NOW()
+WHERE event_id = $1 AND revoked_at IS NULL;
+
+-- name: UseEventInvite :one
+-- Tang use_count neu invite con hieu luc, khong tra ve dong nao khi da het han/het luot/bi thu hoi
+UPDATE event_invites
+SET use_count = use_count + 1
+WHERE invite_id = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+  AND (max_uses IS NULL OR use_count < max_uses)
+RETURNING *;
@@ -0,0 +1,23 @@
+-- name: CreateOwnershipTransfer :one
+INSERT INTO event_ownership_transfers (
+    event_id, from_participant_id, to_participant_id
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetPendingOwnershipTransfer :one
+SELECT * FROM event_ownership_transfers
+WHERE event_id = $1 AND status = 'pending'
+LIMIT 1;
+
+-- name: ResolveOwnershipTransfer :one
+-- Chi cap nhat yeu cau dang cho, tranh 2 request xu ly cung luc
+UPDATE event_ownership_transfers
+SET status = $2, responded_at = NOW()
+WHERE transfer_id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: CancelPendingOwnershipTransfers :exec
+UPDATE event_ownership_transfers
+SET status = 'cancelled', responded_at = NOW()
+WHERE event_id = $1 AND status = 'pending';
@@ -0,0 +1,84 @@
+-- Gop participant sqlc.arg('source_id')::bigint (nguon) vao sqlc.arg('target_id')::bigint (dich) trong cung event.
+-- Thu tu: gop dong trung tren cung expense/mon -> xoa giao dich giua 2 nguoi -> chuyen phan con lai sang dich.
+
+-- name: MergeDuplicatePayers :exec
+-- Ca 2 cung tra 1 expense: cong paid_amount vao dong cua dich, xoa dong cua nguon
+WITH src AS (
+    DELETE FROM expense_payers s
+    USING expense_payers t
+    WHERE s.participant_id = sqlc.arg('source_id')::bigint AND t.participant_id = sqlc.arg('target_id')::bigint AND t.expense_id = s.expense_id
+    RETURNING s.expense_id, s.paid_amount, s.original_amount
+)
+UPDATE expense_payers t
+SET paid_amount = t.paid_amount + src.paid_amount,
+    original_amount = t.original_amount + src.original_amount
+FROM src
+WHERE t.expense_id = src.expense_id AND t.participant_id = sqlc.arg('target_id')::bigint;
+
+-- name: MergeDuplicateBeneficiaries :exec
+-- Ca 2 cung huong loi 1 expense: cong share/ratio/input vao dong cua dich, xoa dong cua nguon
+WITH src AS (
+    DELETE FROM expense_beneficiaries s
+    USING expense_beneficiaries t
+    WHERE s.participant_id = sqlc.arg('source_id')::bigint AND t.participant_id = sqlc.arg('target_id')::bigint AND t.expense_id = s.expense_id
+    RETURNING s.expense_id, s.split_ratio, s.share_amount, s.original_share, s.split_input
+)
+UPDATE expense_beneficiaries t
+SET
+    split_ratio = t.split_ratio + src.split_ratio,
+    share_amount = t.share_amount + src.share_amount,
+    original_share = t.original_share + src.original_share,
+    split_input = CASE
+        WHEN t.split_input IS NULL AND src.split_input IS NULL THEN NULL
+        ELSE COALESCE(t.split_input, 0) + COALESCE(src.split_input, 0)
+    END
+FROM src
+WHERE t.expense_id = src.expense_id AND t.participant_id = sqlc.arg('target_id')::bigint;
+
+-- name: MergeDuplicateItemBeneficiaries :exec
+-- Ca 2 cung an 1 mon: cong weight/share vao dong cua dich, xoa dong cua nguon
+WITH src AS (
+    DELETE FROM expense_item_beneficiaries s
+    USING expense_item_beneficiaries t
+    WHERE s.participant_id = sqlc.arg('source_id')::bigint AND t.participant_id = sqlc.arg('target_id')::bigint AND t.item_id = s.item_id
+    RETURNING s.item_id, s.weight, s.share_amount
+)
+UPDATE expense_item_beneficiaries t
+SET weight = t.weight + src.weight, share_amount = t.share_amount + src.share_amount
+FROM src
+WHERE t.item_id = src.item_id AND t.participant_id = sqlc.arg('target_id')::bigint;
+
+-- name: DeleteTransactionsBetween :exec
+-- Settlement/payment request giua 2 participant se thanh tu tra cho chinh minh sau khi gop
+WITH pr AS (
+    DELETE FROM payment_requests
+    WHERE (payer_id = sqlc.arg('source_id')::bigint AND receiver_id = sqlc.arg('target_id')::bigint) OR (payer_id = sqlc.arg('target_id')::bigint AND receiver_id = sqlc.arg('source_id')::bigint)
+)
+DELETE FROM settlements
+WHERE (payer_id = sqlc.arg('source_id')::bigint AND receiver_id = sqlc.arg('target_id')::bigint) OR (payer_id = sqlc.arg('target_id')::bigint AND receiver_id = sqlc.arg('source_id')::bigint);
+
+-- name: ReassignParticipantReferences :exec
+-- Chuyen moi dong con lai cua nguon sang dich
+WITH payers AS (
+    UPDATE expense_payers SET participant_id = sqlc.arg('target_id')::bigint WHERE participant_id = sqlc.arg('source_id')::bigint
+), beneficiaries AS (
+    UPDATE expense_beneficiaries SET participant_id = sqlc.arg('target_id')::bigint WHERE participant_id = sqlc.arg('source_id')::bigint
+), item_beneficiaries AS (
+    UPDATE expense_item_beneficiaries SET participant_id = sqlc.arg('target_id')::bigint WHERE participant_id = sqlc.arg('source_id')::bigint
+), settlements_moved AS (
+    UPDATE settlements
+    SET
+        payer_id = CASE WHEN payer_id = sqlc.arg('source_id')::bigint THEN sqlc.arg('target_id')::bigint ELSE payer_id END,
+        receiver_id = CASE WHEN receiver_id = sqlc.arg('source_id')::bigint THEN sqlc.arg('target_id')::bigint ELSE receiver_id END
+    WHERE payer_id = sqlc.arg('source_id')::bigint OR receiver_id = sqlc.arg('source_id')::bigint
+), payment_requests_moved AS (
+    UPDATE payment_requests
+    SET
+        payer_id = CASE WHEN payer_id = sqlc.arg('source_id')::bigint THEN sqlc.arg('target_id')::bigint ELSE payer_id END,
+        receiver_id = CASE WHEN receiver_id = sqlc.arg('source_id')::bigint THEN sqlc.arg('target_id')::bigint ELSE receiver_id END,
+        updated_at = NOW()
+    WHERE payer_id = sqlc.arg('source_id')::bigint OR receiver_id = sqlc.arg('source_id')::bigint
+), invites AS (
+    UPDATE event_invites SET participant_id = sqlc.arg('target_id')::bigint WHERE participant_id = sqlc.arg('source_id')::bigint
+)
+UPDATE collectors SET participant_id = sqlc.arg('target_id')::bigint WHERE participant_id = sqlc.arg('source_id')::bigint;
@@ -1,13 +1,13 @@
 -- name: AddParticipant :one
 INSERT INTO participants (
-    event_id, user_id, name, bank_name, bank_account, bank_owner
+    event_id, user_id, name, bank_name, bank_account, bank_owner, role
 ) VALUES (
-    $1, sqlc.narg('user_id'), $2, sqlc.narg('bank_name'), sqlc.narg('bank_account'), sqlc.narg('bank_owner')
+    $1, sqlc.narg('user_id'), $2, sqlc.narg('bank_name'), sqlc.narg('bank_account'), sqlc.narg('bank_owner'), $3
 ) RETURNING *;
 
 -- name: GetParticipantByEventAndUser :one
 SELECT * FROM participants
-WHERE event_id = $1 AND user_id = $2 LIMIT 1;
+WHERE event_id = $1 AND user_id = $2 AND removed_at IS NULL LIMIT 1;
 
 -- name: GetParticipantByID :one
 SELECT * FROM participants
@@ -49,18 +49,69 @@ SELECT
         COALESCE((
             SELECT SUM(ep.paid_amount)
             FROM expense_payers ep
+            JOIN expenses e ON ep.expense_id = e.expense_id
             -- JOIN bảng participants để xác định đúng user_id và event_id
             JOIN participants p_payer ON ep.participant_id = p_payer.participant_id
-            WHERE p_payer.event_id = $1 AND p_payer.user_id = $2
+            WHERE p_payer.event_id = $1 AND p_payer.user_id = $2 AND e.deleted_at IS NULL
         ), 0) 
         - 
         -- 2. Tổng tiền người này phải chịu (Owed/Benefit)
-        -- Công thức: Tổng (Giá trị Hóa đơn * Tỷ lệ chia)
+        -- Công thức: Tổng phần tiền đã chia cho người này (share_amount)
         COALESCE((
-            SELECT SUM(e.total_amount * eb.split_ratio)
+            SELECT SUM(eb.share_amount)
             FROM expense_beneficiaries eb
             JOIN expenses e ON eb.expense_id = e.expense_id
             JOIN participants p_ben ON eb.participant_id = p_ben.participant_id
-            WHERE p_ben.event_id = $1 AND p_ben.user_id = $2
+            WHERE p_ben.event_id = $1 AND p_ben.user_id = $2 AND e.deleted_at IS NULL
         ), 0)
-    )::numeric AS balance;
\ No newline at end of file
+    )::numeric AS balance;
+
+-- name: UpdateParticipantRole :one
+UPDATE participants
+SET role = $2
+WHERE participant_id = $1
+RETURNING *;
+
+-- name: GetOwnershipSuccessor :one
+-- Nguoi nhan quyen owner tu dong: thanh vien co tai khoan, role cao nhat, tham gia som nhat
+SELECT * FROM participants
+WHERE event_id = $1 AND user_id IS NOT NULL AND user_id <> $2 AND removed_at IS NULL
+ORDER BY CASE role WHEN 'admin' THEN 1 WHEN 'member' THEN 2 ELSE 3 END, joined_at, participant_id
+LIMIT 1;
+
+-- name: ClaimGuestParticipant :one
+-- Gan user vao participant khach, giu nguyen lich su payer/beneficiary/settlement. Bank info trong thi lay tu profile
+UPDATE participants
+SET
+    user_id = $2,
+    bank_name = COALESCE(bank_name, sqlc.narg('bank_name')),
+    bank_account = COALESCE(bank_account, sqlc.narg('bank_account')),
+    bank_owner = COALESCE(bank_owner, sqlc.narg('bank_owner'))
+WHERE participant_id = $1 AND user_id IS NULL AND removed_at IS NULL
+RETURNING *;
+
+-- name: SoftRemoveParticipant :one
+-- An participant khoi expense moi, giu lai lich su
+UPDATE participants
+SET removed_at = NOW()
+WHERE participant_id = $1 AND removed_at IS NULL
+RETURNING *;
+
+-- name: RestoreParticipant :one
+-- User da bi xoa tham gia lai: khoi phuc dong cu thay vi tao moi
+UPDATE participants
+SET removed_at = NULL, role = $3
+WHERE event_id = $1 AND user_id = $2 AND removed_at IS NOT NULL
+RETURNING *;
+
+-- name: CancelParticipantPaymentRequests :exec
+-- Huy cac yeu cau thanh toan dang cho cua participant bi xoa
+UPDATE payment_requests
+SET status = 'canceled', updated_at = NOW()
+WHERE status = 'pending' AND (payer_id = $1 OR receiver_id = $1);
+
+-- name: RejectParticipantSettlements :exec
+-- Tu choi cac settlement dang cho xac nhan cua participant bi xoa, tranh xac nhan sau khi so du da chot
+UPDATE settlements
+SET status = 'rejected', resolved_by = sqlc.arg('resolved_by'), resolved_at = NOW(), reject_reason = sqlc.arg('reject_reason')
+WHERE status = 'pending' AND (payer_id = sqlc.arg('participant_id')::bigint OR receiver_id = sqlc.arg('participant_id')::bigint);
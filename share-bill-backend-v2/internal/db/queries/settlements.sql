@@ -25,26 +25,58 @@ WHERE collector_id = $1;
 
 -- name: CreateSettlement :one
 INSERT INTO settlements (
-    event_id, settlement_uuid, payer_id, receiver_id, amount
+    event_id, settlement_uuid, payer_id, receiver_id, amount, created_by, reason, reversal_of,
+    status, resolved_by, resolved_at
 ) VALUES (
-    $1, gen_random_uuid(), $2, $3, $4
+    $1, gen_random_uuid(), $2, $3, $4, $5, $6, $7,
+    $8, $9, CASE WHEN $8::text = 'pending' THEN NULL ELSE NOW() END
 ) RETURNING *;
 
+-- name: ResolveSettlement :execrows
+UPDATE settlements
+SET status = $2, resolved_by = $3, resolved_at = NOW(), reject_reason = $4
+WHERE settlement_id = $1 AND status = 'pending';
+
 -- name: DeleteSettlement :exec
 DELETE FROM settlements WHERE settlement_id = $1;
 
 -- name: ListSettlementsByEvent :many
 SELECT 
-    s.settlement_id, s.settlement_uuid, s.amount, s.created_at,
+    s.settlement_id, s.settlement_uuid, s.amount, s.created_at, s.reason, s.status, s.reject_reason,
     p_payer.name as payer_name, p_payer.participant_uuid as payer_uuid,
-    p_receiver.name as receiver_name, p_receiver.participant_uuid as receiver_uuid
+    p_receiver.name as receiver_name, p_receiver.participant_uuid as receiver_uuid,
+    u.name as created_by_name,
+    orig.settlement_uuid as reversal_of_uuid,
+    rev.settlement_uuid as reversed_by_uuid
 FROM settlements s
 JOIN participants p_payer ON s.payer_id = p_payer.participant_id
 JOIN participants p_receiver ON s.receiver_id = p_receiver.participant_id
+LEFT JOIN users u ON s.created_by = u.user_id
+LEFT JOIN settlements orig ON s.reversal_of = orig.settlement_id
+LEFT JOIN settlements rev ON rev.reversal_of = s.settlement_id AND rev.status <> 'rejected'
 WHERE s.event_id = $1
-ORDER BY s.created_at DESC;
+ORDER BY s.created_at DESC, s.settlement_id DESC;
+
+-- name: GetSettlementByUUID :one
+SELECT 
+    s.settlement_id, s.settlement_uuid, s.event_id, s.payer_id, s.receiver_id, s.created_by,
+    s.amount, s.created_at, s.reason, s.status, s.reject_reason,
+    p_payer.name as payer_name, p_payer.participant_uuid as payer_uuid,
+    p_receiver.name as receiver_name, p_receiver.participant_uuid as receiver_uuid,
+    p_receiver.user_id as receiver_user_id,
+    u.name as created_by_name,
+    orig.settlement_uuid as reversal_of_uuid,
+    rev.settlement_uuid as reversed_by_uuid
+FROM settlements s
+JOIN participants p_payer ON s.payer_id = p_payer.participant_id
+JOIN participants p_receiver ON s.receiver_id = p_receiver.participant_id
+LEFT JOIN users u ON s.created_by = u.user_id
+LEFT JOIN settlements orig ON s.reversal_of = orig.settlement_id
+LEFT JOIN settlements rev ON rev.reversal_of = s.settlement_id AND rev.status <> 'rejected'
+WHERE s.settlement_uuid = $1;
 
 -- name: GetEventBalances :many
+-- paid_amount/share_amount da duoc quy doi sang currency cua event luc luu expense (xem expenses.exchange_rate)
 SELECT 
     p.participant_id,
     p.participant_uuid,
@@ -54,23 +86,23 @@ SELECT
         SELECT SUM(ep.paid_amount) 
         FROM expense_payers ep 
         JOIN expenses e ON ep.expense_id = e.expense_id 
-        WHERE ep.participant_id = p.participant_id AND e.event_id = $1
+        WHERE ep.participant_id = p.participant_id AND e.event_id = $1 AND e.deleted_at IS NULL
     ), 0)::numeric as total_paid,
     COALESCE((
-        SELECT SUM(e.total_amount * eb.split_ratio) 
+        SELECT SUM(eb.share_amount) 
         FROM expense_beneficiaries eb 
         JOIN expenses e ON eb.expense_id = e.expense_id 
-        WHERE eb.participant_id = p.participant_id AND e.event_id = $1
+        WHERE eb.participant_id = p.participant_id AND e.event_id = $1 AND e.deleted_at IS NULL
     ), 0)::numeric as total_share,
     COALESCE((
         SELECT SUM(s.amount) 
         FROM settlements s 
-        WHERE s.payer_id = p.participant_id AND s.event_id = $1
+        WHERE s.payer_id = p.participant_id AND s.event_id = $1 AND s.status = 'confirmed'
     ), 0)::numeric as total_settled_sent,
     COALESCE((
         SELECT SUM(s.amount) 
         FROM settlements s 
-        WHERE s.receiver_id = p.participant_id AND s.event_id = $1
+        WHERE s.receiver_id = p.participant_id AND s.event_id = $1 AND s.status = 'confirmed'
     ), 0)::numeric as total_settled_received
 FROM participants p
 WHERE p.event_id = $1;
\ No newline at end of file
@@ -27,4 +27,6 @@ RETURNING *;
 UPDATE users
 SET avatar = $2
 WHERE user_id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+-- name: DeleteUser :exec
+DELETE FROM users WHERE user_id = $1;
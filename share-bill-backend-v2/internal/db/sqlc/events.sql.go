@@ -7,22 +7,24 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const createEvent = `-- name: CreateEvent :one
 INSERT INTO events (
-    name, currency, description, creator_id, event_uuid, created_at, last_updated_at, is_closed
+    name, currency, description, creator_id, timezone, event_uuid, created_at, last_updated_at, is_closed
 ) VALUES (
-    $1, $2, $4, $3, gen_random_uuid(), NOW(), NOW(), FALSE
-) RETURNING event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses
+    $1, $2, $5, $3, $4, gen_random_uuid(), NOW(), NOW(), FALSE
+) RETURNING event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses, deleted_at, timezone
 `
 
 type CreateEventParams struct {
 	Name        string  `json:"name"`
 	Currency    string  `json:"currency"`
 	CreatorID   *int64  `json:"creator_id"`
+	Timezone    string  `json:"timezone"`
 	Description *string `json:"description"`
 }
 
@@ -31,6 +33,7 @@ func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event
 		arg.Name,
 		arg.Currency,
 		arg.CreatorID,
+		arg.Timezone,
 		arg.Description,
 	)
 	var i Event
@@ -48,6 +51,37 @@ func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event
 		&i.TotalParticipants,
 		&i.TotalTransactions,
 		&i.TotalExpenses,
+		&i.DeletedAt,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const createEventSnapshot = `-- name: CreateEventSnapshot :one
+INSERT INTO event_snapshots (
+    event_id, closed_by, summary
+) VALUES (
+    $1, $2, $3
+) RETURNING snapshot_id, snapshot_uuid, event_id, closed_by, summary, created_at, reopened_at
+`
+
+type CreateEventSnapshotParams struct {
+	EventID  int64  `json:"event_id"`
+	ClosedBy *int64 `json:"closed_by"`
+	Summary  []byte `json:"summary"`
+}
+
+func (q *Queries) CreateEventSnapshot(ctx context.Context, arg CreateEventSnapshotParams) (EventSnapshot, error) {
+	row := q.db.QueryRow(ctx, createEventSnapshot, arg.EventID, arg.ClosedBy, arg.Summary)
+	var i EventSnapshot
+	err := row.Scan(
+		&i.SnapshotID,
+		&i.SnapshotUuid,
+		&i.EventID,
+		&i.ClosedBy,
+		&i.Summary,
+		&i.CreatedAt,
+		&i.ReopenedAt,
 	)
 	return i, err
 }
@@ -62,9 +96,37 @@ func (q *Queries) DeleteEvent(ctx context.Context, eventID int64) error {
 	return err
 }
 
+const getDeletedEventByUUID = `-- name: GetDeletedEventByUUID :one
+SELECT event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses, deleted_at, timezone FROM events
+WHERE event_uuid = $1 AND deleted_at IS NOT NULL LIMIT 1
+`
+
+func (q *Queries) GetDeletedEventByUUID(ctx context.Context, eventUuid uuid.UUID) (Event, error) {
+	row := q.db.QueryRow(ctx, getDeletedEventByUUID, eventUuid)
+	var i Event
+	err := row.Scan(
+		&i.EventID,
+		&i.EventUuid,
+		&i.Name,
+		&i.Status,
+		&i.Description,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.LastUpdatedAt,
+		&i.CreatorID,
+		&i.IsClosed,
+		&i.TotalParticipants,
+		&i.TotalTransactions,
+		&i.TotalExpenses,
+		&i.DeletedAt,
+		&i.Timezone,
+	)
+	return i, err
+}
+
 const getEventByID = `-- name: GetEventByID :one
-SELECT event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses FROM events
-WHERE event_id = $1 LIMIT 1
+SELECT event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses, deleted_at, timezone FROM events
+WHERE event_id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetEventByID(ctx context.Context, eventID int64) (Event, error) {
@@ -84,13 +146,15 @@ func (q *Queries) GetEventByID(ctx context.Context, eventID int64) (Event, error
 		&i.TotalParticipants,
 		&i.TotalTransactions,
 		&i.TotalExpenses,
+		&i.DeletedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getEventByUUID = `-- name: GetEventByUUID :one
-SELECT event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses FROM events
-WHERE event_uuid = $1 LIMIT 1
+SELECT event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses, deleted_at, timezone FROM events
+WHERE event_uuid = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetEventByUUID(ctx context.Context, eventUuid uuid.UUID) (Event, error) {
@@ -110,15 +174,84 @@ func (q *Queries) GetEventByUUID(ctx context.Context, eventUuid uuid.UUID) (Even
 		&i.TotalParticipants,
 		&i.TotalTransactions,
 		&i.TotalExpenses,
+		&i.DeletedAt,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const getLatestEventSnapshot = `-- name: GetLatestEventSnapshot :one
+SELECT snapshot_id, snapshot_uuid, event_id, closed_by, summary, created_at, reopened_at FROM event_snapshots
+WHERE event_id = $1
+ORDER BY created_at DESC, snapshot_id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestEventSnapshot(ctx context.Context, eventID int64) (EventSnapshot, error) {
+	row := q.db.QueryRow(ctx, getLatestEventSnapshot, eventID)
+	var i EventSnapshot
+	err := row.Scan(
+		&i.SnapshotID,
+		&i.SnapshotUuid,
+		&i.EventID,
+		&i.ClosedBy,
+		&i.Summary,
+		&i.CreatedAt,
+		&i.ReopenedAt,
 	)
 	return i, err
 }
 
+const listDeletedEventsByOwner = `-- name: ListDeletedEventsByOwner :many
+SELECT e.event_id, e.event_uuid, e.name, e.status, e.description, e.currency, e.created_at, e.last_updated_at, e.creator_id, e.is_closed, e.total_participants, e.total_transactions, e.total_expenses, e.deleted_at, e.timezone
+FROM events e
+JOIN participants p ON e.event_id = p.event_id
+WHERE p.user_id = $1 AND p.role = 'owner' AND e.deleted_at IS NOT NULL
+ORDER BY e.deleted_at DESC
+`
+
+// Thung rac: event da xoa ma user la owner
+func (q *Queries) ListDeletedEventsByOwner(ctx context.Context, userID *int64) ([]Event, error) {
+	rows, err := q.db.Query(ctx, listDeletedEventsByOwner, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.EventID,
+			&i.EventUuid,
+			&i.Name,
+			&i.Status,
+			&i.Description,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.LastUpdatedAt,
+			&i.CreatorID,
+			&i.IsClosed,
+			&i.TotalParticipants,
+			&i.TotalTransactions,
+			&i.TotalExpenses,
+			&i.DeletedAt,
+			&i.Timezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEventsByUserID = `-- name: ListEventsByUserID :many
-SELECT e.event_id, e.event_uuid, e.name, e.status, e.description, e.currency, e.created_at, e.last_updated_at, e.creator_id, e.is_closed, e.total_participants, e.total_transactions, e.total_expenses
+SELECT e.event_id, e.event_uuid, e.name, e.status, e.description, e.currency, e.created_at, e.last_updated_at, e.creator_id, e.is_closed, e.total_participants, e.total_transactions, e.total_expenses, e.deleted_at, e.timezone
 FROM events e
 JOIN participants p ON e.event_id = p.event_id
-WHERE p.user_id = $1
+WHERE p.user_id = $1 AND p.removed_at IS NULL AND e.deleted_at IS NULL
 ORDER BY e.last_updated_at DESC
 `
 
@@ -146,6 +279,52 @@ func (q *Queries) ListEventsByUserID(ctx context.Context, userID *int64) ([]Even
 			&i.TotalParticipants,
 			&i.TotalTransactions,
 			&i.TotalExpenses,
+			&i.DeletedAt,
+			&i.Timezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnedEventsByUserID = `-- name: ListOwnedEventsByUserID :many
+SELECT e.event_id, e.event_uuid, e.name, e.status, e.description, e.currency, e.created_at, e.last_updated_at, e.creator_id, e.is_closed, e.total_participants, e.total_transactions, e.total_expenses, e.deleted_at, e.timezone
+FROM events e
+JOIN participants p ON e.event_id = p.event_id
+WHERE p.user_id = $1 AND p.role = 'owner' AND e.deleted_at IS NULL
+`
+
+// Cac event user dang la owner (dung khi xoa tai khoan)
+func (q *Queries) ListOwnedEventsByUserID(ctx context.Context, userID *int64) ([]Event, error) {
+	rows, err := q.db.Query(ctx, listOwnedEventsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.EventID,
+			&i.EventUuid,
+			&i.Name,
+			&i.Status,
+			&i.Description,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.LastUpdatedAt,
+			&i.CreatorID,
+			&i.IsClosed,
+			&i.TotalParticipants,
+			&i.TotalTransactions,
+			&i.TotalExpenses,
+			&i.DeletedAt,
+			&i.Timezone,
 		); err != nil {
 			return nil, err
 		}
@@ -157,6 +336,129 @@ func (q *Queries) ListEventsByUserID(ctx context.Context, userID *int64) ([]Even
 	return items, nil
 }
 
+const markEventSnapshotReopened = `-- name: MarkEventSnapshotReopened :exec
+UPDATE event_snapshots
+SET reopened_at = NOW()
+WHERE event_id = $1 AND reopened_at IS NULL
+`
+
+func (q *Queries) MarkEventSnapshotReopened(ctx context.Context, eventID int64) error {
+	_, err := q.db.Exec(ctx, markEventSnapshotReopened, eventID)
+	return err
+}
+
+const purgeDeletedEvents = `-- name: PurgeDeletedEvents :execrows
+DELETE FROM events
+WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeletedEvents(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedEvents, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreEvent = `-- name: RestoreEvent :one
+UPDATE events
+SET deleted_at = NULL, last_updated_at = NOW()
+WHERE event_id = $1 AND deleted_at IS NOT NULL
+RETURNING event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses, deleted_at, timezone
+`
+
+func (q *Queries) RestoreEvent(ctx context.Context, eventID int64) (Event, error) {
+	row := q.db.QueryRow(ctx, restoreEvent, eventID)
+	var i Event
+	err := row.Scan(
+		&i.EventID,
+		&i.EventUuid,
+		&i.Name,
+		&i.Status,
+		&i.Description,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.LastUpdatedAt,
+		&i.CreatorID,
+		&i.IsClosed,
+		&i.TotalParticipants,
+		&i.TotalTransactions,
+		&i.TotalExpenses,
+		&i.DeletedAt,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const setEventClosed = `-- name: SetEventClosed :one
+UPDATE events
+SET
+    is_closed = $2,
+    status = CASE WHEN $2::boolean THEN 'closed' ELSE 'active' END,
+    last_updated_at = NOW()
+WHERE event_id = $1 AND is_closed <> $2
+RETURNING event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses, deleted_at, timezone
+`
+
+type SetEventClosedParams struct {
+	EventID  int64 `json:"event_id"`
+	IsClosed bool  `json:"is_closed"`
+}
+
+func (q *Queries) SetEventClosed(ctx context.Context, arg SetEventClosedParams) (Event, error) {
+	row := q.db.QueryRow(ctx, setEventClosed, arg.EventID, arg.IsClosed)
+	var i Event
+	err := row.Scan(
+		&i.EventID,
+		&i.EventUuid,
+		&i.Name,
+		&i.Status,
+		&i.Description,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.LastUpdatedAt,
+		&i.CreatorID,
+		&i.IsClosed,
+		&i.TotalParticipants,
+		&i.TotalTransactions,
+		&i.TotalExpenses,
+		&i.DeletedAt,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const setEventCreator = `-- name: SetEventCreator :exec
+UPDATE events
+SET creator_id = $2, last_updated_at = NOW()
+WHERE event_id = $1
+`
+
+type SetEventCreatorParams struct {
+	EventID   int64  `json:"event_id"`
+	CreatorID *int64 `json:"creator_id"`
+}
+
+func (q *Queries) SetEventCreator(ctx context.Context, arg SetEventCreatorParams) error {
+	_, err := q.db.Exec(ctx, setEventCreator, arg.EventID, arg.CreatorID)
+	return err
+}
+
+const softDeleteEvent = `-- name: SoftDeleteEvent :execrows
+UPDATE events
+SET deleted_at = NOW(), last_updated_at = NOW()
+WHERE event_id = $1 AND deleted_at IS NULL
+`
+
+// Dua event vao thung rac, job purge xoa han sau khi het han luu tru
+func (q *Queries) SoftDeleteEvent(ctx context.Context, eventID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteEvent, eventID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateEvent = `-- name: UpdateEvent :one
 UPDATE events
 SET 
@@ -164,10 +466,11 @@ SET
     description = COALESCE($3, description),
     status = COALESCE($4, status),
     currency = COALESCE($5, currency),
-    is_closed = COALESCE($6, is_closed),
+    timezone = COALESCE($6, timezone),
+    is_closed = COALESCE($7, is_closed),
     last_updated_at = NOW()
 WHERE event_id = $1
-RETURNING event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses
+RETURNING event_id, event_uuid, name, status, description, currency, created_at, last_updated_at, creator_id, is_closed, total_participants, total_transactions, total_expenses, deleted_at, timezone
 `
 
 type UpdateEventParams struct {
@@ -176,6 +479,7 @@ type UpdateEventParams struct {
 	Description *string `json:"description"`
 	Status      *string `json:"status"`
 	Currency    *string `json:"currency"`
+	Timezone    *string `json:"timezone"`
 	IsClosed    *bool   `json:"is_closed"`
 }
 
@@ -186,6 +490,7 @@ func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event
 		arg.Description,
 		arg.Status,
 		arg.Currency,
+		arg.Timezone,
 		arg.IsClosed,
 	)
 	var i Event
@@ -203,6 +508,8 @@ func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event
 		&i.TotalParticipants,
 		&i.TotalTransactions,
 		&i.TotalExpenses,
+		&i.DeletedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
@@ -7,27 +7,47 @@ package database
 
 import (
 	"context"
+	"time"
 
+	"BACKEND/internal/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createExpense = `-- name: CreateExpense :one
 INSERT INTO expenses (
-    event_id, description, total_amount, created_at, expense_uuid
+    event_id, description, total_amount, split_mode, created_by, currency, exchange_rate, original_amount, category_id, spent_at, created_at, expense_uuid
 ) VALUES (
-    $1, $2, $3, NOW(), gen_random_uuid()
-) RETURNING expense_id, expense_uuid, event_id, description, total_amount, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), gen_random_uuid()
+) RETURNING expense_id, expense_uuid, event_id, description, total_amount, created_at, split_mode, created_by, deleted_at, version, currency, exchange_rate, original_amount, category_id, spent_at
 `
 
 type CreateExpenseParams struct {
-	EventID     int64          `json:"event_id"`
-	Description string         `json:"description"`
-	TotalAmount pgtype.Numeric `json:"total_amount"`
+	EventID        int64          `json:"event_id"`
+	Description    string         `json:"description"`
+	TotalAmount    money.Amount   `json:"total_amount"`
+	SplitMode      string         `json:"split_mode"`
+	CreatedBy      *int64         `json:"created_by"`
+	Currency       string         `json:"currency"`
+	ExchangeRate   pgtype.Numeric `json:"exchange_rate"`
+	OriginalAmount money.Amount   `json:"original_amount"`
+	CategoryID     *int64         `json:"category_id"`
+	SpentAt        time.Time      `json:"spent_at"`
 }
 
 func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error) {
-	row := q.db.QueryRow(ctx, createExpense, arg.EventID, arg.Description, arg.TotalAmount)
+	row := q.db.QueryRow(ctx, createExpense,
+		arg.EventID,
+		arg.Description,
+		arg.TotalAmount,
+		arg.SplitMode,
+		arg.CreatedBy,
+		arg.Currency,
+		arg.ExchangeRate,
+		arg.OriginalAmount,
+		arg.CategoryID,
+		arg.SpentAt,
+	)
 	var i Expense
 	err := row.Scan(
 		&i.ExpenseID,
@@ -36,15 +56,24 @@ func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (E
 		&i.Description,
 		&i.TotalAmount,
 		&i.CreatedAt,
+		&i.SplitMode,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.OriginalAmount,
+		&i.CategoryID,
+		&i.SpentAt,
 	)
 	return i, err
 }
 
 const createExpenseBeneficiary = `-- name: CreateExpenseBeneficiary :exec
 INSERT INTO expense_beneficiaries (
-    expense_id, participant_id, split_ratio, beneficiary_uuid
+    expense_id, participant_id, split_ratio, share_amount, split_input, original_share, beneficiary_uuid
 ) VALUES (
-    $1, $2, $3, gen_random_uuid()
+    $1, $2, $3, $4, $5, $6, gen_random_uuid()
 )
 `
 
@@ -52,29 +81,45 @@ type CreateExpenseBeneficiaryParams struct {
 	ExpenseID     *int64         `json:"expense_id"`
 	ParticipantID *int64         `json:"participant_id"`
 	SplitRatio    pgtype.Numeric `json:"split_ratio"`
+	ShareAmount   money.Amount   `json:"share_amount"`
+	SplitInput    pgtype.Numeric `json:"split_input"`
+	OriginalShare money.Amount   `json:"original_share"`
 }
 
 func (q *Queries) CreateExpenseBeneficiary(ctx context.Context, arg CreateExpenseBeneficiaryParams) error {
-	_, err := q.db.Exec(ctx, createExpenseBeneficiary, arg.ExpenseID, arg.ParticipantID, arg.SplitRatio)
+	_, err := q.db.Exec(ctx, createExpenseBeneficiary,
+		arg.ExpenseID,
+		arg.ParticipantID,
+		arg.SplitRatio,
+		arg.ShareAmount,
+		arg.SplitInput,
+		arg.OriginalShare,
+	)
 	return err
 }
 
 const createExpensePayer = `-- name: CreateExpensePayer :exec
 INSERT INTO expense_payers (
-    expense_id, participant_id, paid_amount, payer_uuid
+    expense_id, participant_id, paid_amount, original_amount, payer_uuid
 ) VALUES (
-    $1, $2, $3, gen_random_uuid()
+    $1, $2, $3, $4, gen_random_uuid()
 )
 `
 
 type CreateExpensePayerParams struct {
-	ExpenseID     int64          `json:"expense_id"`
-	ParticipantID *int64         `json:"participant_id"`
-	PaidAmount    pgtype.Numeric `json:"paid_amount"`
+	ExpenseID      int64        `json:"expense_id"`
+	ParticipantID  *int64       `json:"participant_id"`
+	PaidAmount     money.Amount `json:"paid_amount"`
+	OriginalAmount money.Amount `json:"original_amount"`
 }
 
 func (q *Queries) CreateExpensePayer(ctx context.Context, arg CreateExpensePayerParams) error {
-	_, err := q.db.Exec(ctx, createExpensePayer, arg.ExpenseID, arg.ParticipantID, arg.PaidAmount)
+	_, err := q.db.Exec(ctx, createExpensePayer,
+		arg.ExpenseID,
+		arg.ParticipantID,
+		arg.PaidAmount,
+		arg.OriginalAmount,
+	)
 	return err
 }
 
@@ -105,8 +150,35 @@ func (q *Queries) DeleteExpensePayers(ctx context.Context, expenseID int64) erro
 	return err
 }
 
+const getDeletedExpenseByUUID = `-- name: GetDeletedExpenseByUUID :one
+SELECT expense_id, expense_uuid, event_id, description, total_amount, created_at, split_mode, created_by, deleted_at, version, currency, exchange_rate, original_amount, category_id, spent_at FROM expenses WHERE expense_uuid = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeletedExpenseByUUID(ctx context.Context, expenseUuid uuid.UUID) (Expense, error) {
+	row := q.db.QueryRow(ctx, getDeletedExpenseByUUID, expenseUuid)
+	var i Expense
+	err := row.Scan(
+		&i.ExpenseID,
+		&i.ExpenseUuid,
+		&i.EventID,
+		&i.Description,
+		&i.TotalAmount,
+		&i.CreatedAt,
+		&i.SplitMode,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.OriginalAmount,
+		&i.CategoryID,
+		&i.SpentAt,
+	)
+	return i, err
+}
+
 const getExpenseBeneficiaries = `-- name: GetExpenseBeneficiaries :many
-SELECT eb.split_ratio, p.participant_uuid, p.name
+SELECT eb.split_ratio, eb.share_amount, eb.split_input, eb.original_share, p.participant_uuid, p.name
 FROM expense_beneficiaries eb
 JOIN participants p ON eb.participant_id = p.participant_id
 WHERE eb.expense_id = $1
@@ -114,6 +186,9 @@ WHERE eb.expense_id = $1
 
 type GetExpenseBeneficiariesRow struct {
 	SplitRatio      pgtype.Numeric `json:"split_ratio"`
+	ShareAmount     money.Amount   `json:"share_amount"`
+	SplitInput      pgtype.Numeric `json:"split_input"`
+	OriginalShare   money.Amount   `json:"original_share"`
 	ParticipantUuid uuid.UUID      `json:"participant_uuid"`
 	Name            string         `json:"name"`
 }
@@ -127,7 +202,14 @@ func (q *Queries) GetExpenseBeneficiaries(ctx context.Context, expenseID *int64)
 	var items []GetExpenseBeneficiariesRow
 	for rows.Next() {
 		var i GetExpenseBeneficiariesRow
-		if err := rows.Scan(&i.SplitRatio, &i.ParticipantUuid, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.SplitRatio,
+			&i.ShareAmount,
+			&i.SplitInput,
+			&i.OriginalShare,
+			&i.ParticipantUuid,
+			&i.Name,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -139,7 +221,7 @@ func (q *Queries) GetExpenseBeneficiaries(ctx context.Context, expenseID *int64)
 }
 
 const getExpenseByUUID = `-- name: GetExpenseByUUID :one
-SELECT expense_id, expense_uuid, event_id, description, total_amount, created_at FROM expenses WHERE expense_uuid = $1
+SELECT expense_id, expense_uuid, event_id, description, total_amount, created_at, split_mode, created_by, deleted_at, version, currency, exchange_rate, original_amount, category_id, spent_at FROM expenses WHERE expense_uuid = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetExpenseByUUID(ctx context.Context, expenseUuid uuid.UUID) (Expense, error) {
@@ -152,21 +234,31 @@ func (q *Queries) GetExpenseByUUID(ctx context.Context, expenseUuid uuid.UUID) (
 		&i.Description,
 		&i.TotalAmount,
 		&i.CreatedAt,
+		&i.SplitMode,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.OriginalAmount,
+		&i.CategoryID,
+		&i.SpentAt,
 	)
 	return i, err
 }
 
 const getExpensePayers = `-- name: GetExpensePayers :many
-SELECT ep.paid_amount, p.participant_uuid, p.name
+SELECT ep.paid_amount, ep.original_amount, p.participant_uuid, p.name
 FROM expense_payers ep
 JOIN participants p ON ep.participant_id = p.participant_id
 WHERE ep.expense_id = $1
 `
 
 type GetExpensePayersRow struct {
-	PaidAmount      pgtype.Numeric `json:"paid_amount"`
-	ParticipantUuid uuid.UUID      `json:"participant_uuid"`
-	Name            string         `json:"name"`
+	PaidAmount      money.Amount `json:"paid_amount"`
+	OriginalAmount  money.Amount `json:"original_amount"`
+	ParticipantUuid uuid.UUID    `json:"participant_uuid"`
+	Name            string       `json:"name"`
 }
 
 func (q *Queries) GetExpensePayers(ctx context.Context, expenseID int64) ([]GetExpensePayersRow, error) {
@@ -178,7 +270,176 @@ func (q *Queries) GetExpensePayers(ctx context.Context, expenseID int64) ([]GetE
 	var items []GetExpensePayersRow
 	for rows.Next() {
 		var i GetExpensePayersRow
-		if err := rows.Scan(&i.PaidAmount, &i.ParticipantUuid, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.PaidAmount,
+			&i.OriginalAmount,
+			&i.ParticipantUuid,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedExpensesByEventID = `-- name: ListDeletedExpensesByEventID :many
+SELECT x.expense_uuid, x.description, x.total_amount, x.spent_at, x.deleted_at
+FROM expenses x
+WHERE x.event_id = $1 AND x.deleted_at IS NOT NULL
+ORDER BY x.deleted_at DESC
+`
+
+type ListDeletedExpensesByEventIDRow struct {
+	ExpenseUuid uuid.UUID          `json:"expense_uuid"`
+	Description string             `json:"description"`
+	TotalAmount money.Amount       `json:"total_amount"`
+	SpentAt     time.Time          `json:"spent_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) ListDeletedExpensesByEventID(ctx context.Context, eventID int64) ([]ListDeletedExpensesByEventIDRow, error) {
+	rows, err := q.db.Query(ctx, listDeletedExpensesByEventID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeletedExpensesByEventIDRow
+	for rows.Next() {
+		var i ListDeletedExpensesByEventIDRow
+		if err := rows.Scan(
+			&i.ExpenseUuid,
+			&i.Description,
+			&i.TotalAmount,
+			&i.SpentAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventCurrencyTotals = `-- name: ListEventCurrencyTotals :many
+SELECT
+    x.currency,
+    COUNT(*) as transactions,
+    SUM(x.original_amount)::numeric as original_total,
+    SUM(x.total_amount)::numeric as converted_total
+FROM expenses x
+WHERE x.event_id = $1 AND x.deleted_at IS NULL
+GROUP BY x.currency
+ORDER BY x.currency
+`
+
+type ListEventCurrencyTotalsRow struct {
+	Currency       string       `json:"currency"`
+	Transactions   int64        `json:"transactions"`
+	OriginalTotal  money.Amount `json:"original_total"`
+	ConvertedTotal money.Amount `json:"converted_total"`
+}
+
+// Tong chi theo tung currency goc va gia tri da quy doi sang currency cua event
+func (q *Queries) ListEventCurrencyTotals(ctx context.Context, eventID int64) ([]ListEventCurrencyTotalsRow, error) {
+	rows, err := q.db.Query(ctx, listEventCurrencyTotals, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventCurrencyTotalsRow
+	for rows.Next() {
+		var i ListEventCurrencyTotalsRow
+		if err := rows.Scan(
+			&i.Currency,
+			&i.Transactions,
+			&i.OriginalTotal,
+			&i.ConvertedTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventExpenseBeneficiaries = `-- name: ListEventExpenseBeneficiaries :many
+SELECT eb.expense_id, p.participant_uuid, eb.share_amount
+FROM expense_beneficiaries eb
+JOIN expenses e ON eb.expense_id = e.expense_id
+JOIN participants p ON eb.participant_id = p.participant_id
+WHERE e.event_id = $1 AND e.deleted_at IS NULL
+ORDER BY eb.expense_id, eb.beneficiary_id
+`
+
+type ListEventExpenseBeneficiariesRow struct {
+	ExpenseID       *int64       `json:"expense_id"`
+	ParticipantUuid uuid.UUID    `json:"participant_uuid"`
+	ShareAmount     money.Amount `json:"share_amount"`
+}
+
+func (q *Queries) ListEventExpenseBeneficiaries(ctx context.Context, eventID int64) ([]ListEventExpenseBeneficiariesRow, error) {
+	rows, err := q.db.Query(ctx, listEventExpenseBeneficiaries, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventExpenseBeneficiariesRow
+	for rows.Next() {
+		var i ListEventExpenseBeneficiariesRow
+		if err := rows.Scan(
+			&i.ExpenseID,
+			&i.ParticipantUuid,
+			&i.ShareAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventExpensePayers = `-- name: ListEventExpensePayers :many
+SELECT ep.expense_id, p.participant_uuid, ep.paid_amount
+FROM expense_payers ep
+JOIN expenses e ON ep.expense_id = e.expense_id
+JOIN participants p ON ep.participant_id = p.participant_id
+WHERE e.event_id = $1 AND e.deleted_at IS NULL
+ORDER BY ep.expense_id, ep.payer_id
+`
+
+type ListEventExpensePayersRow struct {
+	ExpenseID       int64        `json:"expense_id"`
+	ParticipantUuid uuid.UUID    `json:"participant_uuid"`
+	PaidAmount      money.Amount `json:"paid_amount"`
+}
+
+func (q *Queries) ListEventExpensePayers(ctx context.Context, eventID int64) ([]ListEventExpensePayersRow, error) {
+	rows, err := q.db.Query(ctx, listEventExpensePayers, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventExpensePayersRow
+	for rows.Next() {
+		var i ListEventExpensePayersRow
+		if err := rows.Scan(
+			&i.ExpenseID,
+			&i.ParticipantUuid,
+			&i.PaidAmount,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -191,24 +452,54 @@ func (q *Queries) GetExpensePayers(ctx context.Context, expenseID int64) ([]GetE
 
 const listExpensesByEventID = `-- name: ListExpensesByEventID :many
 SELECT 
-    x.expense_id, x.expense_uuid, x.description, x.total_amount, x.created_at,
-    (SELECT p.name FROM expense_payers ep JOIN participants p ON ep.participant_id = p.participant_id WHERE ep.expense_id = x.expense_id LIMIT 1) as payer_name
+    x.expense_id, x.expense_uuid, x.description, x.total_amount, x.currency, x.original_amount, x.category_id, x.spent_at, x.created_at,
+    (SELECT p.name FROM expense_payers ep JOIN participants p ON ep.participant_id = p.participant_id WHERE ep.expense_id = x.expense_id LIMIT 1) as payer_name,
+    ARRAY(SELECT t.tag FROM expense_tags t WHERE t.expense_id = x.expense_id ORDER BY t.tag)::text[] as tags
 FROM expenses x
-WHERE x.event_id = $1
-ORDER BY x.created_at DESC
+WHERE x.event_id = $1 AND x.deleted_at IS NULL
+  AND ($2::bigint IS NULL OR x.category_id = $2)
+  AND (NOT $3::boolean OR x.category_id IS NULL)
+  AND ($4::text IS NULL OR EXISTS (
+    SELECT 1 FROM expense_tags t WHERE t.expense_id = x.expense_id AND t.tag = $4
+  ))
+  AND ($5::timestamptz IS NULL OR x.spent_at >= $5)
+  AND ($6::timestamptz IS NULL OR x.spent_at < $6)
+ORDER BY x.spent_at DESC, x.created_at DESC
 `
 
+type ListExpensesByEventIDParams struct {
+	EventID       int64              `json:"event_id"`
+	CategoryID    *int64             `json:"category_id"`
+	Uncategorized bool               `json:"uncategorized"`
+	Tag           *string            `json:"tag"`
+	SpentFrom     pgtype.Timestamptz `json:"spent_from"`
+	SpentTo       pgtype.Timestamptz `json:"spent_to"`
+}
+
 type ListExpensesByEventIDRow struct {
-	ExpenseID   int64              `json:"expense_id"`
-	ExpenseUuid uuid.UUID          `json:"expense_uuid"`
-	Description string             `json:"description"`
-	TotalAmount pgtype.Numeric     `json:"total_amount"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	PayerName   string             `json:"payer_name"`
+	ExpenseID      int64              `json:"expense_id"`
+	ExpenseUuid    uuid.UUID          `json:"expense_uuid"`
+	Description    string             `json:"description"`
+	TotalAmount    money.Amount       `json:"total_amount"`
+	Currency       string             `json:"currency"`
+	OriginalAmount money.Amount       `json:"original_amount"`
+	CategoryID     *int64             `json:"category_id"`
+	SpentAt        time.Time          `json:"spent_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	PayerName      string             `json:"payer_name"`
+	Tags           []string           `json:"tags"`
 }
 
-func (q *Queries) ListExpensesByEventID(ctx context.Context, eventID int64) ([]ListExpensesByEventIDRow, error) {
-	rows, err := q.db.Query(ctx, listExpensesByEventID, eventID)
+// Loc theo danh muc (uncategorized = chua phan loai), tag va khoang [spent_from, spent_to), bo trong thi lay tat ca
+func (q *Queries) ListExpensesByEventID(ctx context.Context, arg ListExpensesByEventIDParams) ([]ListExpensesByEventIDRow, error) {
+	rows, err := q.db.Query(ctx, listExpensesByEventID,
+		arg.EventID,
+		arg.CategoryID,
+		arg.Uncategorized,
+		arg.Tag,
+		arg.SpentFrom,
+		arg.SpentTo,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -221,8 +512,13 @@ func (q *Queries) ListExpensesByEventID(ctx context.Context, eventID int64) ([]L
 			&i.ExpenseUuid,
 			&i.Description,
 			&i.TotalAmount,
+			&i.Currency,
+			&i.OriginalAmount,
+			&i.CategoryID,
+			&i.SpentAt,
 			&i.CreatedAt,
 			&i.PayerName,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -234,23 +530,168 @@ func (q *Queries) ListExpensesByEventID(ctx context.Context, eventID int64) ([]L
 	return items, nil
 }
 
+const listParticipantExpenseEntries = `-- name: ListParticipantExpenseEntries :many
+SELECT
+    x.expense_id, x.expense_uuid, x.description, x.total_amount, x.spent_at,
+    COALESCE((SELECT SUM(ep.paid_amount) FROM expense_payers ep WHERE ep.expense_id = x.expense_id AND ep.participant_id = $2), 0)::numeric as paid_amount,
+    COALESCE((SELECT SUM(eb.share_amount) FROM expense_beneficiaries eb WHERE eb.expense_id = x.expense_id AND eb.participant_id = $2), 0)::numeric as share_amount
+FROM expenses x
+WHERE x.event_id = $1 AND x.deleted_at IS NULL
+  AND (
+    EXISTS (SELECT 1 FROM expense_payers ep WHERE ep.expense_id = x.expense_id AND ep.participant_id = $2)
+    OR EXISTS (SELECT 1 FROM expense_beneficiaries eb WHERE eb.expense_id = x.expense_id AND eb.participant_id = $2)
+  )
+ORDER BY x.spent_at, x.expense_id
+`
+
+type ListParticipantExpenseEntriesParams struct {
+	EventID       int64  `json:"event_id"`
+	ParticipantID *int64 `json:"participant_id"`
+}
+
+type ListParticipantExpenseEntriesRow struct {
+	ExpenseID   int64              `json:"expense_id"`
+	ExpenseUuid uuid.UUID          `json:"expense_uuid"`
+	Description string             `json:"description"`
+	TotalAmount money.Amount       `json:"total_amount"`
+	SpentAt     time.Time          `json:"spent_at"`
+	PaidAmount  money.Amount       `json:"paid_amount"`
+	ShareAmount money.Amount       `json:"share_amount"`
+}
+
+func (q *Queries) ListParticipantExpenseEntries(ctx context.Context, arg ListParticipantExpenseEntriesParams) ([]ListParticipantExpenseEntriesRow, error) {
+	rows, err := q.db.Query(ctx, listParticipantExpenseEntries, arg.EventID, arg.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListParticipantExpenseEntriesRow
+	for rows.Next() {
+		var i ListParticipantExpenseEntriesRow
+		if err := rows.Scan(
+			&i.ExpenseID,
+			&i.ExpenseUuid,
+			&i.Description,
+			&i.TotalAmount,
+			&i.SpentAt,
+			&i.PaidAmount,
+			&i.ShareAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedExpenses = `-- name: PurgeDeletedExpenses :execrows
+DELETE FROM expenses
+WHERE deleted_at IS NOT NULL AND deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeletedExpenses(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedExpenses, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreExpense = `-- name: RestoreExpense :one
+UPDATE expenses SET deleted_at = NULL
+WHERE expense_id = $1 AND deleted_at IS NOT NULL
+RETURNING expense_id, expense_uuid, event_id, description, total_amount, created_at, split_mode, created_by, deleted_at, version, currency, exchange_rate, original_amount, category_id, spent_at
+`
+
+func (q *Queries) RestoreExpense(ctx context.Context, expenseID int64) (Expense, error) {
+	row := q.db.QueryRow(ctx, restoreExpense, expenseID)
+	var i Expense
+	err := row.Scan(
+		&i.ExpenseID,
+		&i.ExpenseUuid,
+		&i.EventID,
+		&i.Description,
+		&i.TotalAmount,
+		&i.CreatedAt,
+		&i.SplitMode,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.OriginalAmount,
+		&i.CategoryID,
+		&i.SpentAt,
+	)
+	return i, err
+}
+
+const softDeleteExpense = `-- name: SoftDeleteExpense :execrows
+UPDATE expenses SET deleted_at = NOW()
+WHERE expense_id = $1 AND deleted_at IS NULL
+  AND ($2::int IS NULL OR version = $2)
+`
+
+type SoftDeleteExpenseParams struct {
+	ExpenseID       int64  `json:"expense_id"`
+	ExpectedVersion *int32 `json:"expected_version"`
+}
+
+// Dua expense vao thung rac, chi tiet payer/beneficiary giu nguyen de khoi phuc. expected_version NULL thi khong kiem tra
+func (q *Queries) SoftDeleteExpense(ctx context.Context, arg SoftDeleteExpenseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteExpense, arg.ExpenseID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateExpense = `-- name: UpdateExpense :one
 UPDATE expenses
 SET 
     description = $2,
-    total_amount = $3
-WHERE expense_id = $1
-RETURNING expense_id, expense_uuid, event_id, description, total_amount, created_at
+    total_amount = $3,
+    split_mode = $4,
+    currency = $5,
+    exchange_rate = $6,
+    original_amount = $7,
+    category_id = $8,
+    spent_at = $9,
+    version = version + 1
+WHERE expense_id = $1 AND version = $10
+RETURNING expense_id, expense_uuid, event_id, description, total_amount, created_at, split_mode, created_by, deleted_at, version, currency, exchange_rate, original_amount, category_id, spent_at
 `
 
 type UpdateExpenseParams struct {
-	ExpenseID   int64          `json:"expense_id"`
-	Description string         `json:"description"`
-	TotalAmount pgtype.Numeric `json:"total_amount"`
+	ExpenseID       int64          `json:"expense_id"`
+	Description     string         `json:"description"`
+	TotalAmount     money.Amount   `json:"total_amount"`
+	SplitMode       string         `json:"split_mode"`
+	Currency        string         `json:"currency"`
+	ExchangeRate    pgtype.Numeric `json:"exchange_rate"`
+	OriginalAmount  money.Amount   `json:"original_amount"`
+	CategoryID      *int64         `json:"category_id"`
+	SpentAt         time.Time      `json:"spent_at"`
+	ExpectedVersion int32          `json:"expected_version"`
 }
 
+// Chi sua khi version khop voi ban client da doc, khong khop thi khong co dong nao (pgx.ErrNoRows)
 func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (Expense, error) {
-	row := q.db.QueryRow(ctx, updateExpense, arg.ExpenseID, arg.Description, arg.TotalAmount)
+	row := q.db.QueryRow(ctx, updateExpense,
+		arg.ExpenseID,
+		arg.Description,
+		arg.TotalAmount,
+		arg.SplitMode,
+		arg.Currency,
+		arg.ExchangeRate,
+		arg.OriginalAmount,
+		arg.CategoryID,
+		arg.SpentAt,
+		arg.ExpectedVersion,
+	)
 	var i Expense
 	err := row.Scan(
 		&i.ExpenseID,
@@ -259,6 +700,15 @@ func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (E
 		&i.Description,
 		&i.TotalAmount,
 		&i.CreatedAt,
+		&i.SplitMode,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.OriginalAmount,
+		&i.CategoryID,
+		&i.SpentAt,
 	)
 	return i, err
 }
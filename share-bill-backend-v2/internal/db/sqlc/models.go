@@ -7,6 +7,7 @@ package database
 import (
 	"time"
 
+	"BACKEND/internal/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -37,16 +38,107 @@ type Event struct {
 	IsClosed          bool               `json:"is_closed"`
 	TotalParticipants int32              `json:"total_participants"`
 	TotalTransactions int32              `json:"total_transactions"`
-	TotalExpenses     pgtype.Numeric     `json:"total_expenses"`
+	TotalExpenses     money.Amount       `json:"total_expenses"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	Timezone          string             `json:"timezone"`
+}
+
+type EventActivity struct {
+	ActivityID   int64     `json:"activity_id"`
+	ActivityUuid uuid.UUID `json:"activity_uuid"`
+	EventID      int64     `json:"event_id"`
+	ActorID      *int64    `json:"actor_id"`
+	Action       string    `json:"action"`
+	EntityType   string    `json:"entity_type"`
+	EntityUuid   uuid.UUID `json:"entity_uuid"`
+	Data         []byte    `json:"data"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type EventExchangeRate struct {
+	EventID   int64          `json:"event_id"`
+	Currency  string         `json:"currency"`
+	Rate      pgtype.Numeric `json:"rate"`
+	Source    string         `json:"source"`
+	UpdatedBy *int64         `json:"updated_by"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type EventInvite struct {
+	InviteID      int64              `json:"invite_id"`
+	InviteUuid    uuid.UUID          `json:"invite_uuid"`
+	EventID       int64              `json:"event_id"`
+	Token         string             `json:"token"`
+	CreatedBy     *int64             `json:"created_by"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	MaxUses       *int32             `json:"max_uses"`
+	UseCount      int32              `json:"use_count"`
+	RevokedAt     pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+	ParticipantID *int64             `json:"participant_id"`
+}
+
+type EventOwnershipTransfer struct {
+	TransferID        int64              `json:"transfer_id"`
+	TransferUuid      uuid.UUID          `json:"transfer_uuid"`
+	EventID           int64              `json:"event_id"`
+	FromParticipantID int64              `json:"from_participant_id"`
+	ToParticipantID   int64              `json:"to_participant_id"`
+	Status            string             `json:"status"`
+	CreatedAt         time.Time          `json:"created_at"`
+	RespondedAt       pgtype.Timestamptz `json:"responded_at"`
+}
+
+type EventSnapshot struct {
+	SnapshotID   int64              `json:"snapshot_id"`
+	SnapshotUuid uuid.UUID          `json:"snapshot_uuid"`
+	EventID      int64              `json:"event_id"`
+	ClosedBy     *int64             `json:"closed_by"`
+	Summary      []byte             `json:"summary"`
+	CreatedAt    time.Time          `json:"created_at"`
+	ReopenedAt   pgtype.Timestamptz `json:"reopened_at"`
 }
 
 type Expense struct {
-	ExpenseID   int64              `json:"expense_id"`
-	ExpenseUuid uuid.UUID          `json:"expense_uuid"`
-	EventID     int64              `json:"event_id"`
-	Description string             `json:"description"`
-	TotalAmount pgtype.Numeric     `json:"total_amount"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ExpenseID      int64              `json:"expense_id"`
+	ExpenseUuid    uuid.UUID          `json:"expense_uuid"`
+	EventID        int64              `json:"event_id"`
+	Description    string             `json:"description"`
+	TotalAmount    money.Amount       `json:"total_amount"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	SplitMode      string             `json:"split_mode"`
+	CreatedBy      *int64             `json:"created_by"`
+	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+	Version        int32              `json:"version"`
+	Currency       string             `json:"currency"`
+	ExchangeRate   pgtype.Numeric     `json:"exchange_rate"`
+	OriginalAmount money.Amount       `json:"original_amount"`
+	CategoryID     *int64             `json:"category_id"`
+	SpentAt        time.Time          `json:"spent_at"`
+}
+
+type ExpenseAdjustment struct {
+	AdjustmentID   int64          `json:"adjustment_id"`
+	AdjustmentUuid uuid.UUID      `json:"adjustment_uuid"`
+	ExpenseID      int64          `json:"expense_id"`
+	Kind           string         `json:"kind"`
+	Name           string         `json:"name"`
+	Percent        pgtype.Numeric `json:"percent"`
+	Amount         money.Amount   `json:"amount"`
+}
+
+type ExpenseAttachment struct {
+	AttachmentID   int64     `json:"attachment_id"`
+	AttachmentUuid uuid.UUID `json:"attachment_uuid"`
+	ExpenseID      int64     `json:"expense_id"`
+	UploadedBy     *int64    `json:"uploaded_by"`
+	FileName       string    `json:"file_name"`
+	ContentType    string    `json:"content_type"`
+	SizeBytes      int64     `json:"size_bytes"`
+	StorageKey     string    `json:"storage_key"`
+	FileUrl        string    `json:"file_url"`
+	ThumbnailUrl   *string   `json:"thumbnail_url"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 type ExpenseBeneficiary struct {
@@ -55,14 +147,58 @@ type ExpenseBeneficiary struct {
 	ExpenseID       *int64         `json:"expense_id"`
 	ParticipantID   *int64         `json:"participant_id"`
 	SplitRatio      pgtype.Numeric `json:"split_ratio"`
+	ShareAmount     money.Amount   `json:"share_amount"`
+	SplitInput      pgtype.Numeric `json:"split_input"`
+	OriginalShare   money.Amount   `json:"original_share"`
+}
+
+type ExpenseCategory struct {
+	CategoryID   int64     `json:"category_id"`
+	CategoryUuid uuid.UUID `json:"category_uuid"`
+	EventID      *int64    `json:"event_id"`
+	Code         *string   `json:"code"`
+	Name         string    `json:"name"`
+	Icon         *string   `json:"icon"`
+	CreatedBy    *int64    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ExpenseItem struct {
+	ItemID    int64        `json:"item_id"`
+	ItemUuid  uuid.UUID    `json:"item_uuid"`
+	ExpenseID int64        `json:"expense_id"`
+	Name      string       `json:"name"`
+	Amount    money.Amount `json:"amount"`
+}
+
+type ExpenseItemBeneficiary struct {
+	ItemID        int64          `json:"item_id"`
+	ParticipantID int64          `json:"participant_id"`
+	Weight        pgtype.Numeric `json:"weight"`
+	ShareAmount   money.Amount   `json:"share_amount"`
 }
 
 type ExpensePayer struct {
-	PayerID       int64          `json:"payer_id"`
-	PayerUuid     uuid.UUID      `json:"payer_uuid"`
-	ExpenseID     int64          `json:"expense_id"`
-	ParticipantID *int64         `json:"participant_id"`
-	PaidAmount    pgtype.Numeric `json:"paid_amount"`
+	PayerID        int64        `json:"payer_id"`
+	PayerUuid      uuid.UUID    `json:"payer_uuid"`
+	ExpenseID      int64        `json:"expense_id"`
+	ParticipantID  *int64       `json:"participant_id"`
+	PaidAmount     money.Amount `json:"paid_amount"`
+	OriginalAmount money.Amount `json:"original_amount"`
+}
+
+type ExpenseRevision struct {
+	RevisionID int64     `json:"revision_id"`
+	ExpenseID  int64     `json:"expense_id"`
+	Version    int32     `json:"version"`
+	Snapshot   []byte    `json:"snapshot"`
+	EditedBy   *int64    `json:"edited_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ExpenseTag struct {
+	ExpenseID int64  `json:"expense_id"`
+	Tag       string `json:"tag"`
 }
 
 type Participant struct {
@@ -75,16 +211,25 @@ type Participant struct {
 	BankAccount     *string            `json:"bank_account"`
 	BankOwner       *string            `json:"bank_owner"`
 	JoinedAt        pgtype.Timestamptz `json:"joined_at"`
+	Role            string             `json:"role"`
+	RemovedAt       pgtype.Timestamptz `json:"removed_at"`
 }
 
 type Settlement struct {
 	SettlementID   int64              `json:"settlement_id"`
 	SettlementUuid uuid.UUID          `json:"settlement_uuid"`
 	EventID        int64              `json:"event_id"`
-	Amount         pgtype.Numeric     `json:"amount"`
+	Amount         money.Amount       `json:"amount"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	PayerID        *int64             `json:"payer_id"`
 	ReceiverID     *int64             `json:"receiver_id"`
+	CreatedBy      *int64             `json:"created_by"`
+	Reason         *string            `json:"reason"`
+	ReversalOf     *int64             `json:"reversal_of"`
+	Status         string             `json:"status"`
+	ResolvedBy     *int64             `json:"resolved_by"`
+	ResolvedAt     pgtype.Timestamptz `json:"resolved_at"`
+	RejectReason   *string            `json:"reject_reason"`
 }
 
 type User struct {
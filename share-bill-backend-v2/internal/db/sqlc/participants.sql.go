@@ -8,21 +8,23 @@ package database
 import (
 	"context"
 
+	"BACKEND/internal/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addParticipant = `-- name: AddParticipant :one
 INSERT INTO participants (
-    event_id, user_id, name, bank_name, bank_account, bank_owner
+    event_id, user_id, name, bank_name, bank_account, bank_owner, role
 ) VALUES (
-    $1, $3, $2, $4, $5, $6
-) RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at
+    $1, $4, $2, $5, $6, $7, $3
+) RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role, removed_at
 `
 
 type AddParticipantParams struct {
 	EventID     int64   `json:"event_id"`
 	Name        string  `json:"name"`
+	Role        string  `json:"role"`
 	UserID      *int64  `json:"user_id"`
 	BankName    *string `json:"bank_name"`
 	BankAccount *string `json:"bank_account"`
@@ -33,6 +35,7 @@ func (q *Queries) AddParticipant(ctx context.Context, arg AddParticipantParams)
 	row := q.db.QueryRow(ctx, addParticipant,
 		arg.EventID,
 		arg.Name,
+		arg.Role,
 		arg.UserID,
 		arg.BankName,
 		arg.BankAccount,
@@ -49,6 +52,97 @@ func (q *Queries) AddParticipant(ctx context.Context, arg AddParticipantParams)
 		&i.BankAccount,
 		&i.BankOwner,
 		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
+	)
+	return i, err
+}
+
+const cancelParticipantPaymentRequests = `-- name: CancelParticipantPaymentRequests :exec
+UPDATE payment_requests
+SET status = 'canceled', updated_at = NOW()
+WHERE status = 'pending' AND (payer_id = $1 OR receiver_id = $1)
+`
+
+// Huy cac yeu cau thanh toan dang cho cua participant bi xoa
+func (q *Queries) CancelParticipantPaymentRequests(ctx context.Context, payerID int64) error {
+	_, err := q.db.Exec(ctx, cancelParticipantPaymentRequests, payerID)
+	return err
+}
+
+const claimGuestParticipant = `-- name: ClaimGuestParticipant :one
+UPDATE participants
+SET
+    user_id = $2,
+    bank_name = COALESCE(bank_name, $3),
+    bank_account = COALESCE(bank_account, $4),
+    bank_owner = COALESCE(bank_owner, $5)
+WHERE participant_id = $1 AND user_id IS NULL AND removed_at IS NULL
+RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role, removed_at
+`
+
+type ClaimGuestParticipantParams struct {
+	ParticipantID int64   `json:"participant_id"`
+	UserID        *int64  `json:"user_id"`
+	BankName      *string `json:"bank_name"`
+	BankAccount   *string `json:"bank_account"`
+	BankOwner     *string `json:"bank_owner"`
+}
+
+// Gan user vao participant khach, giu nguyen lich su payer/beneficiary/settlement. Bank info trong thi lay tu profile
+func (q *Queries) ClaimGuestParticipant(ctx context.Context, arg ClaimGuestParticipantParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, claimGuestParticipant,
+		arg.ParticipantID,
+		arg.UserID,
+		arg.BankName,
+		arg.BankAccount,
+		arg.BankOwner,
+	)
+	var i Participant
+	err := row.Scan(
+		&i.ParticipantID,
+		&i.ParticipantUuid,
+		&i.EventID,
+		&i.UserID,
+		&i.Name,
+		&i.BankName,
+		&i.BankAccount,
+		&i.BankOwner,
+		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
+	)
+	return i, err
+}
+
+const getOwnershipSuccessor = `-- name: GetOwnershipSuccessor :one
+SELECT participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role FROM participants
+WHERE event_id = $1 AND user_id IS NOT NULL AND user_id <> $2 AND removed_at IS NULL
+ORDER BY CASE role WHEN 'admin' THEN 1 WHEN 'member' THEN 2 ELSE 3 END, joined_at, participant_id
+LIMIT 1
+`
+
+type GetOwnershipSuccessorParams struct {
+	EventID int64  `json:"event_id"`
+	UserID  *int64 `json:"user_id"`
+}
+
+// Nguoi nhan quyen owner tu dong: thanh vien co tai khoan, role cao nhat, tham gia som nhat
+func (q *Queries) GetOwnershipSuccessor(ctx context.Context, arg GetOwnershipSuccessorParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, getOwnershipSuccessor, arg.EventID, arg.UserID)
+	var i Participant
+	err := row.Scan(
+		&i.ParticipantID,
+		&i.ParticipantUuid,
+		&i.EventID,
+		&i.UserID,
+		&i.Name,
+		&i.BankName,
+		&i.BankAccount,
+		&i.BankOwner,
+		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
@@ -60,19 +154,20 @@ SELECT
         COALESCE((
             SELECT SUM(ep.paid_amount)
             FROM expense_payers ep
+            JOIN expenses e ON ep.expense_id = e.expense_id
             -- JOIN bảng participants để xác định đúng user_id và event_id
             JOIN participants p_payer ON ep.participant_id = p_payer.participant_id
-            WHERE p_payer.event_id = $1 AND p_payer.user_id = $2
+            WHERE p_payer.event_id = $1 AND p_payer.user_id = $2 AND e.deleted_at IS NULL
         ), 0) 
         - 
         -- 2. Tổng tiền người này phải chịu (Owed/Benefit)
-        -- Công thức: Tổng (Giá trị Hóa đơn * Tỷ lệ chia)
+        -- Công thức: Tổng phần tiền đã chia cho người này (share_amount)
         COALESCE((
-            SELECT SUM(e.total_amount * eb.split_ratio)
+            SELECT SUM(eb.share_amount)
             FROM expense_beneficiaries eb
             JOIN expenses e ON eb.expense_id = e.expense_id
             JOIN participants p_ben ON eb.participant_id = p_ben.participant_id
-            WHERE p_ben.event_id = $1 AND p_ben.user_id = $2
+            WHERE p_ben.event_id = $1 AND p_ben.user_id = $2 AND e.deleted_at IS NULL
         ), 0)
     )::numeric AS balance
 `
@@ -82,16 +177,16 @@ type GetParticipantBalanceParams struct {
 	UserID  *int64 `json:"user_id"`
 }
 
-func (q *Queries) GetParticipantBalance(ctx context.Context, arg GetParticipantBalanceParams) (pgtype.Numeric, error) {
+func (q *Queries) GetParticipantBalance(ctx context.Context, arg GetParticipantBalanceParams) (money.Amount, error) {
 	row := q.db.QueryRow(ctx, getParticipantBalance, arg.EventID, arg.UserID)
-	var balance pgtype.Numeric
+	var balance money.Amount
 	err := row.Scan(&balance)
 	return balance, err
 }
 
 const getParticipantByEventAndUser = `-- name: GetParticipantByEventAndUser :one
-SELECT participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at FROM participants
-WHERE event_id = $1 AND user_id = $2 LIMIT 1
+SELECT participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role FROM participants
+WHERE event_id = $1 AND user_id = $2 AND removed_at IS NULL LIMIT 1
 `
 
 type GetParticipantByEventAndUserParams struct {
@@ -112,12 +207,14 @@ func (q *Queries) GetParticipantByEventAndUser(ctx context.Context, arg GetParti
 		&i.BankAccount,
 		&i.BankOwner,
 		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
 
 const getParticipantByID = `-- name: GetParticipantByID :one
-SELECT participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at FROM participants
+SELECT participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role FROM participants
 WHERE participant_id = $1 LIMIT 1
 `
 
@@ -134,12 +231,14 @@ func (q *Queries) GetParticipantByID(ctx context.Context, participantID int64) (
 		&i.BankAccount,
 		&i.BankOwner,
 		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
 
 const getParticipantByUUID = `-- name: GetParticipantByUUID :one
-SELECT participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at FROM participants WHERE participant_uuid = $1 LIMIT 1
+SELECT participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role FROM participants WHERE participant_uuid = $1 LIMIT 1
 `
 
 func (q *Queries) GetParticipantByUUID(ctx context.Context, participantUuid uuid.UUID) (Participant, error) {
@@ -155,13 +254,15 @@ func (q *Queries) GetParticipantByUUID(ctx context.Context, participantUuid uuid
 		&i.BankAccount,
 		&i.BankOwner,
 		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
 
 const listParticipantsByEventID = `-- name: ListParticipantsByEventID :many
 SELECT 
-    p.participant_id, p.participant_uuid, p.event_id, p.user_id, p.name, p.bank_name, p.bank_account, p.bank_owner, p.joined_at,
+    p.participant_id, p.participant_uuid, p.event_id, p.user_id, p.name, p.bank_name, p.bank_account, p.bank_owner, p.joined_at, p.role, p.removed_at,
     u.user_uuid as user_global_uuid,
     u.email as user_email
 FROM participants p
@@ -180,6 +281,8 @@ type ListParticipantsByEventIDRow struct {
 	BankAccount     *string            `json:"bank_account"`
 	BankOwner       *string            `json:"bank_owner"`
 	JoinedAt        pgtype.Timestamptz `json:"joined_at"`
+	Role            string             `json:"role"`
+	RemovedAt       pgtype.Timestamptz `json:"removed_at"`
 	UserGlobalUuid  pgtype.UUID        `json:"user_global_uuid"`
 	UserEmail       *string            `json:"user_email"`
 }
@@ -203,6 +306,8 @@ func (q *Queries) ListParticipantsByEventID(ctx context.Context, eventID int64)
 			&i.BankAccount,
 			&i.BankOwner,
 			&i.JoinedAt,
+			&i.Role,
+			&i.RemovedAt,
 			&i.UserGlobalUuid,
 			&i.UserEmail,
 		); err != nil {
@@ -216,6 +321,24 @@ func (q *Queries) ListParticipantsByEventID(ctx context.Context, eventID int64)
 	return items, nil
 }
 
+const rejectParticipantSettlements = `-- name: RejectParticipantSettlements :exec
+UPDATE settlements
+SET status = 'rejected', resolved_by = $1, resolved_at = NOW(), reject_reason = $2
+WHERE status = 'pending' AND (payer_id = $3::bigint OR receiver_id = $3::bigint)
+`
+
+type RejectParticipantSettlementsParams struct {
+	ResolvedBy    *int64  `json:"resolved_by"`
+	RejectReason  *string `json:"reject_reason"`
+	ParticipantID int64   `json:"participant_id"`
+}
+
+// Tu choi cac settlement dang cho xac nhan cua participant bi xoa, tranh xac nhan sau khi so du da chot
+func (q *Queries) RejectParticipantSettlements(ctx context.Context, arg RejectParticipantSettlementsParams) error {
+	_, err := q.db.Exec(ctx, rejectParticipantSettlements, arg.ResolvedBy, arg.RejectReason, arg.ParticipantID)
+	return err
+}
+
 const removeParticipant = `-- name: RemoveParticipant :exec
 DELETE FROM participants WHERE event_id = $1 AND user_id = $2
 `
@@ -239,6 +362,66 @@ func (q *Queries) RemoveParticipantByID(ctx context.Context, participantID int64
 	return err
 }
 
+const restoreParticipant = `-- name: RestoreParticipant :one
+UPDATE participants
+SET removed_at = NULL, role = $3
+WHERE event_id = $1 AND user_id = $2 AND removed_at IS NOT NULL
+RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role, removed_at
+`
+
+type RestoreParticipantParams struct {
+	EventID int64  `json:"event_id"`
+	UserID  *int64 `json:"user_id"`
+	Role    string `json:"role"`
+}
+
+// User da bi xoa tham gia lai: khoi phuc dong cu thay vi tao moi
+func (q *Queries) RestoreParticipant(ctx context.Context, arg RestoreParticipantParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, restoreParticipant, arg.EventID, arg.UserID, arg.Role)
+	var i Participant
+	err := row.Scan(
+		&i.ParticipantID,
+		&i.ParticipantUuid,
+		&i.EventID,
+		&i.UserID,
+		&i.Name,
+		&i.BankName,
+		&i.BankAccount,
+		&i.BankOwner,
+		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
+	)
+	return i, err
+}
+
+const softRemoveParticipant = `-- name: SoftRemoveParticipant :one
+UPDATE participants
+SET removed_at = NOW()
+WHERE participant_id = $1 AND removed_at IS NULL
+RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role, removed_at
+`
+
+// An participant khoi expense moi, giu lai lich su
+func (q *Queries) SoftRemoveParticipant(ctx context.Context, participantID int64) (Participant, error) {
+	row := q.db.QueryRow(ctx, softRemoveParticipant, participantID)
+	var i Participant
+	err := row.Scan(
+		&i.ParticipantID,
+		&i.ParticipantUuid,
+		&i.EventID,
+		&i.UserID,
+		&i.Name,
+		&i.BankName,
+		&i.BankAccount,
+		&i.BankOwner,
+		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
+	)
+	return i, err
+}
+
 const updateParticipant = `-- name: UpdateParticipant :one
 UPDATE participants
 SET 
@@ -247,7 +430,7 @@ SET
     bank_account = COALESCE($4, bank_account),
     bank_owner = COALESCE($5, bank_owner)
 WHERE participant_id = $1
-RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at
+RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role, removed_at
 `
 
 type UpdateParticipantParams struct {
@@ -277,6 +460,39 @@ func (q *Queries) UpdateParticipant(ctx context.Context, arg UpdateParticipantPa
 		&i.BankAccount,
 		&i.BankOwner,
 		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
+	)
+	return i, err
+}
+
+const updateParticipantRole = `-- name: UpdateParticipantRole :one
+UPDATE participants
+SET role = $2
+WHERE participant_id = $1
+RETURNING participant_id, participant_uuid, event_id, user_id, name, bank_name, bank_account, bank_owner, joined_at, role, removed_at
+`
+
+type UpdateParticipantRoleParams struct {
+	ParticipantID int64  `json:"participant_id"`
+	Role          string `json:"role"`
+}
+
+func (q *Queries) UpdateParticipantRole(ctx context.Context, arg UpdateParticipantRoleParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, updateParticipantRole, arg.ParticipantID, arg.Role)
+	var i Participant
+	err := row.Scan(
+		&i.ParticipantID,
+		&i.ParticipantUuid,
+		&i.EventID,
+		&i.UserID,
+		&i.Name,
+		&i.BankName,
+		&i.BankAccount,
+		&i.BankOwner,
+		&i.JoinedAt,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
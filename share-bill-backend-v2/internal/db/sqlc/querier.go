@@ -6,54 +6,184 @@ package database
 
 import (
 	"context"
+	"time"
 
+	"BACKEND/internal/money"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddParticipant(ctx context.Context, arg AddParticipantParams) (Participant, error)
+	// Huy cac yeu cau thanh toan dang cho cua participant bi xoa
+	CancelParticipantPaymentRequests(ctx context.Context, payerID int64) error
+	CancelPendingOwnershipTransfers(ctx context.Context, eventID int64) error
+	// Gan user vao participant khach, giu nguyen lich su payer/beneficiary/settlement. Bank info trong thi lay tu profile
+	ClaimGuestParticipant(ctx context.Context, arg ClaimGuestParticipantParams) (Participant, error)
+	// Tang version de client dang sua ban cu bi conflict
+	ConvertExpense(ctx context.Context, arg ConvertExpenseParams) (Expense, error)
+	CountExpenseAttachments(ctx context.Context, expenseID int64) (int64, error)
+	CreateActivity(ctx context.Context, arg CreateActivityParams) error
 	CreateCollector(ctx context.Context, arg CreateCollectorParams) (Collector, error)
 	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	CreateEventCategory(ctx context.Context, arg CreateEventCategoryParams) (ExpenseCategory, error)
+	CreateEventInvite(ctx context.Context, arg CreateEventInviteParams) (EventInvite, error)
+	CreateEventSnapshot(ctx context.Context, arg CreateEventSnapshotParams) (EventSnapshot, error)
 	CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error)
+	CreateExpenseAdjustment(ctx context.Context, arg CreateExpenseAdjustmentParams) error
+	CreateExpenseAttachment(ctx context.Context, arg CreateExpenseAttachmentParams) (ExpenseAttachment, error)
 	CreateExpenseBeneficiary(ctx context.Context, arg CreateExpenseBeneficiaryParams) error
+	CreateExpenseItem(ctx context.Context, arg CreateExpenseItemParams) (ExpenseItem, error)
+	CreateExpenseItemBeneficiary(ctx context.Context, arg CreateExpenseItemBeneficiaryParams) error
 	CreateExpensePayer(ctx context.Context, arg CreateExpensePayerParams) error
+	// Expense tao truoc khi co bang nay chua co revision cho version hien tai: ghi bu, trung thi bo qua
+	CreateExpenseRevision(ctx context.Context, arg CreateExpenseRevisionParams) error
+	CreateExpenseTag(ctx context.Context, arg CreateExpenseTagParams) error
+	CreateOwnershipTransfer(ctx context.Context, arg CreateOwnershipTransferParams) (EventOwnershipTransfer, error)
 	CreateSettlement(ctx context.Context, arg CreateSettlementParams) (Settlement, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
 	DeactivateCollector(ctx context.Context, collectorID int64) error
 	DeleteEvent(ctx context.Context, eventID int64) error
+	// Chi xoa duoc danh muc rieng, expense dang dung se ve chua phan loai (ON DELETE SET NULL)
+	DeleteEventCategory(ctx context.Context, categoryID int64) (int64, error)
+	DeleteEventExchangeRate(ctx context.Context, arg DeleteEventExchangeRateParams) (int64, error)
 	DeleteExpense(ctx context.Context, expenseID int64) error
+	DeleteExpenseAdjustments(ctx context.Context, expenseID int64) error
+	DeleteExpenseAttachment(ctx context.Context, attachmentID int64) (int64, error)
 	DeleteExpenseBeneficiaries(ctx context.Context, expenseID *int64) error
+	DeleteExpenseItems(ctx context.Context, expenseID int64) error
 	DeleteExpensePayers(ctx context.Context, expenseID int64) error
+	DeleteExpenseTags(ctx context.Context, expenseID int64) error
 	DeleteSettlement(ctx context.Context, settlementID int64) error
+	// Settlement/payment request giua 2 participant se thanh tu tra cho chinh minh sau khi gop
+	DeleteTransactionsBetween(ctx context.Context, arg DeleteTransactionsBetweenParams) error
+	DeleteUser(ctx context.Context, userID int64) error
 	GetActiveCollectorByEventID(ctx context.Context, eventID int64) (GetActiveCollectorByEventIDRow, error)
+	GetBuiltinCategoryByCode(ctx context.Context, code string) (ExpenseCategory, error)
+	GetCategoryByID(ctx context.Context, categoryID int64) (ExpenseCategory, error)
+	GetCategoryByUUID(ctx context.Context, categoryUuid uuid.UUID) (ExpenseCategory, error)
+	GetDeletedEventByUUID(ctx context.Context, eventUuid uuid.UUID) (Event, error)
+	GetDeletedExpenseByUUID(ctx context.Context, expenseUuid uuid.UUID) (Expense, error)
+	// Kiem tra con tro phan trang thuoc dung event
+	GetEventActivityID(ctx context.Context, arg GetEventActivityIDParams) (int64, error)
+	// paid_amount/share_amount da duoc quy doi sang currency cua event luc luu expense (xem expenses.exchange_rate)
 	GetEventBalances(ctx context.Context, eventID int64) ([]GetEventBalancesRow, error)
 	GetEventByID(ctx context.Context, eventID int64) (Event, error)
 	GetEventByUUID(ctx context.Context, eventUuid uuid.UUID) (Event, error)
+	GetEventExchangeRate(ctx context.Context, arg GetEventExchangeRateParams) (EventExchangeRate, error)
+	GetEventInviteByToken(ctx context.Context, token string) (EventInvite, error)
+	GetEventInviteByUUID(ctx context.Context, inviteUuid uuid.UUID) (EventInvite, error)
+	GetExpenseAdjustments(ctx context.Context, expenseID int64) ([]ExpenseAdjustment, error)
+	GetExpenseAttachmentByUUID(ctx context.Context, attachmentUuid uuid.UUID) (ExpenseAttachment, error)
 	GetExpenseBeneficiaries(ctx context.Context, expenseID *int64) ([]GetExpenseBeneficiariesRow, error)
 	GetExpenseByUUID(ctx context.Context, expenseUuid uuid.UUID) (Expense, error)
+	GetExpenseItemBeneficiaries(ctx context.Context, expenseID int64) ([]GetExpenseItemBeneficiariesRow, error)
+	GetExpenseItems(ctx context.Context, expenseID int64) ([]ExpenseItem, error)
 	GetExpensePayers(ctx context.Context, expenseID int64) ([]GetExpensePayersRow, error)
-	GetParticipantBalance(ctx context.Context, arg GetParticipantBalanceParams) (pgtype.Numeric, error)
+	GetExpenseRevision(ctx context.Context, arg GetExpenseRevisionParams) (ExpenseRevision, error)
+	GetLatestEventSnapshot(ctx context.Context, eventID int64) (EventSnapshot, error)
+	// Nguoi nhan quyen owner tu dong: thanh vien co tai khoan, role cao nhat, tham gia som nhat
+	GetOwnershipSuccessor(ctx context.Context, arg GetOwnershipSuccessorParams) (Participant, error)
+	GetParticipantBalance(ctx context.Context, arg GetParticipantBalanceParams) (money.Amount, error)
 	GetParticipantByEventAndUser(ctx context.Context, arg GetParticipantByEventAndUserParams) (Participant, error)
 	GetParticipantByID(ctx context.Context, participantID int64) (Participant, error)
 	GetParticipantByUUID(ctx context.Context, participantUuid uuid.UUID) (Participant, error)
+	GetPendingOwnershipTransfer(ctx context.Context, eventID int64) (EventOwnershipTransfer, error)
+	GetSettlementByUUID(ctx context.Context, settlementUuid uuid.UUID) (GetSettlementByUUIDRow, error)
 	GetUserByEmail(ctx context.Context, email *string) (User, error)
 	GetUserByID(ctx context.Context, userID int64) (User, error)
 	GetValidVerifyEmail(ctx context.Context, email string) (VerifyEmail, error)
+	// Thung rac: event da xoa ma user la owner
+	ListDeletedEventsByOwner(ctx context.Context, userID *int64) ([]Event, error)
+	ListDeletedExpensesByEventID(ctx context.Context, eventID int64) ([]ListDeletedExpensesByEventIDRow, error)
+	// Lich su cua 1 doi tuong (vd 1 expense), cu nhat truoc
+	ListEntityActivities(ctx context.Context, arg ListEntityActivitiesParams) ([]ListEntityActivitiesRow, error)
+	// Moi nhat truoc, phan trang theo con tro: sqlc.narg('before') la activity_uuid cuoi cung cua trang truoc
+	ListEventActivities(ctx context.Context, arg ListEventActivitiesParams) ([]ListEventActivitiesRow, error)
+	// Danh muc co san truoc, sau do la danh muc rieng cua event
+	ListEventCategories(ctx context.Context, eventID *int64) ([]ExpenseCategory, error)
+	// Tong chi theo danh muc (da quy doi sang currency cua event), category_id NULL la chua phan loai
+	ListEventCategoryTotals(ctx context.Context, eventID int64) ([]ListEventCategoryTotalsRow, error)
+	// Tong chi theo tung currency goc va gia tri da quy doi sang currency cua event
+	ListEventCurrencyTotals(ctx context.Context, eventID int64) ([]ListEventCurrencyTotalsRow, error)
+	ListEventExchangeRates(ctx context.Context, eventID int64) ([]EventExchangeRate, error)
+	ListEventExpenseBeneficiaries(ctx context.Context, eventID int64) ([]ListEventExpenseBeneficiariesRow, error)
+	ListEventExpensePayers(ctx context.Context, eventID int64) ([]ListEventExpensePayersRow, error)
+	// Ca expense trong thung rac de khoi phuc sau nay van dung currency
+	ListEventExpensesForCurrencyChange(ctx context.Context, eventID int64) ([]Expense, error)
+	ListEventInvites(ctx context.Context, eventID int64) ([]EventInvite, error)
+	ListEventSettlementAmounts(ctx context.Context, eventID int64) ([]ListEventSettlementAmountsRow, error)
+	// Cac tag dang dung trong event, tag dung nhieu truoc (goi y khi nhap)
+	ListEventTags(ctx context.Context, eventID int64) ([]ListEventTagsRow, error)
 	// Lấy danh sách event mà user đã tham gia
 	ListEventsByUserID(ctx context.Context, userID *int64) ([]Event, error)
-	ListExpensesByEventID(ctx context.Context, eventID int64) ([]ListExpensesByEventIDRow, error)
+	// Kem ten nguoi upload (NULL neu tai khoan da bi xoa)
+	ListExpenseAttachments(ctx context.Context, expenseID int64) ([]ListExpenseAttachmentsRow, error)
+	ListExpenseBeneficiaryOriginals(ctx context.Context, expenseID *int64) ([]ListExpenseBeneficiaryOriginalsRow, error)
+	ListExpensePayerOriginals(ctx context.Context, expenseID int64) ([]ListExpensePayerOriginalsRow, error)
+	ListExpenseRevisions(ctx context.Context, expenseID int64) ([]ListExpenseRevisionsRow, error)
+	ListExpenseTags(ctx context.Context, expenseID int64) ([]string, error)
+	// Loc theo danh muc (uncategorized = chua phan loai), tag va khoang [spent_from, spent_to), bo trong thi lay tat ca
+	ListExpensesByEventID(ctx context.Context, arg ListExpensesByEventIDParams) ([]ListExpensesByEventIDRow, error)
+	// Cac event user dang la owner (dung khi xoa tai khoan)
+	ListOwnedEventsByUserID(ctx context.Context, userID *int64) ([]Event, error)
+	// Phan moi participant phai chiu theo danh muc
+	ListParticipantCategoryShares(ctx context.Context, eventID int64) ([]ListParticipantCategorySharesRow, error)
+	ListParticipantExpenseEntries(ctx context.Context, arg ListParticipantExpenseEntriesParams) ([]ListParticipantExpenseEntriesRow, error)
 	ListParticipantsByEventID(ctx context.Context, eventID int64) ([]ListParticipantsByEventIDRow, error)
+	ListPendingPaymentRequestAmounts(ctx context.Context, eventID int64) ([]ListPendingPaymentRequestAmountsRow, error)
+	// File cua expense/event trong thung rac qua han, xoa tren storage truoc khi purge (cascade chi xoa ban ghi)
+	ListPurgeableAttachmentKeys(ctx context.Context, cutoff time.Time) ([]string, error)
 	ListSettlementsByEvent(ctx context.Context, eventID int64) ([]ListSettlementsByEventRow, error)
+	MarkEventSnapshotReopened(ctx context.Context, eventID int64) error
+	// Ca 2 cung huong loi 1 expense: cong share/ratio/input vao dong cua dich, xoa dong cua nguon
+	MergeDuplicateBeneficiaries(ctx context.Context, arg MergeDuplicateBeneficiariesParams) error
+	// Ca 2 cung an 1 mon: cong weight/share vao dong cua dich, xoa dong cua nguon
+	MergeDuplicateItemBeneficiaries(ctx context.Context, arg MergeDuplicateItemBeneficiariesParams) error
+	// Ca 2 cung tra 1 expense: cong paid_amount vao dong cua dich, xoa dong cua nguon
+	MergeDuplicatePayers(ctx context.Context, arg MergeDuplicatePayersParams) error
+	PurgeDeletedEvents(ctx context.Context, cutoff time.Time) (int64, error)
+	PurgeDeletedExpenses(ctx context.Context, cutoff time.Time) (int64, error)
+	// Chuyen moi dong con lai cua nguon sang dich
+	ReassignParticipantReferences(ctx context.Context, arg ReassignParticipantReferencesParams) error
+	// Tu choi cac settlement dang cho xac nhan cua participant bi xoa, tranh xac nhan sau khi so du da chot
+	RejectParticipantSettlements(ctx context.Context, arg RejectParticipantSettlementsParams) error
 	RemoveParticipant(ctx context.Context, arg RemoveParticipantParams) error
 	RemoveParticipantByID(ctx context.Context, participantID int64) error
+	// Chi cap nhat yeu cau dang cho, tranh 2 request xu ly cung luc
+	ResolveOwnershipTransfer(ctx context.Context, arg ResolveOwnershipTransferParams) (EventOwnershipTransfer, error)
+	ResolveSettlement(ctx context.Context, arg ResolveSettlementParams) (int64, error)
+	RestoreEvent(ctx context.Context, eventID int64) (Event, error)
+	RestoreExpense(ctx context.Context, expenseID int64) (Expense, error)
+	// User da bi xoa tham gia lai: khoi phuc dong cu thay vi tao moi
+	RestoreParticipant(ctx context.Context, arg RestoreParticipantParams) (Participant, error)
+	RevokeEventInvite(ctx context.Context, inviteID int64) (int64, error)
+	// Thu hoi moi invite con hieu luc cua event (event khong con owner)
+	RevokeEventInvites(ctx context.Context, eventID int64) error
+	SetEventClosed(ctx context.Context, arg SetEventClosedParams) (Event, error)
+	SetEventCreator(ctx context.Context, arg SetEventCreatorParams) error
+	SetExpenseBeneficiaryShare(ctx context.Context, arg SetExpenseBeneficiaryShareParams) error
+	SetExpensePayerAmount(ctx context.Context, arg SetExpensePayerAmountParams) error
+	SetPaymentRequestAmount(ctx context.Context, arg SetPaymentRequestAmountParams) error
+	SetSettlementAmount(ctx context.Context, arg SetSettlementAmountParams) error
+	// Dua event vao thung rac, job purge xoa han sau khi het han luu tru
+	SoftDeleteEvent(ctx context.Context, eventID int64) (int64, error)
+	// Dua expense vao thung rac, chi tiet payer/beneficiary giu nguyen de khoi phuc. expected_version NULL thi khong kiem tra
+	SoftDeleteExpense(ctx context.Context, arg SoftDeleteExpenseParams) (int64, error)
+	// An participant khoi expense moi, giu lai lich su
+	SoftRemoveParticipant(ctx context.Context, participantID int64) (Participant, error)
 	UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event, error)
+	UpdateEventCategory(ctx context.Context, arg UpdateEventCategoryParams) (ExpenseCategory, error)
+	// Chi sua khi version khop voi ban client da doc, khong khop thi khong co dong nao (pgx.ErrNoRows)
 	UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (Expense, error)
 	UpdateParticipant(ctx context.Context, arg UpdateParticipantParams) (Participant, error)
+	UpdateParticipantRole(ctx context.Context, arg UpdateParticipantRoleParams) (Participant, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserAvatar(ctx context.Context, arg UpdateUserAvatarParams) (User, error)
 	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error)
+	UpsertEventExchangeRate(ctx context.Context, arg UpsertEventExchangeRateParams) (EventExchangeRate, error)
+	// Tang use_count neu invite con hieu luc, khong tra ve dong nao khi da het han/het luot/bi thu hoi
+	UseEventInvite(ctx context.Context, inviteID int64) (EventInvite, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -8,6 +8,7 @@ package database
 import (
 	"context"
 
+	"BACKEND/internal/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -54,17 +55,24 @@ func (q *Queries) CreateCollector(ctx context.Context, arg CreateCollectorParams
 
 const createSettlement = `-- name: CreateSettlement :one
 INSERT INTO settlements (
-    event_id, settlement_uuid, payer_id, receiver_id, amount
+    event_id, settlement_uuid, payer_id, receiver_id, amount, created_by, reason, reversal_of,
+    status, resolved_by, resolved_at
 ) VALUES (
-    $1, gen_random_uuid(), $2, $3, $4
-) RETURNING settlement_id, settlement_uuid, event_id, amount, created_at, payer_id, receiver_id
+    $1, gen_random_uuid(), $2, $3, $4, $5, $6, $7,
+    $8, $9, CASE WHEN $8::text = 'pending' THEN NULL ELSE NOW() END
+) RETURNING settlement_id, settlement_uuid, event_id, amount, created_at, payer_id, receiver_id, created_by, reason, reversal_of, status, resolved_by, resolved_at, reject_reason
 `
 
 type CreateSettlementParams struct {
-	EventID    int64          `json:"event_id"`
-	PayerID    *int64         `json:"payer_id"`
-	ReceiverID *int64         `json:"receiver_id"`
-	Amount     pgtype.Numeric `json:"amount"`
+	EventID    int64        `json:"event_id"`
+	PayerID    *int64       `json:"payer_id"`
+	ReceiverID *int64       `json:"receiver_id"`
+	Amount     money.Amount `json:"amount"`
+	CreatedBy  *int64       `json:"created_by"`
+	Reason     *string      `json:"reason"`
+	ReversalOf *int64       `json:"reversal_of"`
+	Status     string       `json:"status"`
+	ResolvedBy *int64       `json:"resolved_by"`
 }
 
 func (q *Queries) CreateSettlement(ctx context.Context, arg CreateSettlementParams) (Settlement, error) {
@@ -73,6 +81,11 @@ func (q *Queries) CreateSettlement(ctx context.Context, arg CreateSettlementPara
 		arg.PayerID,
 		arg.ReceiverID,
 		arg.Amount,
+		arg.CreatedBy,
+		arg.Reason,
+		arg.ReversalOf,
+		arg.Status,
+		arg.ResolvedBy,
 	)
 	var i Settlement
 	err := row.Scan(
@@ -83,6 +96,13 @@ func (q *Queries) CreateSettlement(ctx context.Context, arg CreateSettlementPara
 		&i.CreatedAt,
 		&i.PayerID,
 		&i.ReceiverID,
+		&i.CreatedBy,
+		&i.Reason,
+		&i.ReversalOf,
+		&i.Status,
+		&i.ResolvedBy,
+		&i.ResolvedAt,
+		&i.RejectReason,
 	)
 	return i, err
 }
@@ -154,39 +174,40 @@ SELECT
         SELECT SUM(ep.paid_amount) 
         FROM expense_payers ep 
         JOIN expenses e ON ep.expense_id = e.expense_id 
-        WHERE ep.participant_id = p.participant_id AND e.event_id = $1
+        WHERE ep.participant_id = p.participant_id AND e.event_id = $1 AND e.deleted_at IS NULL
     ), 0)::numeric as total_paid,
     COALESCE((
-        SELECT SUM(e.total_amount * eb.split_ratio) 
+        SELECT SUM(eb.share_amount) 
         FROM expense_beneficiaries eb 
         JOIN expenses e ON eb.expense_id = e.expense_id 
-        WHERE eb.participant_id = p.participant_id AND e.event_id = $1
+        WHERE eb.participant_id = p.participant_id AND e.event_id = $1 AND e.deleted_at IS NULL
     ), 0)::numeric as total_share,
     COALESCE((
         SELECT SUM(s.amount) 
         FROM settlements s 
-        WHERE s.payer_id = p.participant_id AND s.event_id = $1
+        WHERE s.payer_id = p.participant_id AND s.event_id = $1 AND s.status = 'confirmed'
     ), 0)::numeric as total_settled_sent,
     COALESCE((
         SELECT SUM(s.amount) 
         FROM settlements s 
-        WHERE s.receiver_id = p.participant_id AND s.event_id = $1
+        WHERE s.receiver_id = p.participant_id AND s.event_id = $1 AND s.status = 'confirmed'
     ), 0)::numeric as total_settled_received
 FROM participants p
 WHERE p.event_id = $1
 `
 
 type GetEventBalancesRow struct {
-	ParticipantID        int64          `json:"participant_id"`
-	ParticipantUuid      uuid.UUID      `json:"participant_uuid"`
-	Name                 string         `json:"name"`
-	UserID               *int64         `json:"user_id"`
-	TotalPaid            pgtype.Numeric `json:"total_paid"`
-	TotalShare           pgtype.Numeric `json:"total_share"`
-	TotalSettledSent     pgtype.Numeric `json:"total_settled_sent"`
-	TotalSettledReceived pgtype.Numeric `json:"total_settled_received"`
+	ParticipantID        int64        `json:"participant_id"`
+	ParticipantUuid      uuid.UUID    `json:"participant_uuid"`
+	Name                 string       `json:"name"`
+	UserID               *int64       `json:"user_id"`
+	TotalPaid            money.Amount `json:"total_paid"`
+	TotalShare           money.Amount `json:"total_share"`
+	TotalSettledSent     money.Amount `json:"total_settled_sent"`
+	TotalSettledReceived money.Amount `json:"total_settled_received"`
 }
 
+// paid_amount/share_amount da duoc quy doi sang currency cua event luc luu expense (xem expenses.exchange_rate)
 func (q *Queries) GetEventBalances(ctx context.Context, eventID int64) ([]GetEventBalancesRow, error) {
 	rows, err := q.db.Query(ctx, getEventBalances, eventID)
 	if err != nil {
@@ -216,27 +237,107 @@ func (q *Queries) GetEventBalances(ctx context.Context, eventID int64) ([]GetEve
 	return items, nil
 }
 
+const getSettlementByUUID = `-- name: GetSettlementByUUID :one
+SELECT 
+    s.settlement_id, s.settlement_uuid, s.event_id, s.payer_id, s.receiver_id, s.created_by,
+    s.amount, s.created_at, s.reason, s.status, s.reject_reason,
+    p_payer.name as payer_name, p_payer.participant_uuid as payer_uuid,
+    p_receiver.name as receiver_name, p_receiver.participant_uuid as receiver_uuid,
+    p_receiver.user_id as receiver_user_id,
+    u.name as created_by_name,
+    orig.settlement_uuid as reversal_of_uuid,
+    rev.settlement_uuid as reversed_by_uuid
+FROM settlements s
+JOIN participants p_payer ON s.payer_id = p_payer.participant_id
+JOIN participants p_receiver ON s.receiver_id = p_receiver.participant_id
+LEFT JOIN users u ON s.created_by = u.user_id
+LEFT JOIN settlements orig ON s.reversal_of = orig.settlement_id
+LEFT JOIN settlements rev ON rev.reversal_of = s.settlement_id AND rev.status <> 'rejected'
+WHERE s.settlement_uuid = $1
+`
+
+type GetSettlementByUUIDRow struct {
+	SettlementID   int64              `json:"settlement_id"`
+	SettlementUuid uuid.UUID          `json:"settlement_uuid"`
+	EventID        int64              `json:"event_id"`
+	PayerID        *int64             `json:"payer_id"`
+	ReceiverID     *int64             `json:"receiver_id"`
+	CreatedBy      *int64             `json:"created_by"`
+	Amount         money.Amount       `json:"amount"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	Reason         *string            `json:"reason"`
+	Status         string             `json:"status"`
+	RejectReason   *string            `json:"reject_reason"`
+	PayerName      string             `json:"payer_name"`
+	PayerUuid      uuid.UUID          `json:"payer_uuid"`
+	ReceiverName   string             `json:"receiver_name"`
+	ReceiverUuid   uuid.UUID          `json:"receiver_uuid"`
+	ReceiverUserID *int64             `json:"receiver_user_id"`
+	CreatedByName  *string            `json:"created_by_name"`
+	ReversalOfUuid pgtype.UUID        `json:"reversal_of_uuid"`
+	ReversedByUuid pgtype.UUID        `json:"reversed_by_uuid"`
+}
+
+func (q *Queries) GetSettlementByUUID(ctx context.Context, settlementUuid uuid.UUID) (GetSettlementByUUIDRow, error) {
+	row := q.db.QueryRow(ctx, getSettlementByUUID, settlementUuid)
+	var i GetSettlementByUUIDRow
+	err := row.Scan(
+		&i.SettlementID,
+		&i.SettlementUuid,
+		&i.EventID,
+		&i.PayerID,
+		&i.ReceiverID,
+		&i.CreatedBy,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Reason,
+		&i.Status,
+		&i.RejectReason,
+		&i.PayerName,
+		&i.PayerUuid,
+		&i.ReceiverName,
+		&i.ReceiverUuid,
+		&i.ReceiverUserID,
+		&i.CreatedByName,
+		&i.ReversalOfUuid,
+		&i.ReversedByUuid,
+	)
+	return i, err
+}
+
 const listSettlementsByEvent = `-- name: ListSettlementsByEvent :many
 SELECT 
-    s.settlement_id, s.settlement_uuid, s.amount, s.created_at,
+    s.settlement_id, s.settlement_uuid, s.amount, s.created_at, s.reason, s.status, s.reject_reason,
     p_payer.name as payer_name, p_payer.participant_uuid as payer_uuid,
-    p_receiver.name as receiver_name, p_receiver.participant_uuid as receiver_uuid
+    p_receiver.name as receiver_name, p_receiver.participant_uuid as receiver_uuid,
+    u.name as created_by_name,
+    orig.settlement_uuid as reversal_of_uuid,
+    rev.settlement_uuid as reversed_by_uuid
 FROM settlements s
 JOIN participants p_payer ON s.payer_id = p_payer.participant_id
 JOIN participants p_receiver ON s.receiver_id = p_receiver.participant_id
+LEFT JOIN users u ON s.created_by = u.user_id
+LEFT JOIN settlements orig ON s.reversal_of = orig.settlement_id
+LEFT JOIN settlements rev ON rev.reversal_of = s.settlement_id AND rev.status <> 'rejected'
 WHERE s.event_id = $1
-ORDER BY s.created_at DESC
+ORDER BY s.created_at DESC, s.settlement_id DESC
 `
 
 type ListSettlementsByEventRow struct {
 	SettlementID   int64              `json:"settlement_id"`
 	SettlementUuid uuid.UUID          `json:"settlement_uuid"`
-	Amount         pgtype.Numeric     `json:"amount"`
+	Amount         money.Amount       `json:"amount"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	Reason         *string            `json:"reason"`
+	Status         string             `json:"status"`
+	RejectReason   *string            `json:"reject_reason"`
 	PayerName      string             `json:"payer_name"`
 	PayerUuid      uuid.UUID          `json:"payer_uuid"`
 	ReceiverName   string             `json:"receiver_name"`
 	ReceiverUuid   uuid.UUID          `json:"receiver_uuid"`
+	CreatedByName  *string            `json:"created_by_name"`
+	ReversalOfUuid pgtype.UUID        `json:"reversal_of_uuid"`
+	ReversedByUuid pgtype.UUID        `json:"reversed_by_uuid"`
 }
 
 func (q *Queries) ListSettlementsByEvent(ctx context.Context, eventID int64) ([]ListSettlementsByEventRow, error) {
@@ -253,10 +354,16 @@ func (q *Queries) ListSettlementsByEvent(ctx context.Context, eventID int64) ([]
 			&i.SettlementUuid,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Reason,
+			&i.Status,
+			&i.RejectReason,
 			&i.PayerName,
 			&i.PayerUuid,
 			&i.ReceiverName,
 			&i.ReceiverUuid,
+			&i.CreatedByName,
+			&i.ReversalOfUuid,
+			&i.ReversedByUuid,
 		); err != nil {
 			return nil, err
 		}
@@ -267,3 +374,29 @@ func (q *Queries) ListSettlementsByEvent(ctx context.Context, eventID int64) ([]
 	}
 	return items, nil
 }
+
+const resolveSettlement = `-- name: ResolveSettlement :execrows
+UPDATE settlements
+SET status = $2, resolved_by = $3, resolved_at = NOW(), reject_reason = $4
+WHERE settlement_id = $1 AND status = 'pending'
+`
+
+type ResolveSettlementParams struct {
+	SettlementID int64   `json:"settlement_id"`
+	Status       string  `json:"status"`
+	ResolvedBy   *int64  `json:"resolved_by"`
+	RejectReason *string `json:"reject_reason"`
+}
+
+func (q *Queries) ResolveSettlement(ctx context.Context, arg ResolveSettlementParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resolveSettlement,
+		arg.SettlementID,
+		arg.Status,
+		arg.ResolvedBy,
+		arg.RejectReason,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 1 dong trong activity log cua event
+type ActivityDTO struct {
+	ID         string          `json:"id"`
+	Action     string          `json:"action"`     // vd expense.updated, settlement.created
+	EntityType string          `json:"entityType"` // expense | settlement | participant | collector | payment_request
+	EntityID   string          `json:"entityId"`
+	Actor      *ActivityActor  `json:"actor,omitempty"` // Trong neu tai khoan da bi xoa
+	Data       json.RawMessage `json:"data,omitempty"`  // Chi tiet theo action (truoc/sau, so tien...)
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+type ActivityActor struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Trang activity feed, NextCursor rong la het
+type ActivityFeedResponse struct {
+	Items      []ActivityDTO `json:"items"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// File hoa don dinh kem transaction. URL/ThumbnailURL la duong dan API (can token), khong phai link storage
+type AttachmentDTO struct {
+	ID           string    `json:"id"`
+	FileName     string    `json:"fileName"`
+	ContentType  string    `json:"contentType"`
+	Size         int64     `json:"size"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnailUrl,omitempty"`
+	UploaderID   string    `json:"uploaderId,omitempty"`
+	UploaderName string    `json:"uploaderName,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -58,3 +58,8 @@ type UpdateProfileRequest struct {
 	AccountName   string `json:"accountName"`
 }
 
+// Xac nhan lai mat khau truoc khi xoa tai khoan
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
@@ -0,0 +1,54 @@
+package models
+
+import "BACKEND/internal/money"
+
+// Danh muc chi tieu. Danh muc co san co Code co dinh, danh muc rieng cua event thi khong
+type CategoryDTO struct {
+	ID      string `json:"id"`
+	Code    string `json:"code,omitempty"`
+	Name    string `json:"name"`
+	Icon    string `json:"icon,omitempty"`
+	BuiltIn bool   `json:"builtIn"`
+}
+
+// API: POST /events/:eventId/categories
+type CreateCategoryRequest struct {
+	Name string `json:"name" validate:"required"`
+	Icon string `json:"icon,omitempty"`
+}
+
+// API: PATCH /events/:eventId/categories/:categoryId - bo trong truong nao thi giu nguyen
+type UpdateCategoryRequest struct {
+	Name *string `json:"name"`
+	Icon *string `json:"icon"`
+}
+
+// Tag dang dung trong event kem so transaction
+type TagDTO struct {
+	Tag          string `json:"tag"`
+	Transactions int    `json:"transactions"`
+}
+
+// Tong chi cua 1 danh muc, Category nil la chua phan loai
+type CategoryTotalDTO struct {
+	Category     *CategoryDTO `json:"category"`
+	Amount       money.Amount `json:"amount"`
+	Transactions int          `json:"transactions"`
+}
+
+// Phan cua 1 participant theo tung danh muc
+type ParticipantCategoryBreakdownDTO struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	Total      money.Amount       `json:"total"`
+	Categories []CategoryTotalDTO `json:"categories"`
+}
+
+// API: GET /events/:eventId/categories/breakdown
+// So tien da quy doi sang currency cua event, danh muc nhieu tien truoc
+type CategoryBreakdownResponse struct {
+	Currency     string                            `json:"currency"`
+	Total        money.Amount                      `json:"total"`
+	Categories   []CategoryTotalDTO                `json:"categories"`
+	Participants []ParticipantCategoryBreakdownDTO `json:"participants"`
+}
@@ -9,6 +9,7 @@ type BankInfoDTO struct {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	Data    any    `json:"data,omitempty"` // Trang thai hien tai khi bi conflict
 }
 
 type SuccessResponse struct {
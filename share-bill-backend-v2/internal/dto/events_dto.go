@@ -1,18 +1,26 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"BACKEND/internal/money"
+)
 
 
 type CreateEventRequest struct {
 	Name        string `json:"name" validate:"required"`
 	Currency    string `json:"currency" validate:"required"`
 	Description string `json:"description"`
+	Timezone    string `json:"timezone"` // IANA, vd Asia/Ho_Chi_Minh; bo trong = mac dinh
 }
 
 type UpdateEventRequest struct {
 	Name		*string `json:"name"`
 	Description *string `json:"description"`
 	Currency    *string `json:"currency"`
+	// Bat buoc khi doi currency: 1 don vi currency cu = ConversionRate currency moi
+	ConversionRate *float64 `json:"conversionRate"`
+	Timezone       *string  `json:"timezone"`
 	Status 	*string `json:"status"`
 }
 
@@ -27,6 +35,7 @@ type EventInfoDTO struct {
 	Name        string      `json:"name"`
 	Description string      `json:"description"`
 	Currency    string      `json:"currency"`
+	Timezone    string      `json:"timezone"`
 	Status      string      `json:"status"`
 	CreatedBy   CreatorDTO  `json:"createdBy"`
 	CreatedAt   time.Time   `json:"createdAt"`
@@ -41,6 +50,75 @@ type CreatorDTO struct {
 type EventStatsDTO struct {
 	TotalParticipants int     `json:"totalParticipants"`
 	TotalTransactions int     `json:"totalTransactions"`
-	TotalExpenses     float64 `json:"totalExpenses"`
-	AveragePerPerson  float64 `json:"averagePerPerson"`
-}
\ No newline at end of file
+	TotalExpenses     money.Amount `json:"totalExpenses"`
+	AveragePerPerson  money.Amount `json:"averagePerPerson"`
+}
+// Dong event, strategy bo trong thi dung greedy cho settlement plan duoc chot
+type CloseEventRequest struct {
+	Strategy string `json:"strategy"` // greedy | minimal | collector
+}
+
+// Summary duoc chot tai thoi diem dong event
+type EventSnapshotDTO struct {
+	ID         string               `json:"id"`
+	EventID    string               `json:"eventId"`
+	ClosedAt   time.Time            `json:"closedAt"`
+	ReopenedAt *time.Time           `json:"reopenedAt,omitempty"`
+	Summary    EventSummaryResponse `json:"summary"`
+}
+
+// Owner de nghi chuyen quyen cho 1 thanh vien co tai khoan
+type TransferOwnershipRequest struct {
+	ParticipantID string `json:"participantId"`
+}
+
+// Yeu cau chuyen quyen owner
+type OwnershipTransferDTO struct {
+	ID          string     `json:"id"`
+	EventID     string     `json:"eventId"`
+	From        string     `json:"fromParticipantId"`
+	FromName    string     `json:"fromName"`
+	To          string     `json:"toParticipantId"`
+	ToName      string     `json:"toName"`
+	Status      string     `json:"status"` // pending | accepted | declined | cancelled
+	CreatedAt   time.Time  `json:"createdAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+}
+
+// Event trong thung rac, bi xoa han sau PurgeAt
+type DeletedEventDTO struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Currency  string    `json:"currency"`
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+// 1 don vi Currency = Rate don vi currency cua event
+type ExchangeRateDTO struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	Source    string    `json:"source"` // manual | file
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// API: GET /events/:eventId/exchange-rates
+type ExchangeRatesResponse struct {
+	EventCurrency string            `json:"eventCurrency"`
+	Rates         []ExchangeRateDTO `json:"rates"`
+}
+
+type ExchangeRateInput struct {
+	Currency string  `json:"currency" validate:"required"`
+	Rate     float64 `json:"rate" validate:"required,gt=0"`
+}
+
+// API: PUT /events/:eventId/exchange-rates - nhap tay, currency da co thi ghi de
+type SetExchangeRatesRequest struct {
+	Rates []ExchangeRateInput `json:"rates" validate:"required,min=1"`
+}
+
+// API: POST /events/:eventId/exchange-rates/import - bo trong currencies thi nap tat ca trong file
+type ImportExchangeRatesRequest struct {
+	Currencies []string `json:"currencies"`
+}
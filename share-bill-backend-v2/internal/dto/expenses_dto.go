@@ -1,17 +1,80 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"BACKEND/internal/money"
+)
 
 type CreateTransactionRequest struct {
 	Description   string             `json:"description" validate:"required"`
-	Amount        float64            `json:"amount" validate:"required,gt=0"`
-	Payers        []string 			 `json:"payers" validate:"required"`
+	Amount        money.Amount       `json:"amount" validate:"required,gt=0"`
+	Payers        []TransactionPayer `json:"payers" validate:"required"`
 	Beneficiaries []TransactionBeneficiary `json:"beneficiaries" validate:"required,min=1"` 
-	Attachment    string             `json:"attachment,omitempty"`
+	SplitMode     string             `json:"splitMode,omitempty"` // equal | shares (mac dinh) | percentage | exact | adjustment | itemized
+	Items         []TransactionItem       `json:"items,omitempty"`       // Chi dung cho mode itemized
+	Adjustments   []TransactionAdjustment `json:"adjustments,omitempty"` // Phu thu/giam gia cua ca hoa don (itemized)
+	Currency      string             `json:"currency,omitempty"`     // Bo trong = currency cua event, moi so tien trong request tinh theo currency nay
+	ExchangeRate  *float64           `json:"exchangeRate,omitempty"` // 1 currency = ? currency cua event, bo trong thi lay tu bang ty gia
+	CategoryID    string             `json:"categoryId,omitempty"`   // ID danh muc hoac code cua danh muc co san, bo trong = chua phan loai
+	Tags          []string           `json:"tags,omitempty"`
+	SpentAt       string             `json:"spentAt,omitempty"` // RFC3339, YYYY-MM-DDTHH:MM hoac YYYY-MM-DD theo mui gio cua event; bo trong = bay gio (sua thi giu nguyen)
+}
+
+// Bo loc cho GET /events/:eventId/transactions, bo trong truong nao thi khong loc theo truong do
+type TransactionFilter struct {
+	Category string // ID, code danh muc co san hoac "none" = chua phan loai
+	Tag      string
+	From     string // Ngay/gio bat dau (tinh ca moc nay)
+	To       string // Ngay ket thuc (tinh ca ngay) hoac moc gio (khong tinh moc nay)
 }
+// Payer kem so tien da tra. Amount bo trong thi chia deu total cho cac payer.
+type TransactionPayer struct {
+	ParticipantID string        `json:"participantId" validate:"required"`
+	Amount        *money.Amount `json:"amount,omitempty"`
+}
+
+// Van nhan dinh dang cu: "payers": ["<participant uuid>", ...]
+func (p *TransactionPayer) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*p = TransactionPayer{ParticipantID: id}
+		return nil
+	}
+	type payerAlias TransactionPayer
+	var alias payerAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	*p = TransactionPayer(alias)
+	return nil
+}
+
 type TransactionBeneficiary struct {
-	ParticipantID string  `json:"participantId" validate:"required"` 
-	Weight        float64 `json:"weight"`                            
+	ParticipantID string       `json:"participantId" validate:"required"` 
+	Weight        float64      `json:"weight"`                            
+	Percentage    *float64      `json:"percentage,omitempty"` // Dung cho mode percentage
+	Amount        *money.Amount `json:"amount,omitempty"`     // Dung cho mode exact
+	Adjustment    *money.Amount `json:"adjustment,omitempty"` // Dung cho mode adjustment (+/-)
+	Share         money.Amount `json:"share,omitempty"` // So tien thuc te phai chiu (chi co trong response)
+	OriginalShare *money.Amount `json:"originalShare,omitempty"` // Share theo currency cua transaction (chi co trong response)
+}
+
+// Mon tren hoa don, moi mon co danh sach nguoi an rieng (weight bo trong = chia deu)
+type TransactionItem struct {
+	ID            string                   `json:"id,omitempty"`
+	Name          string                   `json:"name"`
+	Amount        money.Amount             `json:"amount"`
+	Beneficiaries []TransactionBeneficiary `json:"beneficiaries"`
+}
+
+// Phu thu (VAT, phi phuc vu, tip) hoac giam gia, nhap theo percent hoac so tien co dinh
+type TransactionAdjustment struct {
+	Kind    string        `json:"kind"` // surcharge | discount
+	Name    string        `json:"name"`
+	Percent *float64      `json:"percent,omitempty"`
+	Amount  *money.Amount `json:"amount,omitempty"`
 }
 
 type TransactionResponse struct {
@@ -43,21 +106,64 @@ type TransactionResponse struct {
 type TransactionDTO struct {
 	ID        string    `json:"id"`        
 	Description     string    `json:"description"`    
-	Amount    float64   `json:"amount"`    
-	Date      time.Time `json:"date"`      
+	Amount    money.Amount `json:"amount"`    
+	Date      time.Time `json:"date"` // Thoi diem chi tieu (spentAt)
+	CreatedAt time.Time `json:"createdAt"`
 	PayerNames  []string  `json:"payerNames"` 
+	Currency       string       `json:"currency"`       // Currency goc cua transaction
+	OriginalAmount money.Amount `json:"originalAmount"` // Theo currency goc, Amount la so da quy doi sang currency cua event
+	Category       *CategoryDTO `json:"category"`
+	Tags           []string     `json:"tags"`
+}
+// Transaction trong thung rac, bi xoa han sau PurgeAt
+type DeletedTransactionDTO struct {
+	ID          string       `json:"id"`
+	Description string       `json:"description"`
+	Amount      money.Amount `json:"amount"`
+	Date        time.Time    `json:"date"` // Thoi diem chi tieu
+	DeletedAt   time.Time    `json:"deletedAt"`
+	PurgeAt     time.Time    `json:"purgeAt"`
 }
 // API: GET /transactions/:id
 type TransactionDetailResponse struct {
 	ID     string    `json:"id"`
 	Description     string    `json:"description"`
-	Amount float64   `json:"amount"`
-	Date   time.Time `json:"date"`
+	Amount money.Amount `json:"amount"`
+	Date   time.Time    `json:"date"` // Thoi diem chi tieu (spentAt)
+	CreatedAt time.Time `json:"createdAt"`
 	Payers        []PayerInfo              `json:"payers"`        
 	Beneficiaries []TransactionBeneficiary `json:"beneficiaries"` 
-	Attachment    string                   `json:"attachment,omitempty"`
+	SplitMode     string                   `json:"splitMode"`
+	Items         []TransactionItem        `json:"items,omitempty"`
+	Adjustments   []TransactionAdjustment  `json:"adjustments,omitempty"`
+	Version       int32                    `json:"version"` // Gui lai qua If-Match khi sua/xoa
+	// Amount, paidAmount va share la so tien da quy doi sang currency cua event.
+	// Items/adjustments va so tien goc tinh theo Currency cua transaction
+	Currency       string       `json:"currency"`
+	ExchangeRate   float64      `json:"exchangeRate"`
+	OriginalAmount money.Amount `json:"originalAmount"`
+	Category       *CategoryDTO `json:"category"`
+	Tags           []string     `json:"tags"`
+	Attachments    []AttachmentDTO `json:"attachments,omitempty"` // Chi co khi xem chi tiet, khong luu vao revision
+}
+
+// 1 version trong lich su sua cua transaction
+type TransactionRevisionDTO struct {
+	Version    int32     `json:"version"`
+	EditedAt   time.Time `json:"editedAt"`
+	EditorID   string    `json:"editorId,omitempty"`
+	EditorName string    `json:"editorName,omitempty"`
+}
+
+// Noi dung day du cua 1 version
+type TransactionRevisionDetail struct {
+	TransactionRevisionDTO
+	Transaction TransactionDetailResponse `json:"transaction"`
 }
+
 type PayerInfo struct {
-	ID   string `json:"id"`   
-	Name string `json:"name"` 
+	ID         string       `json:"id"`   
+	Name       string       `json:"name"` 
+	PaidAmount money.Amount `json:"paidAmount"`
+	OriginalAmount money.Amount `json:"originalAmount"` // Theo currency cua transaction
 }
\ No newline at end of file
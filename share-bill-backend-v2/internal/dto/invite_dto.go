@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Tao link moi, expiresInHours bo trong thi mac dinh 7 ngay, maxUses bo trong thi khong gioi han.
+// participantId la khach can nhan: link chi dung duoc 1 lan va nguoi join se thay the khach do
+type CreateInviteRequest struct {
+	ExpiresInHours *int   `json:"expiresInHours"`
+	MaxUses        *int32 `json:"maxUses"`
+	ParticipantID  string `json:"participantId"`
+}
+
+// API: POST /join/:token - participantId la khach ma user nhan la minh (bo trong thi tao participant moi)
+type JoinByInviteRequest struct {
+	Name          string       `json:"name"`
+	BankInfo      *BankInfoDTO `json:"bankInfo"`
+	ParticipantID string       `json:"participantId"`
+}
+
+// Khach chua co tai khoan, co the duoc nhan khi join
+type GuestDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type InviteDTO struct {
+	ID        string     `json:"id"`
+	EventID   string     `json:"eventId"`
+	Token     string     `json:"token"`
+	Link      string     `json:"link"`
+	Status    string     `json:"status"` // active | expired | exhausted | revoked
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	MaxUses   *int32     `json:"maxUses,omitempty"`
+	UseCount  int32      `json:"useCount"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Guest     *GuestDTO  `json:"guest,omitempty"` // Link nhan khach
+}
+
+// API: GET /join/:token - xem truoc event truoc khi tham gia
+type InvitePreviewResponse struct {
+	EventID           string `json:"eventId"`
+	EventName         string `json:"eventName"`
+	CreatedBy         string `json:"createdBy"`
+	TotalParticipants int    `json:"totalParticipants"`
+	AlreadyJoined     bool   `json:"alreadyJoined"`
+	// Link nhan khach: khach se duoc thay the. Link thuong: cac khach co the chon de nhan
+	Guest  *GuestDTO  `json:"guest,omitempty"`
+	Guests []GuestDTO `json:"guests,omitempty"`
+}
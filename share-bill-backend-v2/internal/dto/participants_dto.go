@@ -21,4 +21,16 @@ type ParticipantDTO struct {
 	UserID  string `json:"userId,omitempty"` // Để biết link tới profile nào
 	Email   string `json:"email,omitempty"`  // Để hiển thị email
 	IsGuest bool   `json:"isGuest"`          // True = User ảo, False = User thật
-}
\ No newline at end of file
+	Role    string `json:"role"`             // owner | admin | member | viewer
+	// Da bi xoa khoi event, chi con trong lich su
+	RemovedAt *time.Time `json:"removedAt,omitempty"`
+}
+
+// Doi role cua thanh vien (khong dung de chuyen owner)
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required"` // admin | member | viewer
+}
+// Gop participant hien tai vao participant khac (cung event)
+type MergeParticipantRequest struct {
+	IntoParticipantID string `json:"intoParticipantId" validate:"required"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "BACKEND/internal/money"
+
 type SetCollectorRequest struct {
 	ParticipantID string       `json:"participantId" validate:"required"`
 	BankInfo      *BankInfoDTO `json:"bankInfo"`
@@ -14,6 +16,6 @@ type CollectorResponse struct {
 type PaymentQRResponse struct {
 	QRCodeURL string      `json:"qrCodeUrl"` // Link ảnh VietQR
 	BankInfo  BankInfoDTO `json:"bankInfo"`
-	Amount    float64     `json:"amount"`
+	Amount    money.Amount `json:"amount"`
 	Content   string      `json:"content"`
 }
\ No newline at end of file
@@ -1,11 +1,15 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"BACKEND/internal/money"
+)
 
 type CreatePaymentRequestRequest struct {
 	PayerID    string  `json:"payerId" validate:"required"`
 	ReceiverID string  `json:"receiverId" validate:"required"`
-	Amount     float64 `json:"amount" validate:"required,gt=0"`
+	Amount     money.Amount `json:"amount" validate:"required,gt=0"`
 }
 
 type PaymentPartyDTO struct {
@@ -18,7 +22,7 @@ type PaymentRequestDTO struct {
 	EventID   string           `json:"eventId"`
 	Payer     PaymentPartyDTO  `json:"payer"`
 	Receiver  PaymentPartyDTO  `json:"receiver"`
-	Amount    float64          `json:"amount"`
+	Amount    money.Amount     `json:"amount"`
 	Status    string           `json:"status"`
 	CreatedAt time.Time        `json:"createdAt"`
 	UpdatedAt time.Time        `json:"updatedAt"`
@@ -1,34 +1,72 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"BACKEND/internal/money"
+)
 
 
 type CreateSettlementRequest struct {
 	PayerUUID    string  `json:"payerId" validate:"required"`
 	ReceiverUUID string  `json:"receiverId" validate:"required"`
-	Amount       float64 `json:"amount" validate:"required,gt=0"`
+	Amount       money.Amount `json:"amount" validate:"required,gt=0"`
+}
+// Dao nguoc settlement ghi nham, bat buoc co ly do
+type ReverseSettlementRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// Tu choi settlement dang pending, bat buoc co ly do
+type RejectSettlementRequest struct {
+	Reason string `json:"reason" validate:"required"`
 }
+
+type SettlementDTO struct {
+	ID           string          `json:"id"`
+	Payer        SettlementParty `json:"payer"`
+	Receiver     SettlementParty `json:"receiver"`
+	Amount       money.Amount    `json:"amount"`
+	Status       string          `json:"status"` // pending | confirmed | rejected
+	Reason       string          `json:"reason,omitempty"`
+	RejectReason string          `json:"rejectReason,omitempty"`
+	CreatedBy    string          `json:"createdBy,omitempty"`  // Ten user da ghi nhan
+	CreatedAt    time.Time       `json:"createdAt"`
+	ReversalOf   string          `json:"reversalOf,omitempty"` // Settlement goc neu day la but toan dao nguoc
+	ReversedBy   string          `json:"reversedBy,omitempty"` // But toan da dao nguoc settlement nay
+}
+
 type EventSummaryResponse struct {
 	Event          SettlementEventDTO        `json:"event"`
 	Summary        SummaryInfoDTO      `json:"summary"`
 	Participants   []ParticipantBalDTO `json:"participants"`   
 	SettlementPlan []SettlementPlanDTO `json:"settlementPlan"` 
+	PairwiseDebts  []SettlementPlanDTO `json:"pairwiseDebts,omitempty"` // Chi co khi goi voi ?pairwise=gross|net
 	Meta           SummaryMeta         `json:"meta"`
 }
 
 type SettlementEventDTO struct {
 	ID                string `json:"id"`
 	Name              string `json:"name"`
-	TotalExpenses     float64 `json:"totalExpenses"`
-	AveragePerPerson  float64 `json:"averagePerPerson"`
+	TotalExpenses     money.Amount `json:"totalExpenses"`
+	AveragePerPerson  money.Amount `json:"averagePerPerson"`
 	TotalParticipants int     `json:"totalParticipants"`
 	Currency          string  `json:"currency"`
 	Status            string  `json:"status"`
 }
 
 type SummaryInfoDTO struct {
-	TotalPaidByAll float64       `json:"totalPaidByAll"`
-	Collector      *CollectorDTO `json:"collector"`
+	TotalPaidByAll money.Amount       `json:"totalPaidByAll"`
+	Collector      *CollectorDTO      `json:"collector"`
+	ByCurrency     []CurrencyTotalDTO `json:"byCurrency"` // Tong chi theo currency goc cua transaction
+}
+
+// Amount la tong da quy doi sang currency cua event
+type CurrencyTotalDTO struct {
+	Currency       string       `json:"currency"`
+	Transactions   int64        `json:"transactions"`
+	OriginalAmount money.Amount `json:"originalAmount"`
+	Amount         money.Amount `json:"amount"`
 }
 
 type CollectorDTO struct {
@@ -41,9 +79,9 @@ type ParticipantBalDTO struct {
 	ID             string       `json:"id"`
 	Name           string       `json:"name"`
 	Avatar         string       `json:"avatar,omitempty"`
-	TotalPaid      float64      `json:"totalPaid"`
-	TotalBenefit   float64      `json:"totalBenefit"` // Số tiền phải đóng
-	Balance        float64      `json:"balance"`      // Dư/Nợ
+	TotalPaid      money.Amount `json:"totalPaid"`
+	TotalBenefit   money.Amount `json:"totalBenefit"` // Số tiền phải đóng
+	Balance        money.Amount `json:"balance"`      // Dư/Nợ
 	BalanceType    string       `json:"balanceType"`  // "credit" (dư) hoặc "debit" (nợ)
 	QRCodeURL      string       `json:"qrCodeUrl,omitempty"`
 	SettlementInfo *SettlementInfoDTO `json:"settlementInfo,omitempty"`
@@ -55,9 +93,10 @@ type SettlementInfoDTO struct {
 }
 
 type SettlementPlanDTO struct {
-	From   SettlementParty `json:"from"`
-	To     SettlementParty `json:"to"`
-	Amount float64         `json:"amount"`
+	From      SettlementParty `json:"from"`
+	To        SettlementParty `json:"to"`
+	Amount    money.Amount    `json:"amount"`
+	QRCodeURL string          `json:"qrCodeUrl,omitempty"` // Chi co o strategy collector
 }
 
 type SettlementParty struct {
@@ -67,4 +106,32 @@ type SettlementParty struct {
 
 type SummaryMeta struct {
 	GeneratedAt time.Time `json:"generatedAt"`
-}
\ No newline at end of file
+	Strategy    string    `json:"strategy"` // greedy | minimal | collector
+	Pairwise    string    `json:"pairwise,omitempty"` // gross | net
+}
+// API: GET /participants/:participantId/balance
+// Cac dong cong lai dung bang Balance trong summary
+type ParticipantBalanceBreakdown struct {
+	Participant     SettlementParty   `json:"participant"`
+	Currency        string            `json:"currency"`
+	TotalPaid       money.Amount      `json:"totalPaid"`
+	TotalBenefit    money.Amount      `json:"totalBenefit"`
+	SettledSent     money.Amount      `json:"settledSent"`
+	SettledReceived money.Amount      `json:"settledReceived"`
+	Balance         money.Amount      `json:"balance"`
+	Entries         []BalanceEntryDTO `json:"entries"`
+}
+
+// 1 expense hoac settlement lam thay doi balance, sap xep theo thoi gian
+type BalanceEntryDTO struct {
+	Type           string           `json:"type"` // expense | settlement
+	ID             string           `json:"id"`
+	Date           time.Time        `json:"date"`
+	Description    string           `json:"description,omitempty"`
+	Paid           money.Amount     `json:"paid"`                   // Expense: so tien da tra
+	Share          money.Amount     `json:"share"`                  // Expense: phan phai chiu
+	Direction      string           `json:"direction,omitempty"`    // Settlement: sent | received
+	Counterparty   *SettlementParty `json:"counterparty,omitempty"` // Settlement: nguoi nhan/nguoi tra
+	Amount         money.Amount     `json:"amount"`                 // Muc thay doi balance (+/-)
+	RunningBalance money.Amount     `json:"runningBalance"`
+}
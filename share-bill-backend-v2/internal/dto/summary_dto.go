@@ -1,12 +1,14 @@
 package models
 
+import "BACKEND/internal/money"
+
 type ParticipantSumDTO struct {
 	ID           string            `json:"id"`
 	Name         string            `json:"name"`
 	Avatar       string            `json:"avatar,omitempty"`
-	TotalPaid    float64           `json:"totalPaid"`
-	TotalBenefit float64           `json:"totalBenefit"`
-	Balance      float64           `json:"balance"`     // + = Receive, - = Pay
+	TotalPaid    money.Amount      `json:"totalPaid"`
+	TotalBenefit money.Amount      `json:"totalBenefit"`
+	Balance      money.Amount      `json:"balance"`     // + = Receive, - = Pay
 	BalanceType  string            `json:"balanceType"` // 'credit' or 'debit'
 	QRCodeURL    string            `json:"qrCodeUrl,omitempty"`
 	Settlement   *SettlementAction `json:"settlementInfo,omitempty"`
@@ -20,7 +22,7 @@ type SettlementAction struct {
 type SettlementPlanItem struct {
 	From      SimpleParticipant `json:"from"`
 	To        SimpleParticipant `json:"to"`
-	Amount    float64           `json:"amount"`
+	Amount    money.Amount      `json:"amount"`
 	QRCodeURL string            `json:"qrCodeUrl,omitempty"`
 }
 
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"mime"
+
+	models "BACKEND/internal/dto"
+	services "BACKEND/internal/services"
+	utils "BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AttachmentHandler struct {
+	service *services.AttachmentService
+}
+
+// Tao attachment handler
+func NewAttachmentHandler(service *services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{service: service}
+}
+
+// POST /api/v1/transactions/:transactionId/attachments
+// Upload hoa don (multipart, field "files" nhieu file hoac "file"), JPG/PNG/WEBP/PDF toi da 5MB moi file
+// Ca request bi gioi han boi BodyLimit (10MB)
+func (h *AttachmentHandler) UploadAttachments(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	txnUUID := c.Params("transactionId")
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Multipart form with files is required",
+		})
+	}
+	files := append(form.File["files"], form.File["file"]...)
+
+	resp, err := h.service.UploadAttachments(c.Context(), userID, txnUUID, files)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Attachments uploaded successfully",
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/transactions/:transactionId/attachments
+// Danh sach hoa don dinh kem
+func (h *AttachmentHandler) ListAttachments(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	resp, err := h.service.ListAttachments(c.Context(), userID, c.Params("transactionId"))
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/transactions/:transactionId/attachments/:attachmentId?thumbnail=true
+// Tai file hoa don (hoac anh thu nho), chi thanh vien event
+func (h *AttachmentHandler) DownloadAttachment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	content, err := h.service.OpenAttachment(c.Context(), userID, c.Params("transactionId"), c.Params("attachmentId"), c.QueryBool("thumbnail"))
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	c.Set(fiber.HeaderContentType, content.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": content.FileName}))
+	// Khong cho proxy/CDN dung chung cache vi file can quyen
+	c.Set(fiber.HeaderCacheControl, "private, max-age=3600")
+	return c.SendStream(content.Body, int(content.Size))
+}
+
+// DELETE /api/v1/transactions/:transactionId/attachments/:attachmentId
+// Xoa hoa don (nguoi upload hoac admin tro len)
+func (h *AttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	if err := h.service.DeleteAttachment(c.Context(), userID, c.Params("transactionId"), c.Params("attachmentId")); err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Attachment deleted successfully",
+	})
+}
@@ -178,6 +178,28 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	})
 }
 
+// DELETE /api/v1/users/profile
+// Xoa tai khoan (xac nhan bang mat khau), quyen owner cac event duoc chuyen tu dong
+func (h *UserHandler) DeleteAccount(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req models.DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+
+	if err := h.service.DeleteAccount(c.Context(), userID, req); err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Account deleted successfully",
+	})
+}
+
 // Cap nhat avatar user
 func (h *UserHandler) UpdateAvatar(c *fiber.Ctx) error{
 	userID := c.Locals("user_id").(int64)
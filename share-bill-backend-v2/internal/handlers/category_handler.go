@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	models "BACKEND/internal/dto"
+	services "BACKEND/internal/services"
+	utils "BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryHandler struct {
+	service *services.CategoryService
+}
+
+// Tao category handler
+func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// GET /api/v1/events/:eventId/categories
+// Danh muc co san va danh muc rieng cua event
+func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.ListCategories(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/events/:eventId/categories
+// Tao danh muc rieng cho event
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	var req models.CreateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+
+	resp, err := h.service.CreateCategory(c.Context(), userID, eventUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Category created successfully",
+		Data:    resp,
+	})
+}
+
+// PATCH /api/v1/events/:eventId/categories/:categoryId
+// Doi ten/icon danh muc rieng (admin tro len)
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	var req models.UpdateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+
+	resp, err := h.service.UpdateCategory(c.Context(), userID, eventUUID, c.Params("categoryId"), req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Category updated successfully",
+		Data:    resp,
+	})
+}
+
+// DELETE /api/v1/events/:eventId/categories/:categoryId
+// Xoa danh muc rieng (admin tro len), transaction dang dung ve chua phan loai
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	if err := h.service.DeleteCategory(c.Context(), userID, eventUUID, c.Params("categoryId")); err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Category deleted successfully",
+	})
+}
+
+// GET /api/v1/events/:eventId/categories/breakdown?participantId=<id>
+// Tong chi theo danh muc cua event va cua tung participant
+func (h *CategoryHandler) GetBreakdown(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.GetBreakdown(c.Context(), userID, eventUUID, c.Query("participantId"))
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/events/:eventId/tags
+// Cac tag dang dung trong event
+func (h *CategoryHandler) ListTags(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.ListTags(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
@@ -56,7 +56,7 @@ func (h *EventHandler) GetEvent(c *fiber.Ctx) error {
 }
 
 // UpdateEvent PUT /events/:eventId
-// Cap nhat event (chi creator)
+// Cap nhat event (admin tro len)
 func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	eventUUID := c.Params("eventId")
@@ -92,52 +92,205 @@ func (h *EventHandler) ListEvents(c *fiber.Ctx) error {
 	})
 }
 
-// JoinEvent POST /events/:eventId/join
-// Tham gia event
-func (h *EventHandler) JoinEvent(c *fiber.Ctx) error {
+// LeaveEvent POST /events/:eventId/leave?reassignTo=<participantId>
+// Roi event neu balance = 0, hoac chuyen so du cho reassignTo
+func (h *EventHandler) LeaveEvent(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	eventUUID := c.Params("eventId")
-	var req models.UpsertParticipantRequest
-	_ = c.BodyParser(&req)
 
-	err := h.service.JoinEvent(c.Context(), userID, eventUUID, req)
+	err := h.service.LeaveEvent(c.Context(), userID, eventUUID, c.Query("reassignTo"))
 	if err != nil {
 		return utils.MapError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
-		Success: true, Message: "Joined event successfully",
+		Success: true, Message: "Left event successfully",
 	})
 }
 
-// LeaveEvent POST /events/:eventId/leave
-// Roi event neu balance = 0
-func (h *EventHandler) LeaveEvent(c *fiber.Ctx) error {
+// DeleteEvent DELETE /events/:eventId
+// Xoa event vao thung rac (chi owner)
+func (h *EventHandler) DeleteEvent(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	eventUUID := c.Params("eventId")
 
-	err := h.service.LeaveEvent(c.Context(), userID, eventUUID)
+	err := h.service.DeleteEvent(c.Context(), userID, eventUUID)
 	if err != nil {
 		return utils.MapError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
-		Success: true, Message: "Left event successfully",
+		Success: true, Message: "Event deleted successfully",
 	})
 }
 
-// DeleteEvent DELETE /events/:eventId
-// Xoa event (chi creator)
-func (h *EventHandler) DeleteEvent(c *fiber.Ctx) error {
+// RestoreEvent POST /events/:eventId/restore
+// Khoi phuc event tu thung rac (chi owner)
+func (h *EventHandler) RestoreEvent(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	eventUUID := c.Params("eventId")
 
-	err := h.service.DeleteEvent(c.Context(), userID, eventUUID)
+	if err := h.service.RestoreEvent(c.Context(), userID, eventUUID); err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Message: "Event restored successfully",
+	})
+}
+
+// ListDeletedEvents GET /events/trash
+// Liet ke event trong thung rac cua user
+func (h *EventHandler) ListDeletedEvents(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	resp, err := h.service.ListDeletedEvents(c.Context(), userID)
 	if err != nil {
 		return utils.MapError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
-		Success: true, Message: "Event deleted successfully",
+		Success: true, Data: resp,
+	})
+}
+
+// CloseEvent POST /events/:eventId/close
+// Dong event va chot settlement plan (chi owner)
+func (h *EventHandler) CloseEvent(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+	var req models.CloseEventRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "INVALID_BODY", Message: "Invalid request body",
+			})
+		}
+	}
+
+	resp, err := h.service.CloseEvent(c.Context(), userID, eventUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Message: "Event closed successfully", Data: resp,
+	})
+}
+
+// ReopenEvent POST /events/:eventId/reopen
+// Mo lai event da dong (chi owner)
+func (h *EventHandler) ReopenEvent(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	if err := h.service.ReopenEvent(c.Context(), userID, eventUUID); err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Message: "Event reopened successfully",
+	})
+}
+
+// GetEventSnapshot GET /events/:eventId/snapshot
+// Lay summary da chot o lan dong gan nhat
+func (h *EventHandler) GetEventSnapshot(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.GetEventSnapshot(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Data: resp,
 	})
-}
\ No newline at end of file
+}
+
+// TransferOwnership POST /events/:eventId/ownership-transfer
+// Owner de nghi chuyen quyen, nguoi nhan phai chap nhan
+func (h *EventHandler) TransferOwnership(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+	var req models.TransferOwnershipRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid request body",
+		})
+	}
+
+	resp, err := h.service.TransferOwnership(c.Context(), userID, eventUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.SuccessResponse{
+		Success: true, Message: "Ownership transfer requested", Data: resp,
+	})
+}
+
+// GetOwnershipTransfer GET /events/:eventId/ownership-transfer
+// Xem de nghi chuyen quyen dang cho
+func (h *EventHandler) GetOwnershipTransfer(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.GetOwnershipTransfer(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Data: resp,
+	})
+}
+
+// AcceptOwnershipTransfer POST /events/:eventId/ownership-transfer/accept
+// Nguoi duoc de nghi chap nhan lam owner
+func (h *EventHandler) AcceptOwnershipTransfer(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.AcceptOwnershipTransfer(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Message: "Ownership transferred successfully", Data: resp,
+	})
+}
+
+// DeclineOwnershipTransfer POST /events/:eventId/ownership-transfer/decline
+// Nguoi duoc de nghi tu choi, hoac owner huy de nghi
+func (h *EventHandler) DeclineOwnershipTransfer(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.DeclineOwnershipTransfer(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Message: "Ownership transfer " + resp.Status, Data: resp,
+	})
+}
+
+// ListActivities GET /events/:eventId/activities?limit=&before=
+// Activity feed cua event, phan trang bang nextCursor
+func (h *EventHandler) ListActivities(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.ListActivities(c.Context(), userID, eventUUID, c.QueryInt("limit"), c.Query("before"))
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true, Data: resp,
+	})
+}
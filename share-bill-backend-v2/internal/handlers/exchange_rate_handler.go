@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	models "BACKEND/internal/dto"
+	services "BACKEND/internal/services"
+	utils "BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExchangeRateHandler struct {
+	service *services.ExchangeRateService
+}
+
+// Tao exchange rate handler
+func NewExchangeRateHandler(service *services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: service}
+}
+
+// GET /api/v1/events/:eventId/exchange-rates
+// Bang ty gia cua event
+func (h *ExchangeRateHandler) ListRates(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.ListRates(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// PUT /api/v1/events/:eventId/exchange-rates
+// Nhap tay ty gia (admin tro len)
+func (h *ExchangeRateHandler) SetRates(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	var req models.SetExchangeRatesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+
+	resp, err := h.service.SetRates(c.Context(), userID, eventUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Exchange rates updated successfully",
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/events/:eventId/exchange-rates/import
+// Nap ty gia tu file local (admin tro len)
+func (h *ExchangeRateHandler) ImportRates(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	var req models.ImportExchangeRatesRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "INVALID_BODY", Message: "Invalid request body",
+			})
+		}
+	}
+
+	resp, err := h.service.ImportRates(c.Context(), userID, eventUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Exchange rates imported successfully",
+		Data:    resp,
+	})
+}
+
+// DELETE /api/v1/events/:eventId/exchange-rates/:currency
+// Xoa ty gia cua 1 currency (admin tro len)
+func (h *ExchangeRateHandler) DeleteRate(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	if err := h.service.DeleteRate(c.Context(), userID, eventUUID, c.Params("currency")); err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Exchange rate deleted successfully",
+	})
+}
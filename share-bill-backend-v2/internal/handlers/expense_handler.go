@@ -1,6 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
 	models "BACKEND/internal/dto"
 	services "BACKEND/internal/services"
 	utils "BACKEND/internal/utils"
@@ -42,13 +47,18 @@ func (h *ExpenseHandler) CreateTransaction(c *fiber.Ctx) error {
 	})
 }
 
-// GET /api/v1/events/:eventId/transactions
-// Liet ke transactions trong event
+// GET /api/v1/events/:eventId/transactions?category=<id|code|none>&tag=<tag>&from=<date>&to=<date>
+// Liet ke transactions trong event, loc theo danh muc/tag/khoang ngay chi tieu neu co
 func (h *ExpenseHandler) ListTransactions(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	eventUUID := c.Params("eventId")
 
-	resp, err := h.service.ListTransactions(c.Context(), userID, eventUUID)
+	resp, err := h.service.ListTransactions(c.Context(), userID, eventUUID, models.TransactionFilter{
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+	})
 	if err != nil {
 		return utils.MapError(c, err)
 	}
@@ -69,6 +79,23 @@ func (h *ExpenseHandler) GetTransaction(c *fiber.Ctx) error {
 	if err != nil {
 		return utils.MapError(c, err)
 	}
+	c.Set(fiber.HeaderETag, transactionETag(resp.Version))
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/transactions/:transactionId/history
+// Lich su thay doi cua transaction
+func (h *ExpenseHandler) GetTransactionHistory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	txnUUID := c.Params("transactionId")
+
+	resp, err := h.service.GetTransactionHistory(c.Context(), userID, txnUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
 	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
 		Success: true,
 		Data:    resp,
@@ -88,29 +115,180 @@ func (h *ExpenseHandler) UpdateTransaction(c *fiber.Ctx) error {
 		})
 	}
 
-	err := h.service.UpdateTransaction(c.Context(), userID, txnUUID, req)
+	expectedVersion, err := ifMatchVersion(c)
 	if err != nil {
 		return utils.MapError(c, err)
 	}
+	resp, err := h.service.UpdateTransaction(c.Context(), userID, txnUUID, req, expectedVersion)
+	if err != nil {
+		return h.mapTransactionError(c, userID, txnUUID, err)
+	}
 
+	c.Set(fiber.HeaderETag, transactionETag(resp.Version))
 	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
 		Success: true,
 		Message: "Transaction updated successfully",
+		Data:    resp,
 	})
 }
 
 // DELETE /api/v1/transactions/:transactionId
-// Xoa transaction
+// Xoa transaction vao thung rac
 func (h *ExpenseHandler) DeleteTransaction(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	txnUUID := c.Params("transactionId")
 
-	err := h.service.DeleteTransaction(c.Context(), userID, txnUUID)
+	expectedVersion, err := ifMatchVersion(c)
 	if err != nil {
 		return utils.MapError(c, err)
 	}
+	err = h.service.DeleteTransaction(c.Context(), userID, txnUUID, expectedVersion)
+	if err != nil {
+		return h.mapTransactionError(c, userID, txnUUID, err)
+	}
 	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
 		Success: true,
 		Message: "Transaction deleted successfully",
 	})
-}
\ No newline at end of file
+}
+
+// POST /api/v1/transactions/:transactionId/restore
+// Khoi phuc transaction tu thung rac
+func (h *ExpenseHandler) RestoreTransaction(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	txnUUID := c.Params("transactionId")
+
+	err := h.service.RestoreTransaction(c.Context(), userID, txnUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Transaction restored successfully",
+	})
+}
+
+// GET /api/v1/events/:eventId/transactions/trash
+// Liet ke transaction trong thung rac cua event
+func (h *ExpenseHandler) ListDeletedTransactions(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.ListDeletedTransactions(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/transactions/:transactionId/revisions
+// Liet ke cac version cua transaction
+func (h *ExpenseHandler) ListRevisions(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	txnUUID := c.Params("transactionId")
+
+	resp, err := h.service.ListRevisions(c.Context(), userID, txnUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/transactions/:transactionId/revisions/:version
+// Xem noi dung 1 version
+func (h *ExpenseHandler) GetRevision(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	txnUUID := c.Params("transactionId")
+	version, err := revisionParam(c)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	resp, err := h.service.GetRevision(c.Context(), userID, txnUUID, version)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/transactions/:transactionId/revisions/:version/restore
+// Khoi phuc transaction ve 1 version cu (tao version moi)
+func (h *ExpenseHandler) RestoreRevision(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	txnUUID := c.Params("transactionId")
+	version, err := revisionParam(c)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	resp, err := h.service.RestoreRevision(c.Context(), userID, txnUUID, version, expectedVersion)
+	if err != nil {
+		return h.mapTransactionError(c, userID, txnUUID, err)
+	}
+	c.Set(fiber.HeaderETag, transactionETag(resp.Version))
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Transaction restored to version " + strconv.Itoa(int(version)),
+		Data:    resp,
+	})
+}
+
+// Helper: ETag cua transaction la version hien tai
+func transactionETag(version int32) string {
+	return fmt.Sprintf("%q", strconv.Itoa(int(version)))
+}
+
+// Helper: doc version tu header If-Match ("3" hoac W/"3"). Bo trong hoac "*" thi khong kiem tra
+func ifMatchVersion(c *fiber.Ctx) (*int32, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	raw := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || version < 1 {
+		return nil, fmt.Errorf("%w: If-Match must be a transaction version", utils.ErrInvalidInput)
+	}
+	v := int32(version)
+	return &v, nil
+}
+
+// Helper: doc :version tren path
+func revisionParam(c *fiber.Ctx) (int32, error) {
+	version, err := strconv.ParseInt(c.Params("version"), 10, 32)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: version must be a positive integer", utils.ErrInvalidInput)
+	}
+	return int32(version), nil
+}
+
+// Helper: bi conflict thi tra 409 kem trang thai hien tai de client merge lai
+func (h *ExpenseHandler) mapTransactionError(c *fiber.Ctx, userID int64, txnUUID string, err error) error {
+	if !errors.Is(err, utils.ErrVersionConflict) {
+		return utils.MapError(c, err)
+	}
+	current, getErr := h.service.GetTransaction(c.Context(), userID, txnUUID)
+	if getErr != nil {
+		// Transaction da bi xoa trong luc sua
+		return utils.MapError(c, getErr)
+	}
+	c.Set(fiber.HeaderETag, transactionETag(current.Version))
+	return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+		Error:   "VERSION_CONFLICT",
+		Message: err.Error(),
+		Data:    current,
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	models "BACKEND/internal/dto"
+	services "BACKEND/internal/services"
+	utils "BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InviteHandler struct {
+	service *services.InviteService
+}
+
+// Tao invite handler
+func NewInviteHandler(service *services.InviteService) *InviteHandler {
+	return &InviteHandler{service: service}
+}
+
+// POST /api/v1/events/:eventId/invites
+// Tao link moi (admin tro len)
+func (h *InviteHandler) CreateInvite(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	var req models.CreateInviteRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "INVALID_BODY", Message: "Invalid request body",
+			})
+		}
+	}
+
+	resp, err := h.service.CreateInvite(c.Context(), userID, eventUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Invite created successfully",
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/events/:eventId/invites
+func (h *InviteHandler) ListInvites(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.ListInvites(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/invites/:inviteId/revoke
+func (h *InviteHandler) RevokeInvite(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	inviteUUID := c.Params("inviteId")
+
+	if err := h.service.RevokeInvite(c.Context(), userID, inviteUUID); err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Invite revoked successfully",
+	})
+}
+
+// GET /api/v1/invites/:inviteId/qr
+// Tra anh PNG chua link moi
+func (h *InviteHandler) GetInviteQR(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	inviteUUID := c.Params("inviteId")
+
+	png, err := h.service.GetInviteQR(c.Context(), userID, inviteUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Status(fiber.StatusOK).Send(png)
+}
+
+// GET /api/v1/join/:token
+// Xem truoc event truoc khi tham gia
+func (h *InviteHandler) PreviewInvite(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	token := c.Params("token")
+
+	resp, err := h.service.PreviewInvite(c.Context(), userID, token)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/join/:token
+// Tham gia event bang link moi, co the nhan 1 khach co san la minh
+func (h *InviteHandler) JoinByInvite(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	token := c.Params("token")
+	var req models.JoinByInviteRequest
+	_ = c.BodyParser(&req)
+
+	eventUUID, err := h.service.JoinByInvite(c.Context(), userID, token, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Joined event successfully",
+		Data:    fiber.Map{"eventId": eventUUID},
+	})
+}
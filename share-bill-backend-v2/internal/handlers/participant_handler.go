@@ -24,7 +24,7 @@ func (h *ParticipantHandler) ListParticipants(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	eventUUID := c.Params("eventId")
 
-	resp, err := h.service.ListParticipants(c.Context(), userID, eventUUID)
+	resp, err := h.service.ListParticipants(c.Context(), userID, eventUUID, c.QueryBool("includeRemoved"))
 	if err != nil {
 		return utils.MapError(c, err)
 	}
@@ -57,7 +57,7 @@ func (h *ParticipantHandler) AddVirtualParticipant(c *fiber.Ctx) error {
 }
 
 // PUT /api/v1/participants/:participantId
-// Cap nhat participant (owner hoac chu event cho guest)
+// Cap nhat participant (chinh minh, hoac admin tro len cho guest)
 func (h *ParticipantHandler) UpdateParticipant(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	partUUID := c.Params("participantId")
@@ -79,13 +79,13 @@ func (h *ParticipantHandler) UpdateParticipant(c *fiber.Ctx) error {
 	})
 }
 
-// DELETE /api/v1/participants/:participantId
-// Kick participant (chi creator, kiem tra balance)
+// DELETE /api/v1/participants/:participantId?reassignTo=<participantId>
+// Kick participant (admin tro len, xoa mem). Balance khac 0 thi phai chuyen cho reassignTo
 func (h *ParticipantHandler) KickParticipant(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	partUUID := c.Params("participantId")
 
-	err := h.service.KickParticipant(c.Context(), userID, partUUID)
+	err := h.service.KickParticipant(c.Context(), userID, partUUID, c.Query("reassignTo"))
 	if err != nil {
 		return utils.MapError(c, err)
 	}
@@ -94,4 +94,49 @@ func (h *ParticipantHandler) KickParticipant(c *fiber.Ctx) error {
 		Success: true,
 		Message: "Participant removed successfully",
 	})
-}
\ No newline at end of file
+}
+// PUT /api/v1/participants/:participantId/role
+// Doi role participant (chi owner cap/thu quyen admin)
+func (h *ParticipantHandler) ChangeRole(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	partUUID := c.Params("participantId")
+	var req models.ChangeRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+	resp, err := h.service.ChangeRole(c.Context(), userID, partUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Participant role updated successfully",
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/participants/:participantId/merge
+// Gop participant trung vao participant khac (admin tro len)
+func (h *ParticipantHandler) MergeParticipant(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	partUUID := c.Params("participantId")
+	var req models.MergeParticipantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+	resp, err := h.service.MergeParticipant(c.Context(), userID, partUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Participants merged successfully",
+		Data:    resp,
+	})
+}
@@ -2,9 +2,9 @@ package handlers
 
 import (
 	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
 	services "BACKEND/internal/services"
 	utils "BACKEND/internal/utils"
-	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -67,7 +67,7 @@ func (h *PaymentHandler) GetPaymentQR(c *fiber.Ctx) error {
 	receiverUUID := c.Query("receiverId") 
 	amountStr := c.Query("amount")        
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amount, err := money.Parse(amountStr)
 	if err != nil || amount <= 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
 			Error:   "INVALID_AMOUNT",
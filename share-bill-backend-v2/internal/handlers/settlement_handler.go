@@ -17,13 +17,16 @@ func NewSettlementHandler(s *services.SettlementService) *SettlementHandler {
 	return &SettlementHandler{service: s}
 } 
 
-// GET /api/v1/events/:eventId/summary
-// Tra balances va settlement plan
+// GET /api/v1/events/:eventId/summary?strategy=greedy|minimal|collector&pairwise=gross|net
+// Tra balances va settlement plan, kem bang no truc tiep giua tung cap neu co ?pairwise
 func (h *SettlementHandler) GetEventSummary(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(int64)
 	eventUUID := c.Params("eventId")
+	strategy := c.Query("strategy", services.SummaryStrategyGreedy)
 
-	resp, err := h.service.GetEventSummary(c.Context(), userID, eventUUID)
+	pairwise := c.Query("pairwise")
+
+	resp, err := h.service.GetEventSummary(c.Context(), userID, eventUUID, strategy, pairwise)
 	if err != nil {
 		return utils.MapError(c, err)
 	}
@@ -47,13 +50,136 @@ func (h *SettlementHandler) CreateSettlement(c *fiber.Ctx) error {
 		})
 	}
 
-	err := h.service.CreateSettlement(c.Context(), userID, eventUUID, req)
+	resp, err := h.service.CreateSettlement(c.Context(), userID, eventUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	message := "Settlement recorded successfully"
+	if resp.Status == "pending" {
+		message = "Settlement recorded, waiting for receiver confirmation"
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    resp,
+	})
+}
+// GET /api/v1/events/:eventId/settlements
+// Lich su settlement cua event
+func (h *SettlementHandler) ListSettlements(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	eventUUID := c.Params("eventId")
+
+	resp, err := h.service.ListSettlements(c.Context(), userID, eventUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/participants/:participantId/balance
+// Chi tiet cac expense/settlement tao nen balance cua 1 thanh vien
+func (h *SettlementHandler) GetParticipantBalance(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	participantUUID := c.Params("participantId")
+
+	resp, err := h.service.GetParticipantBalanceBreakdown(c.Context(), userID, participantUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GET /api/v1/settlements/:settlementId
+// Chi tiet mot settlement
+func (h *SettlementHandler) GetSettlement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	settlementUUID := c.Params("settlementId")
+
+	resp, err := h.service.GetSettlement(c.Context(), userID, settlementUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/settlements/:settlementId/reverse
+// Dao nguoc settlement bang but toan bu tru
+func (h *SettlementHandler) ReverseSettlement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	settlementUUID := c.Params("settlementId")
+
+	var req models.ReverseSettlementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+
+	resp, err := h.service.ReverseSettlement(c.Context(), userID, settlementUUID, req)
 	if err != nil {
 		return utils.MapError(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(models.SuccessResponse{
 		Success: true,
-		Message: "Settlement recorded successfully",
+		Message: "Settlement reversed successfully",
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/settlements/:settlementId/confirm
+// Nguoi nhan (hoac admin neu nguoi nhan la khach) xac nhan settlement
+func (h *SettlementHandler) ConfirmSettlement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	settlementUUID := c.Params("settlementId")
+
+	resp, err := h.service.ConfirmSettlement(c.Context(), userID, settlementUUID)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Settlement confirmed",
+		Data:    resp,
+	})
+}
+
+// POST /api/v1/settlements/:settlementId/reject
+// Tu choi settlement kem ly do
+func (h *SettlementHandler) RejectSettlement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	settlementUUID := c.Params("settlementId")
+
+	var req models.RejectSettlementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "INVALID_BODY", Message: "Invalid JSON format",
+		})
+	}
+
+	resp, err := h.service.RejectSettlement(c.Context(), userID, settlementUUID, req)
+	if err != nil {
+		return utils.MapError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SuccessResponse{
+		Success: true,
+		Message: "Settlement rejected",
+		Data:    resp,
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,96 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+var ErrInvalidWeights = errors.New("weights must be non-negative and not all zero")
+
+// WeightRat doi weight float64 sang phan so theo dung dang thap phan nguoi dung nhap (0.1 -> 1/10)
+func WeightRat(w float64) (*big.Rat, bool) {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(w, 'f', -1, 64))
+	return r, ok
+}
+
+// Allocate chia total theo weights bang phuong phap largest remainder.
+// Moi phan duoc lam tron theo currency va tong cac phan luon bang dung total.
+// Khi hoa (remainder bang nhau) thi uu tien phan tu dung truoc de ket qua on dinh.
+func Allocate(total Amount, weights []float64, currency string) ([]Amount, error) {
+	rats := make([]*big.Rat, len(weights))
+	for i, w := range weights {
+		r, ok := WeightRat(w)
+		if !ok {
+			return nil, ErrInvalidWeights
+		}
+		rats[i] = r
+	}
+	return AllocateRat(total, rats, currency)
+}
+
+// AllocateEqual chia deu total cho n phan
+func AllocateEqual(total Amount, n int, currency string) ([]Amount, error) {
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return Allocate(total, weights, currency)
+}
+
+// AllocateRat giong Allocate nhung nhan weights dang phan so chinh xac
+func AllocateRat(total Amount, weights []*big.Rat, currency string) ([]Amount, error) {
+	if len(weights) == 0 {
+		return nil, ErrInvalidWeights
+	}
+	sumW := new(big.Rat)
+	for _, w := range weights {
+		if w.Sign() < 0 {
+			return nil, ErrInvalidWeights
+		}
+		sumW.Add(sumW, w)
+	}
+	if sumW.Sign() == 0 {
+		return nil, ErrInvalidWeights
+	}
+
+	sign := int64(1)
+	if total < 0 {
+		sign = -1
+		total = -total
+	}
+	// Chia theo buoc cua currency; du lieu cu chua lam tron thi chia theo cent
+	step := Step(currency)
+	if total%step != 0 {
+		step = 1
+	}
+	units := int64(total / step)
+
+	type part struct {
+		idx int
+		rem *big.Rat
+	}
+	result := make([]Amount, len(weights))
+	parts := make([]part, len(weights))
+	allocated := int64(0)
+	for i, w := range weights {
+		exact := new(big.Rat).Mul(big.NewRat(units, 1), w)
+		exact.Quo(exact, sumW)
+		floor := new(big.Int).Quo(exact.Num(), exact.Denom())
+		result[i] = Amount(floor.Int64())
+		allocated += floor.Int64()
+		parts[i] = part{idx: i, rem: exact.Sub(exact, new(big.Rat).SetInt(floor))}
+	}
+
+	sort.SliceStable(parts, func(a, b int) bool {
+		return parts[a].rem.Cmp(parts[b].rem) > 0
+	})
+	for i := int64(0); i < units-allocated; i++ {
+		result[parts[i].idx]++
+	}
+	for i := range result {
+		result[i] = result[i] * step * Amount(sign)
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    Amount
+		weights  []float64
+		currency string
+		want     []Amount
+	}{
+		{"even", 900, []float64{1, 1, 1}, "USD", []Amount{300, 300, 300}},
+		// Hoa remainder thi phan tu dung truoc nhan cent du
+		{"tie first wins", 100, []float64{1, 1, 1}, "USD", []Amount{34, 33, 33}},
+		{"tie two extra", 200, []float64{1, 1, 1}, "USD", []Amount{67, 67, 66}},
+		{"largest remainder", 100, []float64{1, 2}, "USD", []Amount{33, 67}},
+		{"zero weight", 1000, []float64{0, 1, 3}, "USD", []Amount{0, 250, 750}},
+		{"fractional weights", 1000, []float64{0.1, 0.2, 0.7}, "USD", []Amount{100, 200, 700}},
+		// VND/JPY khong co so le: chia theo don vi 100 cent
+		{"vnd", FromUnits(100000), []float64{1, 1, 1}, "VND", []Amount{FromUnits(33334), FromUnits(33333), FromUnits(33333)}},
+		{"jpy", FromUnits(10), []float64{1, 1, 1}, "JPY", []Amount{FromUnits(4), FromUnits(3), FromUnits(3)}},
+		// Du lieu cu chua lam tron theo currency thi chia theo cent
+		{"vnd legacy cents", 1001, []float64{1, 1}, "VND", []Amount{501, 500}},
+		{"negative", -100, []float64{1, 1, 1}, "USD", []Amount{-34, -33, -33}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Allocate(tt.total, tt.weights, tt.currency)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Allocate = %v, want %v", got, tt.want)
+			}
+			if Sum(got) != tt.total {
+				t.Fatalf("sum = %d, want %d", Sum(got), tt.total)
+			}
+		})
+	}
+}
+
+func TestAllocateSumPreserved(t *testing.T) {
+	weights := []float64{3, 7, 11, 13, 0.5, 2.25}
+	for _, currency := range []string{"USD", "VND"} {
+		for total := Amount(0); total <= 5000; total += 37 {
+			total := total.Round(currency)
+			got, err := Allocate(total, weights, currency)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if Sum(got) != total {
+				t.Fatalf("%s total %d: sum = %d", currency, total, Sum(got))
+			}
+			for _, a := range got {
+				if !a.IsRounded(currency) {
+					t.Fatalf("%s total %d: part %d not rounded", currency, total, a)
+				}
+			}
+		}
+	}
+}
+
+func TestAllocateDeterministic(t *testing.T) {
+	weights := []*big.Rat{big.NewRat(1, 3), big.NewRat(1, 3), big.NewRat(1, 3), big.NewRat(1, 6)}
+	first, err := AllocateRat(1001, weights, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		got, _ := AllocateRat(1001, weights, "USD")
+		if !reflect.DeepEqual(got, first) {
+			t.Fatalf("run %d = %v, want %v", i, got, first)
+		}
+	}
+}
+
+func TestAllocateInvalidWeights(t *testing.T) {
+	for _, weights := range [][]float64{nil, {0, 0}, {1, -1}} {
+		if _, err := Allocate(100, weights, "USD"); !errors.Is(err, ErrInvalidWeights) {
+			t.Errorf("Allocate(%v) error = %v, want ErrInvalidWeights", weights, err)
+		}
+	}
+}
+
+func TestAllocateEqual(t *testing.T) {
+	got, err := AllocateEqual(1000, 3, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []Amount{334, 333, 333}) {
+		t.Fatalf("AllocateEqual = %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package money
+
+import (
+	"math/big"
+	"strings"
+)
+
+// So chu so le cua tung loai tien (ISO 4217). Mac dinh la 2.
+var minorUnits = map[string]int{
+	"VND": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"THB": 2,
+	"SGD": 2,
+	"CNY": 2,
+}
+
+// NormalizeCurrency chuan hoa ma currency ve dang viet hoa ("usd " -> "USD")
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// IsCurrencyCode kiem tra ma currency co dang ISO 4217 (3 chu cai)
+func IsCurrencyCode(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// MinorUnits tra ve so chu so le cua currency, toi da bang Scale
+func MinorUnits(currency string) int {
+	if n, ok := minorUnits[NormalizeCurrency(currency)]; ok {
+		return n
+	}
+	return Scale
+}
+
+// Step la buoc nho nhat (tinh bang cent) cua currency: VND = 100, USD = 1
+func Step(currency string) Amount {
+	step := Amount(1)
+	for i := MinorUnits(currency); i < Scale; i++ {
+		step *= 10
+	}
+	return step
+}
+
+// Round lam tron so tien ve don vi nho nhat cua currency (half away from zero)
+func (a Amount) Round(currency string) Amount {
+	step := Step(currency)
+	if step == 1 {
+		return a
+	}
+	v, _ := fromRat(big.NewRat(int64(a), int64(step)))
+	return v * step
+}
+
+// IsRounded kiem tra so tien da dung don vi nho nhat cua currency chua
+func (a Amount) IsRounded(currency string) bool {
+	return a%Step(currency) == 0
+}
+
+// Div chia deu so tien cho n va lam tron theo currency (dung cho so lieu trung binh)
+func (a Amount) Div(n int64, currency string) Amount {
+	if n == 0 {
+		return 0
+	}
+	v, _ := fromRat(big.NewRat(int64(a), n))
+	return v.Round(currency)
+}
+
+// MulRat nhan so tien voi mot phan so (vd 8/100 cho VAT 8%) va lam tron 1 lan theo currency
+func (a Amount) MulRat(r *big.Rat, currency string) (Amount, error) {
+	step := Step(currency)
+	v, err := fromRat(new(big.Rat).Mul(big.NewRat(int64(a), int64(step)), r))
+	if err != nil {
+		return 0, err
+	}
+	return v * step, nil
+}
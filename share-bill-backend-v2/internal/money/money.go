@@ -0,0 +1,192 @@
+// Package money chua kieu so tien thap phan chinh xac dung chung cho DTO, service va sqlc.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scale la so chu so thap phan luu trong DB (NUMERIC(14,2))
+const Scale = 2
+
+const centsPerUnit = 100
+
+var (
+	ErrInvalidAmount = errors.New("invalid money amount")
+	ErrOverflow      = errors.New("money amount out of range")
+)
+
+// Amount la so tien tinh theo don vi 1/100 (cent), khong bao gio di qua float64
+type Amount int64
+
+var bigCentsPerUnit = big.NewInt(centsPerUnit)
+
+// FromUnits tao Amount tu so nguyen don vi tien te (vd 50000 VND)
+func FromUnits(units int64) Amount {
+	return Amount(units * centsPerUnit)
+}
+
+// FromCents tao Amount tu so cent
+func FromCents(cents int64) Amount {
+	return Amount(cents)
+}
+
+// Parse doc chuoi thap phan ("12.5", "500000", "1e6"), lam tron half-up ve cent
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrInvalidAmount
+	}
+	return fromRat(r.Mul(r, new(big.Rat).SetInt(bigCentsPerUnit)))
+}
+
+// fromRat lam tron mot so cent dang phan so ve so nguyen gan nhat (half away from zero)
+func fromRat(r *big.Rat) (Amount, error) {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	m.Abs(m).Lsh(m, 1)
+	if m.Cmp(r.Denom()) >= 0 {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if !q.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return Amount(q.Int64()), nil
+}
+
+// Cents tra ve so cent
+func (a Amount) Cents() int64 {
+	return int64(a)
+}
+
+// Float64 chi dung cho hien thi/xap xi, khong dung de tinh toan
+func (a Amount) Float64() float64 {
+	return float64(a) / centsPerUnit
+}
+
+// Rat tra ve gia tri chinh xac dang phan so (don vi tien te)
+func (a Amount) Rat() *big.Rat {
+	return big.NewRat(int64(a), centsPerUnit)
+}
+
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+func (a Amount) Neg() Amount {
+	return -a
+}
+
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Min tra ve so nho hon
+func Min(a, b Amount) Amount {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Sum cong mot danh sach so tien
+func Sum(amounts []Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// String tra ve dang thap phan ngan gon: 500000, 12.5, -0.01
+func (a Amount) String() string {
+	sign := ""
+	v := int64(a)
+	if v < 0 {
+		sign = "-"
+	}
+	u := uint64(v)
+	if v < 0 {
+		u = uint64(-v)
+	}
+	whole := u / centsPerUnit
+	frac := u % centsPerUnit
+	if frac == 0 {
+		return sign + strconv.FormatUint(whole, 10)
+	}
+	fracStr := strings.TrimRight(fmt.Sprintf("%02d", frac), "0")
+	return sign + strconv.FormatUint(whole, 10) + "." + fracStr
+}
+
+// MarshalJSON ghi so tien duoi dang JSON number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON nhan ca JSON number lan chuoi ("12.50")
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		*a = 0
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// ScanNumeric cho phep pgx scan truc tiep cot NUMERIC vao Amount
+func (a *Amount) ScanNumeric(n pgtype.Numeric) error {
+	if !n.Valid {
+		*a = 0
+		return nil
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return ErrInvalidAmount
+	}
+	r := new(big.Rat).SetInt(n.Int)
+	exp := int64(n.Exp) + Scale
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(exp)), nil)
+	if exp >= 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(pow))
+	}
+	v, err := fromRat(r)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// NumericValue cho phep pgx ghi Amount vao cot NUMERIC
+func (a Amount) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(a)), Exp: -Scale, Valid: true}, nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
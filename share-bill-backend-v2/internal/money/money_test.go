@@ -0,0 +1,153 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  error
+	}{
+		{"500000", 50000000, nil},
+		{"12.5", 1250, nil},
+		{"12.50", 1250, nil},
+		{" 7 ", 700, nil},
+		{"1e6", 100000000, nil},
+		{"0.01", 1, nil},
+		// Lam tron half away from zero ve cent
+		{"0.004", 0, nil},
+		{"0.005", 1, nil},
+		{"-0.005", -1, nil},
+		{"-0.004", 0, nil},
+		{"2.675", 268, nil},
+		{"1/3", 33, nil},
+		{"", 0, ErrInvalidAmount},
+		{"   ", 0, ErrInvalidAmount},
+		{"abc", 0, ErrInvalidAmount},
+		{"1,5", 0, ErrInvalidAmount},
+		{"1e30", 0, ErrOverflow},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0"},
+		{50000000, "500000"},
+		{1250, "12.5"},
+		{1205, "12.05"},
+		{-1, "-0.01"},
+		{-1250, "-12.5"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var v struct {
+		A Amount `json:"a"`
+		B Amount `json:"b"`
+		C Amount `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a": 12.5, "b": "0.10", "c": null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != 1250 || v.B != 10 || v.C != 0 {
+		t.Fatalf("unmarshal = %+v", v)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"a":12.5,"b":0.1,"c":0}` {
+		t.Fatalf("marshal = %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"a": "x"}`), &v); err == nil {
+		t.Fatal("expected error for invalid amount")
+	}
+}
+
+func TestScanNumeric(t *testing.T) {
+	tests := []struct {
+		n    pgtype.Numeric
+		want Amount
+	}{
+		{pgtype.Numeric{Int: big.NewInt(1250), Exp: -2, Valid: true}, 1250},
+		{pgtype.Numeric{Int: big.NewInt(5), Exp: 4, Valid: true}, 5000000},
+		// Du lieu nhieu chu so le hon Scale thi lam tron ve cent
+		{pgtype.Numeric{Int: big.NewInt(12345), Exp: -3, Valid: true}, 1235},
+		{pgtype.Numeric{Valid: false}, 0},
+	}
+	for _, tt := range tests {
+		var a Amount
+		if err := a.ScanNumeric(tt.n); err != nil {
+			t.Fatalf("ScanNumeric(%v) error: %v", tt.n, err)
+		}
+		if a != tt.want {
+			t.Errorf("ScanNumeric(%v) = %d, want %d", tt.n, a, tt.want)
+		}
+	}
+	var a Amount
+	if err := a.ScanNumeric(pgtype.Numeric{NaN: true, Valid: true}); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("ScanNumeric(NaN) error = %v", err)
+	}
+	n, _ := Amount(-1205).NumericValue()
+	if err := a.ScanNumeric(n); err != nil || a != -1205 {
+		t.Errorf("NumericValue round trip = %d, %v", a, err)
+	}
+}
+
+func TestRoundByCurrency(t *testing.T) {
+	tests := []struct {
+		in       Amount
+		currency string
+		want     Amount
+	}{
+		{1250, "USD", 1250},
+		{1250, "VND", 1300},
+		{1249, "VND", 1200},
+		{-150, "VND", -200},
+		{1250, "jpy", 1300},
+		{1250, "XYZ", 1250},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Round(tt.currency); got != tt.want {
+			t.Errorf("Amount(%d).Round(%s) = %d, want %d", tt.in, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestMulRat(t *testing.T) {
+	// 10400 VND * 1/25000 = 0.416 USD -> 0.42
+	got, err := FromUnits(10400).MulRat(big.NewRat(1, 25000), "USD")
+	if err != nil || got != 42 {
+		t.Fatalf("MulRat = %d, %v", got, err)
+	}
+	// 0.01 USD * 250.01 = 2.5001 VND -> 3 VND (lam tron theo don vi VND)
+	got, err = FromCents(1).MulRat(big.NewRat(25001, 100), "VND")
+	if err != nil || got != FromUnits(3) {
+		t.Fatalf("MulRat VND = %d, %v", got, err)
+	}
+}
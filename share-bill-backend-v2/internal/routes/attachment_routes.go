@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"BACKEND/internal/handlers"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAttachmentRoutes(
+	app *fiber.App,
+	tokenMaker *utils.JWTMaker,
+	attachmentHandler *handlers.AttachmentHandler,
+) {
+	authMiddleware := middleware.NewAuthMiddleware(tokenMaker)
+	v1 := app.Group("/api/v1", authMiddleware)
+
+	transactions := v1.Group("/transactions")
+	// Tải hoá đơn lên (nhiều file)
+	transactions.Post("/:transactionId/attachments", attachmentHandler.UploadAttachments)
+	// Danh sách hoá đơn đính kèm
+	transactions.Get("/:transactionId/attachments", attachmentHandler.ListAttachments)
+	// Tải hoá đơn về (?thumbnail=true lấy ảnh thu nhỏ), chỉ thành viên nhóm
+	transactions.Get("/:transactionId/attachments/:attachmentId", attachmentHandler.DownloadAttachment)
+	// Xoá hoá đơn
+	transactions.Delete("/:transactionId/attachments/:attachmentId", attachmentHandler.DeleteAttachment)
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"BACKEND/internal/handlers"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupCategoryRoutes(
+	app *fiber.App,
+	tokenMaker *utils.JWTMaker,
+	categoryHandler *handlers.CategoryHandler,
+) {
+	authMiddleware := middleware.NewAuthMiddleware(tokenMaker)
+	v1 := app.Group("/api/v1", authMiddleware)
+
+	events := v1.Group("/events")
+	// Danh mục có sẵn và danh mục riêng của event
+	events.Get("/:eventId/categories", categoryHandler.ListCategories)
+	// Tạo danh mục riêng
+	events.Post("/:eventId/categories", categoryHandler.CreateCategory)
+	// Tổng chi theo danh mục của event và từng người
+	events.Get("/:eventId/categories/breakdown", categoryHandler.GetBreakdown)
+	// Đổi tên/icon danh mục riêng (admin trở lên)
+	events.Patch("/:eventId/categories/:categoryId", categoryHandler.UpdateCategory)
+	// Xoá danh mục riêng (admin trở lên)
+	events.Delete("/:eventId/categories/:categoryId", categoryHandler.DeleteCategory)
+	// Các tag đang dùng trong event
+	events.Get("/:eventId/tags", categoryHandler.ListTags)
+}
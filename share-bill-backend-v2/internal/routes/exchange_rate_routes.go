@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"BACKEND/internal/handlers"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupExchangeRateRoutes(
+	app *fiber.App,
+	tokenMaker *utils.JWTMaker,
+	exchangeRateHandler *handlers.ExchangeRateHandler,
+) {
+	authMiddleware := middleware.NewAuthMiddleware(tokenMaker)
+	v1 := app.Group("/api/v1", authMiddleware)
+
+	events := v1.Group("/events")
+	// Xem bảng tỷ giá của event
+	events.Get("/:eventId/exchange-rates", exchangeRateHandler.ListRates)
+	// Nhập tay tỷ giá (admin trở lên)
+	events.Put("/:eventId/exchange-rates", exchangeRateHandler.SetRates)
+	// Nạp tỷ giá từ file local
+	events.Post("/:eventId/exchange-rates/import", exchangeRateHandler.ImportRates)
+	// Xoá tỷ giá của 1 currency
+	events.Delete("/:eventId/exchange-rates/:currency", exchangeRateHandler.DeleteRate)
+}
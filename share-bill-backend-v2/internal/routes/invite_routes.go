@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"BACKEND/internal/handlers"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupInviteRoutes(
+	app *fiber.App,
+	tokenMaker *utils.JWTMaker,
+	inviteHandler *handlers.InviteHandler,
+) {
+	authMiddleware := middleware.NewAuthMiddleware(tokenMaker)
+	v1 := app.Group("/api/v1", authMiddleware)
+
+	// Tạo / xem link mời (admin trở lên)
+	events := v1.Group("/events")
+	events.Post("/:eventId/invites", inviteHandler.CreateInvite)
+	events.Get("/:eventId/invites", inviteHandler.ListInvites)
+
+	invites := v1.Group("/invites")
+	// Thu hồi link mời
+	invites.Post("/:inviteId/revoke", inviteHandler.RevokeInvite)
+	// Ảnh QR của link mời
+	invites.Get("/:inviteId/qr", inviteHandler.GetInviteQR)
+
+	// Tham gia nhóm bằng link mời
+	join := v1.Group("/join")
+	join.Get("/:token", inviteHandler.PreviewInvite)
+	join.Post("/:token", inviteHandler.JoinByInvite)
+}
@@ -61,6 +61,8 @@ func SetupRoutes(
 	users.Put("/profile", userHandler.UpdateProfile)
 	// Update avatar
 	users.Patch("/avatar", userHandler.UpdateAvatar)
+	// Xoá tài khoản
+	users.Delete("/profile", userHandler.DeleteAccount)
 	
 	// --- EVENT ---
 	events := v1.Group("/events")
@@ -68,30 +70,57 @@ func SetupRoutes(
 	events.Post("/", eventHandler.CreateEvent)
 	// List nhóm
 	events.Get("/", eventHandler.ListEvents)
+	// Thùng rác: nhóm đã xoá (đặt trước /:eventId)
+	events.Get("/trash", eventHandler.ListDeletedEvents)
 	// Chi tiết nhóm
 	events.Get("/:eventId", eventHandler.GetEvent)
 	// Sửa nhóm
 	events.Put("/:eventId", eventHandler.UpdateEvent)
-	// Xoá nhóm
+	// Xoá nhóm (vào thùng rác)
 	events.Delete("/:eventId", eventHandler.DeleteEvent)
-	// Tham gia
-	events.Post("/:eventId/join", eventHandler.JoinEvent)
+	// Khôi phục nhóm từ thùng rác
+	events.Post("/:eventId/restore", eventHandler.RestoreEvent)
 	// Rời nhóm
 	events.Post("/:eventId/leave", eventHandler.LeaveEvent)
+	// Đóng nhóm (chốt số dư cuối cùng)
+	events.Post("/:eventId/close", eventHandler.CloseEvent)
+	// Mở lại nhóm đã đóng
+	events.Post("/:eventId/reopen", eventHandler.ReopenEvent)
+	// Xem bản chốt số dư lần đóng gần nhất
+	events.Get("/:eventId/snapshot", eventHandler.GetEventSnapshot)
+	// Chuyển quyền owner (người nhận phải chấp nhận)
+	events.Post("/:eventId/ownership-transfer", eventHandler.TransferOwnership)
+	events.Get("/:eventId/ownership-transfer", eventHandler.GetOwnershipTransfer)
+	events.Post("/:eventId/ownership-transfer/accept", eventHandler.AcceptOwnershipTransfer)
+	events.Post("/:eventId/ownership-transfer/decline", eventHandler.DeclineOwnershipTransfer)
+	// Nhật ký hoạt động của nhóm
+	events.Get("/:eventId/activities", eventHandler.ListActivities)
 
 	// --- TRANSACTIONS (EXPENSE) ---
 	// Tạo chi tiêu
 	events.Post("/:eventId/transactions", expenseHandler.CreateTransaction)
 	// List chi tiêu của event
 	events.Get("/:eventId/transactions", expenseHandler.ListTransactions)
+	// Thùng rác chi tiêu của event
+	events.Get("/:eventId/transactions/trash", expenseHandler.ListDeletedTransactions)
 
 	transactions := v1.Group("/transactions")
 	// Lấy chi tiết chi tiêu
 	transactions.Get("/:transactionId", expenseHandler.GetTransaction)
+	// Lịch sử sửa đổi chi tiêu
+	transactions.Get("/:transactionId/history", expenseHandler.GetTransactionHistory)
 	// Cập nhật chi tiêu
 	transactions.Put("/:transactionId", expenseHandler.UpdateTransaction)
-	// Xoá chi tiêu
+	// Xoá chi tiêu (vào thùng rác)
 	transactions.Delete("/:transactionId", expenseHandler.DeleteTransaction)
+	// Khôi phục chi tiêu từ thùng rác
+	transactions.Post("/:transactionId/restore", expenseHandler.RestoreTransaction)
+	// Danh sách phiên bản của chi tiêu
+	transactions.Get("/:transactionId/revisions", expenseHandler.ListRevisions)
+	// Xem 1 phiên bản
+	transactions.Get("/:transactionId/revisions/:version", expenseHandler.GetRevision)
+	// Khôi phục chi tiêu về 1 phiên bản cũ
+	transactions.Post("/:transactionId/revisions/:version/restore", expenseHandler.RestoreRevision)
 
 	// --- PAYMENT ROUTES ---
 	// Chọn collector
@@ -108,6 +137,17 @@ func SetupRoutes(
 	events.Get("/:eventId/summary", settlementHandler.GetEventSummary)
 	// Ghi nhận trả nợ
 	events.Post("/:eventId/settlements", settlementHandler.CreateSettlement)
+	// Lịch sử trả nợ
+	events.Get("/:eventId/settlements", settlementHandler.ListSettlements)
+	settlements := v1.Group("/settlements")
+	// Chi tiết một lần trả nợ
+	settlements.Get("/:settlementId", settlementHandler.GetSettlement)
+	// Đảo ngược lần trả nợ ghi nhầm
+	settlements.Post("/:settlementId/reverse", settlementHandler.ReverseSettlement)
+	// Người nhận xác nhận đã nhận tiền
+	settlements.Post("/:settlementId/confirm", settlementHandler.ConfirmSettlement)
+	// Người nhận từ chối (kèm lý do)
+	settlements.Post("/:settlementId/reject", settlementHandler.RejectSettlement)
 
 	// --- PARTICIPANTS ---
 	// List thành viên
@@ -119,4 +159,10 @@ func SetupRoutes(
 	parts.Put("/:participantId", participantHandler.UpdateParticipant)
 	// Kick thành viên
 	parts.Delete("/:participantId", participantHandler.KickParticipant)
+	// Đổi quyền thành viên
+	parts.Put("/:participantId/role", participantHandler.ChangeRole)
+	// Gộp thành viên trùng
+	parts.Post("/:participantId/merge", participantHandler.MergeParticipant)
+	// Chi tiết số dư của thành viên (từng giao dịch góp vào)
+	parts.Get("/:participantId/balance", settlementHandler.GetParticipantBalance)
 }
\ No newline at end of file
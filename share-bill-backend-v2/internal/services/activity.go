@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	database "BACKEND/internal/db/sqlc"
+	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
+	utils "BACKEND/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Doi tuong cua activity
+const (
+	entityEvent          = "event"
+	entityExpense        = "expense"
+	entitySettlement     = "settlement"
+	entityParticipant    = "participant"
+	entityCollector      = "collector"
+	entityPaymentRequest = "payment_request"
+	entityCategory       = "category"
+)
+
+// So activity moi trang mac dinh va toi da
+const (
+	defaultActivityPageSize = 20
+	maxActivityPageSize     = 100
+)
+
+// Hanh dong ghi vao activity log (<entity>.<hanh dong>)
+const (
+	activityExpenseCreated           = "expense.created"
+	activityExpenseUpdated           = "expense.updated"
+	activityExpenseDeleted           = "expense.deleted"
+	activityExpenseRestored          = "expense.restored"
+	activityExpenseAttachmentAdded   = "expense.attachment_added"
+	activityExpenseAttachmentDeleted = "expense.attachment_deleted"
+	activityEventDeleted             = "event.deleted"
+	activityEventRestored            = "event.restored"
+	activityEventCurrencyChanged     = "event.currency_changed"
+	activityExchangeRatesSet         = "event.exchange_rates_set"
+	activityExchangeRateDeleted      = "event.exchange_rate_deleted"
+	activityCategoryCreated          = "category.created"
+	activityCategoryUpdated          = "category.updated"
+	activityCategoryDeleted          = "category.deleted"
+	activitySettlementCreated        = "settlement.created"
+	activitySettlementReversed       = "settlement.reversed"
+	activitySettlementConfirmed      = "settlement.confirmed"
+	activitySettlementRejected       = "settlement.rejected"
+	activityCollectorSet             = "collector.set"
+	activityParticipantAdded         = "participant.added"
+	activityParticipantJoined        = "participant.joined"
+	activityParticipantKicked        = "participant.kicked"
+	activityParticipantLeft          = "participant.left"
+	activityParticipantMerged        = "participant.merged"
+	activityParticipantRole          = "participant.role_changed"
+	activityPaymentRequestCreated    = "payment_request.created"
+	activityPaymentRequestConfirmed  = "payment_request.confirmed"
+	activityPaymentRequestCanceled   = "payment_request.canceled"
+)
+
+// Ghi 1 dong activity. Goi trong cung transaction voi thay doi de log khong bi lech
+func recordActivity(ctx context.Context, q database.Querier, eventID int64, actorID int64, action string, entityType string, entityUUID uuid.UUID, data any) error {
+	var raw []byte
+	if data != nil {
+		var err error
+		raw, err = json.Marshal(data)
+		if err != nil {
+			return err
+		}
+	}
+	return q.CreateActivity(ctx, database.CreateActivityParams{
+		EventID:    eventID,
+		ActorID:    &actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityUuid: entityUUID,
+		Data:       raw,
+	})
+}
+
+// Gia tri cu/moi cua 1 truong bi sua
+type fieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// So tien cua 1 participant trong expense
+type expenseShareEntry struct {
+	ParticipantID string       `json:"participantId"`
+	Name          string       `json:"name"`
+	Amount        money.Amount `json:"amount"`
+}
+
+// Trang thai expense tai 1 thoi diem, dung de so sanh truoc/sau khi sua
+type expenseSnapshot struct {
+	Description    string              `json:"description"`
+	TotalAmount    money.Amount        `json:"totalAmount"`
+	Currency       string              `json:"currency"`
+	OriginalAmount money.Amount        `json:"originalAmount"`
+	SplitMode      string              `json:"splitMode"`
+	Category       string              `json:"category,omitempty"`
+	Tags           []string            `json:"tags,omitempty"`
+	SpentAt        time.Time           `json:"spentAt"`
+	Payers         []expenseShareEntry `json:"payers"`
+	Shares         []expenseShareEntry `json:"shares"`
+}
+
+// Chup trang thai hien tai cua expense (payers + share cua tung nguoi)
+func loadExpenseSnapshot(ctx context.Context, q database.Querier, expense database.Expense) (expenseSnapshot, error) {
+	snap := expenseSnapshot{
+		Description:    expense.Description,
+		TotalAmount:    expense.TotalAmount,
+		Currency:       expense.Currency,
+		OriginalAmount: expense.OriginalAmount,
+		SplitMode:      expense.SplitMode,
+		SpentAt:        expense.SpentAt,
+		Payers:         []expenseShareEntry{},
+		Shares:         []expenseShareEntry{},
+	}
+	if expense.CategoryID != nil {
+		category, err := q.GetCategoryByID(ctx, *expense.CategoryID)
+		if err != nil {
+			return snap, err
+		}
+		snap.Category = category.Name
+	}
+	tags, err := q.ListExpenseTags(ctx, expense.ExpenseID)
+	if err != nil {
+		return snap, err
+	}
+	snap.Tags = tags
+	payers, err := q.GetExpensePayers(ctx, expense.ExpenseID)
+	if err != nil {
+		return snap, err
+	}
+	for _, p := range payers {
+		snap.Payers = append(snap.Payers, expenseShareEntry{
+			ParticipantID: p.ParticipantUuid.String(), Name: p.Name, Amount: p.PaidAmount,
+		})
+	}
+	bens, err := q.GetExpenseBeneficiaries(ctx, &expense.ExpenseID)
+	if err != nil {
+		return snap, err
+	}
+	for _, b := range bens {
+		snap.Shares = append(snap.Shares, expenseShareEntry{
+			ParticipantID: b.ParticipantUuid.String(), Name: b.Name, Amount: b.ShareAmount,
+		})
+	}
+	// Query khong co ORDER BY, sap xep de so sanh on dinh
+	for _, list := range [][]expenseShareEntry{snap.Payers, snap.Shares} {
+		sort.Slice(list, func(i, j int) bool { return list[i].ParticipantID < list[j].ParticipantID })
+	}
+	return snap, nil
+}
+
+// Chi giu cac truong thay doi giua 2 snapshot
+func diffExpenseSnapshots(before, after expenseSnapshot) map[string]fieldChange {
+	changes := make(map[string]fieldChange)
+	if before.Description != after.Description {
+		changes["description"] = fieldChange{From: before.Description, To: after.Description}
+	}
+	if before.TotalAmount != after.TotalAmount {
+		changes["totalAmount"] = fieldChange{From: before.TotalAmount, To: after.TotalAmount}
+	}
+	if before.Currency != after.Currency {
+		changes["currency"] = fieldChange{From: before.Currency, To: after.Currency}
+	}
+	if before.OriginalAmount != after.OriginalAmount {
+		changes["originalAmount"] = fieldChange{From: before.OriginalAmount, To: after.OriginalAmount}
+	}
+	if before.SplitMode != after.SplitMode {
+		changes["splitMode"] = fieldChange{From: before.SplitMode, To: after.SplitMode}
+	}
+	if before.Category != after.Category {
+		changes["category"] = fieldChange{From: before.Category, To: after.Category}
+	}
+	if !before.SpentAt.Equal(after.SpentAt) {
+		changes["spentAt"] = fieldChange{From: before.SpentAt, To: after.SpentAt}
+	}
+	if !reflect.DeepEqual(before.Tags, after.Tags) {
+		changes["tags"] = fieldChange{From: before.Tags, To: after.Tags}
+	}
+	if !reflect.DeepEqual(before.Payers, after.Payers) {
+		changes["payers"] = fieldChange{From: before.Payers, To: after.Payers}
+	}
+	if !reflect.DeepEqual(before.Shares, after.Shares) {
+		changes["shares"] = fieldChange{From: before.Shares, To: after.Shares}
+	}
+	return changes
+}
+
+// Chuyen 1 dong activity sang DTO
+func toActivityDTO(row database.ListEventActivitiesRow) models.ActivityDTO {
+	dto := models.ActivityDTO{
+		ID:         row.ActivityUuid.String(),
+		Action:     row.Action,
+		EntityType: row.EntityType,
+		EntityID:   row.EntityUuid.String(),
+		CreatedAt:  row.CreatedAt,
+	}
+	if len(row.Data) > 0 {
+		dto.Data = json.RawMessage(row.Data)
+	}
+	if row.ActorUuid.Valid {
+		dto.Actor = &models.ActivityActor{
+			ID:   row.ActorUuid.String(),
+			Name: utils.GetStringFromPointer(row.ActorName),
+		}
+	}
+	return dto
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	database "BACKEND/internal/db/sqlc"
+	models "BACKEND/internal/dto"
+	utils "BACKEND/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Gioi han file hoa don
+const (
+	maxAttachmentSize            = 5 * 1024 * 1024 // 5MB
+	maxAttachmentsPerTransaction = 10
+	attachmentFolder             = "sharever_receipts"
+)
+
+// Dinh dang hoa don nhan duoc, xac dinh theo noi dung file (khong tin extension/header cua client)
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// Noi dung file tra ve cho client, nguoi goi phai Close Body
+type AttachmentContent struct {
+	Body        io.ReadCloser
+	Size        int64 // -1 neu storage khong bao kich thuoc
+	ContentType string
+	FileName    string
+}
+
+type AttachmentService struct {
+	store   database.Store
+	uploads *UploadService // nil khi chua cau hinh cloudinary
+}
+
+// Khoi tao AttachmentService
+func NewAttachmentService(store database.Store, uploads *UploadService) *AttachmentService {
+	return &AttachmentService{store: store, uploads: uploads}
+}
+
+// File da kiem tra, san sang upload
+type receiptFile struct {
+	header      *multipart.FileHeader
+	contentType string
+}
+
+// Upload 1 hoac nhieu hoa don cho transaction (member tro len), tra ve toan bo file dinh kem sau khi upload
+func (s *AttachmentService) UploadAttachments(ctx context.Context, userID int64, transactionUUIDStr string, files []*multipart.FileHeader) ([]models.AttachmentDTO, error) {
+	if s.uploads == nil {
+		return nil, utils.ErrStorageUnavailable
+	}
+	expense, err := s.getExpense(ctx, transactionUUIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireRole(ctx, s.store, expense.EventID, userID, roleMember); err != nil {
+		return nil, err
+	}
+	event, err := s.store.GetEventByID(ctx, expense.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	if event.IsClosed {
+		return nil, utils.ErrEventClosed
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: at least one file is required", utils.ErrInvalidInput)
+	}
+	existing, err := s.store.CountExpenseAttachments(ctx, expense.ExpenseID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	if int(existing)+len(files) > maxAttachmentsPerTransaction {
+		return nil, fmt.Errorf("%w: a transaction can have at most %d attachments", utils.ErrInvalidInput, maxAttachmentsPerTransaction)
+	}
+	receipts := make([]receiptFile, 0, len(files))
+	for _, header := range files {
+		contentType, err := checkReceiptFile(header)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receiptFile{header: header, contentType: contentType})
+	}
+
+	// Upload len storage truoc, loi giua chung thi xoa cac file da len
+	folder := attachmentFolder + "/" + event.EventUuid.String()
+	stored := make([]StoredFile, 0, len(receipts))
+	for _, r := range receipts {
+		file, err := s.uploadReceipt(ctx, r.header, folder)
+		if err != nil {
+			s.deleteStoredFiles(ctx, stored)
+			return nil, fmt.Errorf("upload %s failed: %w", r.header.Filename, err)
+		}
+		stored = append(stored, file)
+	}
+
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		names := make([]string, 0, len(receipts))
+		for i, r := range receipts {
+			if _, err := q.CreateExpenseAttachment(ctx, database.CreateExpenseAttachmentParams{
+				ExpenseID:    expense.ExpenseID,
+				UploadedBy:   &userID,
+				FileName:     attachmentFileName(r.header.Filename),
+				ContentType:  r.contentType,
+				SizeBytes:    r.header.Size,
+				StorageKey:   stored[i].Key,
+				FileUrl:      stored[i].URL,
+				ThumbnailUrl: utils.StringToPtr(stored[i].ThumbnailURL),
+			}); err != nil {
+				return err
+			}
+			names = append(names, attachmentFileName(r.header.Filename))
+		}
+		return recordActivity(ctx, q, expense.EventID, userID, activityExpenseAttachmentAdded, entityExpense, expense.ExpenseUuid, map[string]any{"files": names})
+	})
+	if err != nil {
+		s.deleteStoredFiles(ctx, stored)
+		return nil, utils.ErrInternalDB
+	}
+	result, err := loadAttachmentDTOs(ctx, s.store, expense)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	return result, nil
+}
+
+// Danh sach file dinh kem cua transaction (moi thanh vien event)
+func (s *AttachmentService) ListAttachments(ctx context.Context, userID int64, transactionUUIDStr string) ([]models.AttachmentDTO, error) {
+	expense, err := s.getExpense(ctx, transactionUUIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireRole(ctx, s.store, expense.EventID, userID, roleViewer); err != nil {
+		return nil, err
+	}
+	result, err := loadAttachmentDTOs(ctx, s.store, expense)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	return result, nil
+}
+
+// Mo file (hoac anh thu nho) de tra ve cho client. Chi thanh vien event moi tai duoc
+func (s *AttachmentService) OpenAttachment(ctx context.Context, userID int64, transactionUUIDStr string, attachmentUUIDStr string, thumbnail bool) (AttachmentContent, error) {
+	expense, attachment, err := s.getAttachment(ctx, transactionUUIDStr, attachmentUUIDStr)
+	if err != nil {
+		return AttachmentContent{}, err
+	}
+	if _, err := requireRole(ctx, s.store, expense.EventID, userID, roleViewer); err != nil {
+		return AttachmentContent{}, err
+	}
+	if s.uploads == nil {
+		return AttachmentContent{}, utils.ErrStorageUnavailable
+	}
+	content := AttachmentContent{ContentType: attachment.ContentType, FileName: attachment.FileName}
+	url := attachment.FileUrl
+	if thumbnail {
+		if attachment.ThumbnailUrl == nil {
+			return AttachmentContent{}, utils.ErrNotFound
+		}
+		url = *attachment.ThumbnailUrl
+		content.ContentType = "image/jpeg"
+		content.FileName = strings.TrimSuffix(attachment.FileName, filepath.Ext(attachment.FileName)) + "-thumbnail.jpg"
+	}
+	content.Body, content.Size, err = s.uploads.OpenPrivateFile(ctx, url)
+	if err != nil {
+		return AttachmentContent{}, fmt.Errorf("download attachment failed: %w", err)
+	}
+	return content, nil
+}
+
+// Xoa file dinh kem: admin tro len hoac nguoi da upload
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID int64, transactionUUIDStr string, attachmentUUIDStr string) error {
+	expense, attachment, err := s.getAttachment(ctx, transactionUUIDStr, attachmentUUIDStr)
+	if err != nil {
+		return err
+	}
+	me, err := requireRole(ctx, s.store, expense.EventID, userID, roleMember)
+	if err != nil {
+		return err
+	}
+	if !hasRole(me.Role, roleAdmin) && (attachment.UploadedBy == nil || *attachment.UploadedBy != userID) {
+		return utils.ErrPermissionDenied
+	}
+	event, err := s.store.GetEventByID(ctx, expense.EventID)
+	if err != nil {
+		return utils.ErrInternalDB
+	}
+	if event.IsClosed {
+		return utils.ErrEventClosed
+	}
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		affected, err := q.DeleteExpenseAttachment(ctx, attachment.AttachmentID)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return utils.ErrNotFound
+		}
+		return recordActivity(ctx, q, expense.EventID, userID, activityExpenseAttachmentDeleted, entityExpense, expense.ExpenseUuid, map[string]any{"file": attachment.FileName})
+	})
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return err
+		}
+		return utils.ErrInternalDB
+	}
+	// Ban ghi da xoa, file tren storage xoa loi thi chi log (khong con ai tai duoc)
+	if s.uploads == nil {
+		log.Println("Storage disabled, attachment file kept:", attachment.StorageKey)
+		return nil
+	}
+	if err := s.uploads.DeletePrivateFile(ctx, attachment.StorageKey); err != nil {
+		log.Println("Delete attachment file failed:", attachment.StorageKey, err)
+	}
+	return nil
+}
+
+// Helper: transaction chua bi xoa theo uuid
+func (s *AttachmentService) getExpense(ctx context.Context, transactionUUIDStr string) (database.Expense, error) {
+	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
+	if err != nil {
+		return database.Expense{}, utils.ErrInvalidInput
+	}
+	expense, err := s.store.GetExpenseByUUID(ctx, txnUUID)
+	if err != nil {
+		return database.Expense{}, utils.ErrNotFound
+	}
+	return expense, nil
+}
+
+// Helper: file dinh kem phai thuoc dung transaction tren URL
+func (s *AttachmentService) getAttachment(ctx context.Context, transactionUUIDStr string, attachmentUUIDStr string) (database.Expense, database.ExpenseAttachment, error) {
+	expense, err := s.getExpense(ctx, transactionUUIDStr)
+	if err != nil {
+		return database.Expense{}, database.ExpenseAttachment{}, err
+	}
+	attachmentUUID, err := utils.StringToUUID(attachmentUUIDStr)
+	if err != nil {
+		return database.Expense{}, database.ExpenseAttachment{}, utils.ErrInvalidInput
+	}
+	attachment, err := s.store.GetExpenseAttachmentByUUID(ctx, attachmentUUID)
+	if err != nil || attachment.ExpenseID != expense.ExpenseID {
+		return database.Expense{}, database.ExpenseAttachment{}, utils.ErrNotFound
+	}
+	return expense, attachment, nil
+}
+
+// Helper: upload 1 file len storage voi public id ngau nhien (khong lo ten file that)
+func (s *AttachmentService) uploadReceipt(ctx context.Context, header *multipart.FileHeader, folder string) (StoredFile, error) {
+	file, err := header.Open()
+	if err != nil {
+		return StoredFile{}, err
+	}
+	defer file.Close()
+	return s.uploads.UploadPrivateFile(ctx, file, folder, uuid.NewString())
+}
+
+// Helper: don cac file da upload khi khong luu duoc ban ghi
+func (s *AttachmentService) deleteStoredFiles(ctx context.Context, files []StoredFile) {
+	for _, f := range files {
+		if err := s.uploads.DeletePrivateFile(ctx, f.Key); err != nil {
+			log.Println("Delete orphan attachment file failed:", f.Key, err)
+		}
+	}
+}
+
+// Helper: kiem tra kich thuoc va dinh dang file hoa don, tra ve content type theo noi dung file
+func checkReceiptFile(header *multipart.FileHeader) (string, error) {
+	if header.Size > maxAttachmentSize {
+		return "", fmt.Errorf("%w: %s is too large (max 5MB)", utils.ErrInvalidInput, header.Filename)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return "", fmt.Errorf("%w: unable to read %s", utils.ErrInvalidInput, header.Filename)
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("%w: unable to read %s", utils.ErrInvalidInput, header.Filename)
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !allowedAttachmentTypes[contentType] {
+		return "", fmt.Errorf("%w: %s is not a JPG, PNG, WEBP or PDF file", utils.ErrInvalidInput, header.Filename)
+	}
+	return contentType, nil
+}
+
+// Helper: chi giu ten file (bo duong dan client gui kem)
+func attachmentFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return "receipt"
+	}
+	return name
+}
+
+// Helper: danh sach file dinh kem cua transaction, url tro ve API tai file
+func loadAttachmentDTOs(ctx context.Context, q database.Querier, expense database.Expense) ([]models.AttachmentDTO, error) {
+	rows, err := q.ListExpenseAttachments(ctx, expense.ExpenseID)
+	if err != nil {
+		return nil, err
+	}
+	base := "/api/v1/transactions/" + expense.ExpenseUuid.String() + "/attachments/"
+	result := make([]models.AttachmentDTO, 0, len(rows))
+	for _, row := range rows {
+		dto := models.AttachmentDTO{
+			ID:           row.AttachmentUuid.String(),
+			FileName:     row.FileName,
+			ContentType:  row.ContentType,
+			Size:         row.SizeBytes,
+			URL:          base + row.AttachmentUuid.String(),
+			UploaderName: utils.GetStringFromPointer(row.UploaderName),
+			CreatedAt:    row.CreatedAt,
+		}
+		if row.ThumbnailUrl != nil {
+			dto.ThumbnailURL = dto.URL + "?thumbnail=true"
+		}
+		if row.UploaderUuid.Valid {
+			dto.UploaderID = row.UploaderUuid.String()
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
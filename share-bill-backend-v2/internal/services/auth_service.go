@@ -14,6 +14,7 @@ import (
 	models "BACKEND/internal/dto"
 	"BACKEND/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -222,6 +223,11 @@ func (s *UserService) RefreshTokens(ctx context.Context, refreshToken string) (s
 	if err != nil || storedUID != userID {
 		return "", "", utils.ErrUnauthorized
 	}
+	// Tai khoan da bi xoa
+	if _, err := s.store.GetUserByID(ctx, userID); err != nil {
+		_ = s.redisClient.DeleteRefreshToken(ctx, jti)
+		return "", "", utils.ErrUnauthorized
+	}
 
 	// Rotate: delete old and create new
 	_ = s.redisClient.DeleteRefreshToken(ctx, jti)
@@ -273,6 +279,66 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID int64, req model
 	return s.mapUserResponse(user), nil
 }
 
+// Xoa tai khoan: event user dang la owner duoc tu dong chuyen cho thanh vien co tai khoan
+// (admin truoc, roi theo thu tu tham gia). Participant cua user giu lai nhu khach de khong mat so du.
+func (s *UserService) DeleteAccount(ctx context.Context, userID int64, req models.DeleteAccountRequest) error {
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return utils.ErrNotFound
+	}
+	if err := utils.CheckPassword(req.Password, user.Password); err != nil {
+		return utils.ErrUnauthorized
+	}
+
+	return s.store.ExecTx(ctx, func(q *database.Queries) error {
+		events, err := q.ListOwnedEventsByUserID(ctx, &userID)
+		if err != nil {
+			return utils.ErrInternalDB
+		}
+		for _, event := range events {
+			owner, err := q.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+				EventID: event.EventID,
+				UserID:  &userID,
+			})
+			if err != nil {
+				return utils.ErrInternalDB
+			}
+			successor, err := q.GetOwnershipSuccessor(ctx, database.GetOwnershipSuccessorParams{
+				EventID: event.EventID,
+				UserID:  &userID,
+			})
+			switch {
+			case err == nil:
+				if err := handOverOwnership(ctx, q, event.EventID, owner, successor); err != nil {
+					return utils.ErrInternalDB
+				}
+			case errors.Is(err, pgx.ErrNoRows):
+				// Khong con ai co tai khoan: event khong co owner, creator_id ve NULL khi xoa user.
+				// Thu hoi invite de khong ai vao duoc event khong co nguoi quan ly
+				if err := q.CancelPendingOwnershipTransfers(ctx, event.EventID); err != nil {
+					return utils.ErrInternalDB
+				}
+				if err := q.RevokeEventInvites(ctx, event.EventID); err != nil {
+					return utils.ErrInternalDB
+				}
+			default:
+				return utils.ErrInternalDB
+			}
+			// Participant cu thanh khach, khong giu quyen quan ly
+			if _, err := q.UpdateParticipantRole(ctx, database.UpdateParticipantRoleParams{
+				ParticipantID: owner.ParticipantID,
+				Role:          roleMember,
+			}); err != nil {
+				return utils.ErrInternalDB
+			}
+		}
+		if err := q.DeleteUser(ctx, userID); err != nil {
+			return utils.ErrInternalDB
+		}
+		return nil
+	})
+}
+
 
 func (s *UserService) mapUserResponse(user database.User) models.UserResponse {
 	var bankInfo *models.BankInfoDTO
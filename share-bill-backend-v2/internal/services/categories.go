@@ -0,0 +1,446 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	database "BACKEND/internal/db/sqlc"
+	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
+	utils "BACKEND/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Gioi han cho danh muc rieng va tag
+const (
+	maxCategoryNameLength = 50
+	maxTagLength          = 32
+	maxTagsPerExpense     = 10
+)
+
+// Gia tri filter category de lay transaction chua phan loai
+const categoryFilterNone = "none"
+
+type CategoryService struct {
+	store database.Store
+}
+
+// Khoi tao CategoryService
+func NewCategoryService(store database.Store) *CategoryService {
+	return &CategoryService{store: store}
+}
+
+// Helper: tim danh muc theo ID hoac code cua danh muc co san. Bo trong thi tra ve nil (chua phan loai)
+func resolveCategory(ctx context.Context, q database.Querier, eventID int64, input string) (*database.ExpenseCategory, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+	var (
+		category database.ExpenseCategory
+		err      error
+	)
+	if categoryUUID, parseErr := utils.StringToUUID(input); parseErr == nil {
+		category, err = q.GetCategoryByUUID(ctx, categoryUUID)
+	} else {
+		category, err = q.GetBuiltinCategoryByCode(ctx, strings.ToLower(input))
+	}
+	// Danh muc rieng cua event khac coi nhu khong ton tai
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && category.EventID != nil && *category.EventID != eventID) {
+		return nil, fmt.Errorf("%w: category %q not found", utils.ErrInvalidInput, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Helper: id cua danh muc de luu vao expense, nil = chua phan loai
+func categoryID(category *database.ExpenseCategory) *int64 {
+	if category == nil {
+		return nil
+	}
+	return &category.CategoryID
+}
+
+// Helper: chuan hoa tag (bo #, chu thuong, bo trung), giu thu tu nguoi dung nhap
+func normalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, raw := range tags {
+		tag := normalizeTag(raw)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", utils.ErrInvalidInput, tag, maxTagLength)
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > maxTagsPerExpense {
+		return nil, fmt.Errorf("%w: at most %d tags per transaction", utils.ErrInvalidInput, maxTagsPerExpense)
+	}
+	return result, nil
+}
+
+// Helper: "#Da Lat " -> "da lat"
+func normalizeTag(raw string) string {
+	tag := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(raw), "#"))
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// Helper: ghi de toan bo tag cua expense
+func saveExpenseTags(ctx context.Context, q *database.Queries, expenseID int64, tags []string) error {
+	if err := q.DeleteExpenseTags(ctx, expenseID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := q.CreateExpenseTag(ctx, database.CreateExpenseTagParams{ExpenseID: expenseID, Tag: tag}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toCategoryDTO(c database.ExpenseCategory) models.CategoryDTO {
+	return models.CategoryDTO{
+		ID:      c.CategoryUuid.String(),
+		Code:    utils.GetStringFromPointer(c.Code),
+		Name:    c.Name,
+		Icon:    utils.GetStringFromPointer(c.Icon),
+		BuiltIn: c.EventID == nil,
+	}
+}
+
+// Helper: map category_id -> DTO cho moi danh muc event dung duoc
+func loadCategoryDTOs(ctx context.Context, q database.Querier, eventID int64) (map[int64]models.CategoryDTO, error) {
+	rows, err := q.ListEventCategories(ctx, &eventID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]models.CategoryDTO, len(rows))
+	for _, row := range rows {
+		result[row.CategoryID] = toCategoryDTO(row)
+	}
+	return result, nil
+}
+
+// Helper: DTO cua danh muc theo id, nil neu chua phan loai
+func categoryDTOByID(categories map[int64]models.CategoryDTO, id *int64) *models.CategoryDTO {
+	if id == nil {
+		return nil
+	}
+	dto, ok := categories[*id]
+	if !ok {
+		return nil
+	}
+	return &dto
+}
+
+// Danh muc co san va danh muc rieng cua event, moi thanh vien deu xem duoc
+func (s *CategoryService) ListCategories(ctx context.Context, userID int64, eventUUIDStr string) ([]models.CategoryDTO, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleViewer)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.store.ListEventCategories(ctx, &event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.CategoryDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toCategoryDTO(row))
+	}
+	return result, nil
+}
+
+// Tao danh muc rieng cho event (member tro len). Ten khong duoc trung danh muc da co
+func (s *CategoryService) CreateCategory(ctx context.Context, userID int64, eventUUIDStr string, req models.CreateCategoryRequest) (models.CategoryDTO, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleMember)
+	if err != nil {
+		return models.CategoryDTO{}, err
+	}
+	name, err := s.checkCategoryName(ctx, event.EventID, req.Name, 0)
+	if err != nil {
+		return models.CategoryDTO{}, err
+	}
+	var created database.ExpenseCategory
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		var err error
+		created, err = q.CreateEventCategory(ctx, database.CreateEventCategoryParams{
+			EventID:   &event.EventID,
+			Name:      name,
+			Icon:      utils.StringToPtr(strings.TrimSpace(req.Icon)),
+			CreatedBy: &userID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityCategoryCreated, entityCategory, created.CategoryUuid, map[string]any{
+			"name": created.Name,
+		})
+	})
+	if err != nil {
+		return models.CategoryDTO{}, utils.ErrInternalDB
+	}
+	return toCategoryDTO(created), nil
+}
+
+// Doi ten/icon danh muc rieng (admin tro len). Danh muc co san khong sua duoc
+func (s *CategoryService) UpdateCategory(ctx context.Context, userID int64, eventUUIDStr string, categoryUUIDStr string, req models.UpdateCategoryRequest) (models.CategoryDTO, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleAdmin)
+	if err != nil {
+		return models.CategoryDTO{}, err
+	}
+	category, err := s.getCustomCategory(ctx, event.EventID, categoryUUIDStr)
+	if err != nil {
+		return models.CategoryDTO{}, err
+	}
+	arg := database.UpdateEventCategoryParams{
+		CategoryID: category.CategoryID,
+		Name:       category.Name,
+		Icon:       category.Icon,
+	}
+	if req.Name != nil {
+		arg.Name, err = s.checkCategoryName(ctx, event.EventID, *req.Name, category.CategoryID)
+		if err != nil {
+			return models.CategoryDTO{}, err
+		}
+	}
+	if req.Icon != nil {
+		arg.Icon = utils.StringToPtr(strings.TrimSpace(*req.Icon))
+	}
+	var updated database.ExpenseCategory
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		var err error
+		updated, err = q.UpdateEventCategory(ctx, arg)
+		if err != nil {
+			return err
+		}
+		changes := make(map[string]fieldChange)
+		if category.Name != updated.Name {
+			changes["name"] = fieldChange{From: category.Name, To: updated.Name}
+		}
+		if utils.GetStringFromPointer(category.Icon) != utils.GetStringFromPointer(updated.Icon) {
+			changes["icon"] = fieldChange{From: utils.GetStringFromPointer(category.Icon), To: utils.GetStringFromPointer(updated.Icon)}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityCategoryUpdated, entityCategory, updated.CategoryUuid, map[string]any{
+			"changes": changes,
+		})
+	})
+	if err != nil {
+		return models.CategoryDTO{}, utils.ErrInternalDB
+	}
+	return toCategoryDTO(updated), nil
+}
+
+// Xoa danh muc rieng (admin tro len), transaction dang dung ve chua phan loai
+func (s *CategoryService) DeleteCategory(ctx context.Context, userID int64, eventUUIDStr string, categoryUUIDStr string) error {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleAdmin)
+	if err != nil {
+		return err
+	}
+	category, err := s.getCustomCategory(ctx, event.EventID, categoryUUIDStr)
+	if err != nil {
+		return err
+	}
+	return s.store.ExecTx(ctx, func(q *database.Queries) error {
+		affected, err := q.DeleteEventCategory(ctx, category.CategoryID)
+		if err != nil {
+			return utils.ErrInternalDB
+		}
+		if affected == 0 {
+			return utils.ErrNotFound
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityCategoryDeleted, entityCategory, category.CategoryUuid, map[string]any{
+			"name": category.Name,
+		})
+	})
+}
+
+// Cac tag dang dung trong event, dung de goi y khi nhap
+func (s *CategoryService) ListTags(ctx context.Context, userID int64, eventUUIDStr string) ([]models.TagDTO, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleViewer)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.store.ListEventTags(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.TagDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, models.TagDTO{Tag: row.Tag, Transactions: int(row.Transactions)})
+	}
+	return result, nil
+}
+
+// Tong chi theo danh muc cua ca event va phan cua tung participant.
+// participantUUIDStr khac rong thi chi tra ve participant do
+func (s *CategoryService) GetBreakdown(ctx context.Context, userID int64, eventUUIDStr string, participantUUIDStr string) (models.CategoryBreakdownResponse, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleViewer)
+	if err != nil {
+		return models.CategoryBreakdownResponse{}, err
+	}
+	categories, err := loadCategoryDTOs(ctx, s.store, event.EventID)
+	if err != nil {
+		return models.CategoryBreakdownResponse{}, utils.ErrInternalDB
+	}
+	participants, err := s.store.ListParticipantsByEventID(ctx, event.EventID)
+	if err != nil {
+		return models.CategoryBreakdownResponse{}, utils.ErrInternalDB
+	}
+	var onlyParticipant *int64
+	if participantUUIDStr != "" {
+		for _, p := range participants {
+			if p.ParticipantUuid.String() == participantUUIDStr {
+				id := p.ParticipantID
+				onlyParticipant = &id
+				break
+			}
+		}
+		if onlyParticipant == nil {
+			return models.CategoryBreakdownResponse{}, utils.ErrNotFound
+		}
+	}
+
+	totals, err := s.store.ListEventCategoryTotals(ctx, event.EventID)
+	if err != nil {
+		return models.CategoryBreakdownResponse{}, utils.ErrInternalDB
+	}
+	resp := models.CategoryBreakdownResponse{
+		Currency:     money.NormalizeCurrency(event.Currency),
+		Categories:   make([]models.CategoryTotalDTO, 0, len(totals)),
+		Participants: []models.ParticipantCategoryBreakdownDTO{},
+	}
+	for _, row := range totals {
+		resp.Total += row.Total
+		resp.Categories = append(resp.Categories, models.CategoryTotalDTO{
+			Category:     categoryDTOByID(categories, row.CategoryID),
+			Amount:       row.Total,
+			Transactions: int(row.Transactions),
+		})
+	}
+	sortCategoryTotals(resp.Categories)
+
+	shares, err := s.store.ListParticipantCategoryShares(ctx, event.EventID)
+	if err != nil {
+		return models.CategoryBreakdownResponse{}, utils.ErrInternalDB
+	}
+	byParticipant := make(map[int64]*models.ParticipantCategoryBreakdownDTO)
+	for _, row := range shares {
+		if row.ParticipantID == nil || (onlyParticipant != nil && *row.ParticipantID != *onlyParticipant) {
+			continue
+		}
+		entry, ok := byParticipant[*row.ParticipantID]
+		if !ok {
+			entry = &models.ParticipantCategoryBreakdownDTO{Categories: []models.CategoryTotalDTO{}}
+			byParticipant[*row.ParticipantID] = entry
+		}
+		entry.Total += row.Total
+		entry.Categories = append(entry.Categories, models.CategoryTotalDTO{
+			Category:     categoryDTOByID(categories, row.CategoryID),
+			Amount:       row.Total,
+			Transactions: int(row.Transactions),
+		})
+	}
+	// Giu thu tu participant trong event; participant chua co share van co mat khi loc theo participant
+	for _, p := range participants {
+		entry, ok := byParticipant[p.ParticipantID]
+		if !ok {
+			if onlyParticipant == nil || *onlyParticipant != p.ParticipantID {
+				continue
+			}
+			entry = &models.ParticipantCategoryBreakdownDTO{Categories: []models.CategoryTotalDTO{}}
+		}
+		entry.ID = p.ParticipantUuid.String()
+		entry.Name = p.Name
+		sortCategoryTotals(entry.Categories)
+		resp.Participants = append(resp.Participants, *entry)
+	}
+	return resp, nil
+}
+
+// Helper: danh muc nhieu tien truoc, bang nhau thi theo ten; chua phan loai xep cuoi
+func sortCategoryTotals(list []models.CategoryTotalDTO) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if (list[i].Category == nil) != (list[j].Category == nil) {
+			return list[j].Category == nil
+		}
+		if list[i].Amount != list[j].Amount {
+			return list[i].Amount > list[j].Amount
+		}
+		if list[i].Category == nil {
+			return false
+		}
+		return list[i].Category.Name < list[j].Category.Name
+	})
+}
+
+// Helper: kiem tra ten danh muc, khong trung (khong phan biet hoa thuong) voi danh muc khac trong event
+func (s *CategoryService) checkCategoryName(ctx context.Context, eventID int64, input string, selfID int64) (string, error) {
+	name := strings.Join(strings.Fields(input), " ")
+	if name == "" {
+		return "", fmt.Errorf("%w: category name is required", utils.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		return "", fmt.Errorf("%w: category name is longer than %d characters", utils.ErrInvalidInput, maxCategoryNameLength)
+	}
+	rows, err := s.store.ListEventCategories(ctx, &eventID)
+	if err != nil {
+		return "", utils.ErrInternalDB
+	}
+	for _, row := range rows {
+		if row.CategoryID != selfID && strings.EqualFold(row.Name, name) {
+			return "", fmt.Errorf("%w: category %q already exists", utils.ErrAlreadyExists, row.Name)
+		}
+	}
+	return name, nil
+}
+
+// Helper: lay danh muc rieng cua event, danh muc co san hoac cua event khac tra ve khong tim thay
+func (s *CategoryService) getCustomCategory(ctx context.Context, eventID int64, categoryUUIDStr string) (database.ExpenseCategory, error) {
+	categoryUUID, err := utils.StringToUUID(categoryUUIDStr)
+	if err != nil {
+		return database.ExpenseCategory{}, utils.ErrInvalidInput
+	}
+	category, err := s.store.GetCategoryByUUID(ctx, categoryUUID)
+	if err != nil {
+		return database.ExpenseCategory{}, utils.ErrNotFound
+	}
+	if category.EventID == nil {
+		return database.ExpenseCategory{}, fmt.Errorf("%w: built-in categories cannot be changed", utils.ErrPermissionDenied)
+	}
+	if *category.EventID != eventID {
+		return database.ExpenseCategory{}, utils.ErrNotFound
+	}
+	return category, nil
+}
+
+// Helper: lay event va kiem tra quyen; sua danh muc thi event phai con mo
+func (s *CategoryService) getEvent(ctx context.Context, userID int64, eventUUIDStr string, min string) (database.Event, error) {
+	eventUUID, err := utils.StringToUUID(eventUUIDStr)
+	if err != nil {
+		return database.Event{}, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUID)
+	if err != nil {
+		return database.Event{}, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, min); err != nil {
+		return database.Event{}, err
+	}
+	if min != roleViewer && event.IsClosed {
+		return database.Event{}, utils.ErrEventClosed
+	}
+	return event, nil
+}
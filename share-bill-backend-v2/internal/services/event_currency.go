@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	database "BACKEND/internal/db/sqlc"
+	"BACKEND/internal/money"
+	"BACKEND/internal/settlement"
+	utils "BACKEND/internal/utils"
+)
+
+const currencyRoundingReason = "rounding adjustment on currency change"
+
+// Helper: doi currency cua event sang to voi ty gia rate (1 currency cu = rate currency moi).
+// Quy doi expense (ca trong thung rac), settlement, payment request dang cho va bang ty gia.
+// Goi trong cung transaction voi UpdateEvent de khong bi lech giua chung
+func changeEventCurrency(ctx context.Context, q *database.Queries, event database.Event, to string, rate *big.Rat, userID int64) error {
+	from := money.NormalizeCurrency(event.Currency)
+
+	before, err := eventNetBalances(ctx, q, event.EventID)
+	if err != nil {
+		return err
+	}
+
+	expenses, err := q.ListEventExpensesForCurrencyChange(ctx, event.EventID)
+	if err != nil {
+		return err
+	}
+	for _, expense := range expenses {
+		if err := convertExpenseCurrency(ctx, q, expense, to, rate, userID); err != nil {
+			return err
+		}
+	}
+
+	settlements, err := q.ListEventSettlementAmounts(ctx, event.EventID)
+	if err != nil {
+		return err
+	}
+	for _, row := range settlements {
+		amount, err := row.Amount.MulRat(rate, to)
+		if err != nil {
+			return utils.ErrInvalidInput
+		}
+		if err := q.SetSettlementAmount(ctx, database.SetSettlementAmountParams{
+			SettlementID: row.SettlementID, Amount: amount,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Expense va settlement lam tron rieng nen so du co the lech vai xu, bu lai bang settlement da xac nhan
+	after, err := eventNetBalances(ctx, q, event.EventID)
+	if err != nil {
+		return err
+	}
+	adjustments, err := roundingAdjustments(before, after, to)
+	if err != nil {
+		return err
+	}
+	reason := currencyRoundingReason
+	for _, t := range adjustments {
+		payer, _ := strconv.ParseInt(t.From, 10, 64)
+		receiver, _ := strconv.ParseInt(t.To, 10, 64)
+		if _, err := q.CreateSettlement(ctx, database.CreateSettlementParams{
+			EventID:    event.EventID,
+			PayerID:    &payer,
+			ReceiverID: &receiver,
+			Amount:     t.Amount,
+			CreatedBy:  &userID,
+			Reason:     &reason,
+			Status:     settlementStatusConfirmed,
+			ResolvedBy: &userID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	requests, err := q.ListPendingPaymentRequestAmounts(ctx, event.EventID)
+	if err != nil {
+		return err
+	}
+	for _, row := range requests {
+		amount, err := row.Amount.MulRat(rate, to)
+		if err != nil {
+			return utils.ErrInvalidInput
+		}
+		if amount <= 0 {
+			return fmt.Errorf("%w: pending payment request of %s is too small to convert to %s", utils.ErrInvalidInput, row.Amount, to)
+		}
+		if err := q.SetPaymentRequestAmount(ctx, database.SetPaymentRequestAmountParams{
+			PaymentRequestID: row.PaymentRequestID, Amount: amount,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := rebaseExchangeRates(ctx, q, event.EventID, from, to, rate, userID); err != nil {
+		return err
+	}
+
+	rateValue, _ := rate.Float64()
+	return recordActivity(ctx, q, event.EventID, userID, activityEventCurrencyChanged, entityEvent, event.EventUuid, map[string]any{
+		"from":            from,
+		"to":              to,
+		"rate":            rateValue,
+		"expenses":        len(expenses),
+		"settlements":     len(settlements),
+		"adjustments":     len(adjustments),
+		"paymentRequests": len(requests),
+	})
+}
+
+// Helper: so du rong cua tung participant (da tinh settlement da xac nhan), ID la participant_id
+func eventNetBalances(ctx context.Context, q *database.Queries, eventID int64) ([]settlement.Balance, error) {
+	rows, err := q.GetEventBalances(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]settlement.Balance, len(rows))
+	for i, row := range rows {
+		balances[i] = settlement.Balance{
+			ID:     strconv.FormatInt(row.ParticipantID, 10),
+			Amount: (row.TotalPaid - row.TotalShare) + (row.TotalSettledSent - row.TotalSettledReceived),
+		}
+	}
+	return balances, nil
+}
+
+// Helper: settlement bu lam tron sau khi doi currency. Nguoi co so du 0 truoc khi doi phai giu dung 0,
+// phan chenh lech chia cho nhung nguoi con so du theo ty le so du cu
+func roundingAdjustments(before []settlement.Balance, after []settlement.Balance, currency string) ([]settlement.Transfer, error) {
+	settled := make(map[string]bool, len(before))
+	var open []settlement.Balance
+	for _, b := range before {
+		if b.Amount.IsZero() {
+			settled[b.ID] = true
+		} else {
+			open = append(open, b)
+		}
+	}
+
+	// Cung quy uoc voi planner: duong la phai nhan lai (so du bi du), am la phai tra them
+	residual := make([]settlement.Balance, 0, len(after))
+	var drift money.Amount
+	for _, a := range after {
+		if settled[a.ID] {
+			residual = append(residual, settlement.Balance{ID: a.ID, Amount: a.Amount})
+			drift += a.Amount
+		}
+	}
+	if !drift.IsZero() && len(open) > 0 {
+		weights := make([]*big.Rat, len(open))
+		for i, b := range open {
+			weights[i] = big.NewRat(b.Amount.Abs().Cents(), 1)
+		}
+		parts, err := money.AllocateRat(-drift, weights, currency)
+		if err != nil {
+			return nil, utils.ErrInvalidInput
+		}
+		for i, b := range open {
+			residual = append(residual, settlement.Balance{ID: b.ID, Amount: parts[i]})
+		}
+	}
+
+	return settlement.Greedy(residual), nil
+}
+
+// Helper: tinh lai so tien da quy doi cua 1 expense tu so tien goc.
+// Payer/share chia lai theo ty le so tien goc nen tong van bang total
+func convertExpenseCurrency(ctx context.Context, q *database.Queries, expense database.Expense, to string, rate *big.Rat, userID int64) error {
+	conv := currencyConversion{
+		currency:      money.NormalizeCurrency(expense.Currency),
+		eventCurrency: to,
+		rate:          big.NewRat(1, 1),
+	}
+	if !conv.same() {
+		combined := new(big.Rat).Mul(numericRat(expense.ExchangeRate), rate)
+		conv.rate, _ = new(big.Rat).SetString(combined.FloatString(exchangeRateScale))
+		if conv.rate.Sign() <= 0 {
+			return fmt.Errorf("%w: exchange rate for %s becomes too small", utils.ErrInvalidInput, conv.currency)
+		}
+	}
+	total, err := conv.convert(expense.OriginalAmount)
+	if err != nil {
+		return err
+	}
+
+	payers, err := q.ListExpensePayerOriginals(ctx, expense.ExpenseID)
+	if err != nil {
+		return err
+	}
+	paid := make([]money.Amount, len(payers))
+	for i, p := range payers {
+		paid[i] = p.OriginalAmount
+	}
+	paid, err = conv.convertParts(expense.OriginalAmount, paid)
+	if err != nil {
+		return err
+	}
+	for i, p := range payers {
+		if err := q.SetExpensePayerAmount(ctx, database.SetExpensePayerAmountParams{
+			PayerID: p.PayerID, PaidAmount: paid[i],
+		}); err != nil {
+			return err
+		}
+	}
+
+	bens, err := q.ListExpenseBeneficiaryOriginals(ctx, &expense.ExpenseID)
+	if err != nil {
+		return err
+	}
+	shares := make([]money.Amount, len(bens))
+	for i, b := range bens {
+		shares[i] = b.OriginalShare
+	}
+	shares, err = conv.convertParts(expense.OriginalAmount, shares)
+	if err != nil {
+		return err
+	}
+	for i, b := range bens {
+		if err := q.SetExpenseBeneficiaryShare(ctx, database.SetExpenseBeneficiaryShareParams{
+			BeneficiaryID: b.BeneficiaryID, ShareAmount: shares[i],
+		}); err != nil {
+			return err
+		}
+	}
+
+	converted, err := q.ConvertExpense(ctx, database.ConvertExpenseParams{
+		ExpenseID:    expense.ExpenseID,
+		TotalAmount:  total,
+		ExchangeRate: ratNumeric(conv.rate),
+	})
+	if err != nil {
+		return err
+	}
+	// Version moi sau khi quy doi cung co revision nhu 1 lan sua
+	if !converted.DeletedAt.Valid {
+		if _, err := saveExpenseRevision(ctx, q, converted, &userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Helper: bang ty gia tinh lai theo currency moi; currency cu tro thanh 1 dong ty gia
+func rebaseExchangeRates(ctx context.Context, q *database.Queries, eventID int64, from string, to string, rate *big.Rat, userID int64) error {
+	rows, err := q.ListEventExchangeRates(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.Currency == to {
+			if _, err := q.DeleteEventExchangeRate(ctx, database.DeleteEventExchangeRateParams{
+				EventID: eventID, Currency: row.Currency,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		rebased := new(big.Rat).Mul(numericRat(row.Rate), rate)
+		rebased, _ = new(big.Rat).SetString(rebased.FloatString(exchangeRateScale))
+		if rebased.Sign() <= 0 {
+			return fmt.Errorf("%w: exchange rate for %s becomes too small", utils.ErrInvalidInput, row.Currency)
+		}
+		if _, err := q.UpsertEventExchangeRate(ctx, database.UpsertEventExchangeRateParams{
+			EventID:   eventID,
+			Currency:  row.Currency,
+			Rate:      ratNumeric(rebased),
+			Source:    row.Source,
+			UpdatedBy: &userID,
+		}); err != nil {
+			return err
+		}
+	}
+	if !money.IsCurrencyCode(from) {
+		return nil
+	}
+	inverse := new(big.Rat).Inv(rate)
+	inverse, _ = new(big.Rat).SetString(inverse.FloatString(exchangeRateScale))
+	if inverse.Sign() <= 0 {
+		return nil
+	}
+	_, err = q.UpsertEventExchangeRate(ctx, database.UpsertEventExchangeRateParams{
+		EventID:   eventID,
+		Currency:  from,
+		Rate:      ratNumeric(inverse),
+		Source:    rateSourceManual,
+		UpdatedBy: &userID,
+	})
+	return err
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"BACKEND/internal/money"
+	"BACKEND/internal/settlement"
+)
+
+// Helper: so du sau khi ap dung cac settlement bu lam tron
+func applyTransfers(bs []settlement.Balance, transfers []settlement.Transfer) map[string]money.Amount {
+	left := make(map[string]money.Amount, len(bs))
+	for _, b := range bs {
+		left[b.ID] += b.Amount
+	}
+	for _, tr := range transfers {
+		left[tr.From] += tr.Amount
+		left[tr.To] -= tr.Amount
+	}
+	return left
+}
+
+// 3 bill 10.400 VND A tra het, chia doi cho A va B; B da tra A 15.600 VND.
+// Doi sang USD: share thanh 0,21 x 3 = 0,63 nhung settlement chi con 0,62
+func TestRoundingAdjustmentsKeepsSettledAtZero(t *testing.T) {
+	conv := currencyConversion{currency: "VND", eventCurrency: "USD", rate: big.NewRat(1, 25000)}
+	bill := money.FromUnits(10400)
+	half := money.FromUnits(5200)
+	transfer := money.FromUnits(15600)
+
+	before := []settlement.Balance{
+		{ID: "1", Amount: 3*bill - 3*half - transfer},
+		{ID: "2", Amount: -3*half + transfer},
+	}
+
+	var paidA, shareA, shareB money.Amount
+	for i := 0; i < 3; i++ {
+		paid, err := conv.convertParts(bill, []money.Amount{bill})
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares, err := conv.convertParts(bill, []money.Amount{half, half})
+		if err != nil {
+			t.Fatal(err)
+		}
+		paidA += paid[0]
+		shareA += shares[0]
+		shareB += shares[1]
+	}
+	settled, err := conv.convert(transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := []settlement.Balance{
+		{ID: "1", Amount: paidA - shareA - settled},
+		{ID: "2", Amount: -shareB + settled},
+	}
+	if after[0].Amount.IsZero() {
+		t.Fatalf("expected rounding drift, got %v", after)
+	}
+
+	adjustments, err := roundingAdjustments(before, after, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []settlement.Transfer{{From: "2", To: "1", Amount: money.FromCents(1)}}
+	if len(adjustments) != 1 || adjustments[0] != want[0] {
+		t.Fatalf("got %v, want %v", adjustments, want)
+	}
+	for id, a := range applyTransfers(after, adjustments) {
+		if !a.IsZero() {
+			t.Fatalf("%s left with %s", id, a)
+		}
+	}
+}
+
+func TestRoundingAdjustments(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		before   []int64
+		after    []int64
+		// So du mong doi sau khi bu, theo thu tu ID
+		want []int64
+		n    int
+	}{
+		{"no drift", "USD", []int64{0, 500, -500}, []int64{0, 2, -2}, []int64{0, 2, -2}, 0},
+		{"settled drift absorbed by open", "USD", []int64{0, 3000, -3000}, []int64{1, 10, -11}, []int64{0, 11, -11}, 1},
+		{"split by old balance", "USD", []int64{0, 0, 2000, -1000, -1000}, []int64{-2, 0, 80, -39, -39}, []int64{0, 0, 79, -40, -39}, 2},
+		{"all settled", "USD", []int64{0, 0, 0}, []int64{1, -2, 1}, []int64{0, 0, 0}, 2},
+		{"vnd steps", "VND", []int64{0, 100000, -100000}, []int64{-100, 500, -400}, []int64{0, 400, -400}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := make([]settlement.Balance, len(tt.before))
+			after := make([]settlement.Balance, len(tt.after))
+			for i := range tt.before {
+				id := string(rune('A' + i))
+				before[i] = settlement.Balance{ID: id, Amount: money.FromCents(tt.before[i])}
+				after[i] = settlement.Balance{ID: id, Amount: money.FromCents(tt.after[i])}
+			}
+			adjustments, err := roundingAdjustments(before, after, tt.currency)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(adjustments) != tt.n {
+				t.Fatalf("got %d adjustments %v, want %d", len(adjustments), adjustments, tt.n)
+			}
+			left := applyTransfers(after, adjustments)
+			for i, w := range tt.want {
+				id := string(rune('A' + i))
+				if left[id] != money.FromCents(w) {
+					t.Fatalf("%s: got %d, want %d", id, left[id].Cents(), w)
+				}
+			}
+		})
+	}
+}
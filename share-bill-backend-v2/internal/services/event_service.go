@@ -3,21 +3,41 @@ package services
 import (
 	database "BACKEND/internal/db/sqlc"
 	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
 	utils "BACKEND/internal/utils"
 	"context"
+	"encoding/json"
+	"math/big"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Trang thai event, dong bo voi events.is_closed
+const (
+	eventStatusActive = "active"
+	eventStatusClosed = "closed"
 )
 
 type EventService struct {
-	store database.Store
+	store          database.Store
+	trashRetention time.Duration
 }
 
-func NewEventService(store database.Store) *EventService {
-	return &EventService{store: store}
+func NewEventService(store database.Store, retention time.Duration) *EventService {
+	return &EventService{store: store, trashRetention: trashRetention(retention)}
 }
 
 func (s *EventService) CreateEvent(ctx context.Context, userID int64, req models.CreateEventRequest) (models.EventDetailResponse, error) {
+	timezone, err := normalizeTimezone(req.Timezone)
+	if err != nil {
+		return models.EventDetailResponse{}, err
+	}
 	var resp models.EventDetailResponse
-	err := s.store.ExecTx(ctx, func(q *database.Queries) error {
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
 		// Tạo event
 		var description *string
 		if req.Description != "" {
@@ -27,6 +47,7 @@ func (s *EventService) CreateEvent(ctx context.Context, userID int64, req models
 			Name: req.Name,
 			Currency: req.Currency,
 			CreatorID: &userID,
+			Timezone: timezone,
 			Description: description,
 		}
 		event, err := s.store.CreateEvent(ctx, argEvent)
@@ -42,6 +63,7 @@ func (s *EventService) CreateEvent(ctx context.Context, userID int64, req models
 			EventID: event.EventID,
 			Name: creator.Name,
 			UserID: &userID,
+			Role: roleOwner,
 			BankName: creator.BankName,
 			BankAccount: creator.BankAccount,
 			BankOwner: creator.BankOwner,
@@ -57,6 +79,7 @@ func (s *EventService) CreateEvent(ctx context.Context, userID int64, req models
 				Name:        event.Name,
 				Description: utils.GetStringFromPointer(event.Description),
 				Currency:    event.Currency,
+				Timezone:    event.Timezone,
 				Status:      utils.GetStringFromPointer(event.Status),
 				CreatedBy: models.CreatorDTO{
 					ID:   creator.UserUuid.String(),
@@ -103,17 +126,9 @@ func (s *EventService) GetEvent(ctx context.Context, userID int64, eventUUID str
 		return models.EventDetailResponse{}, utils.ErrPermissionDenied
 	}
 
-	creator, err := s.store.GetUserByID(ctx, *event.CreatorID)
-	if err != nil {
-		return models.EventDetailResponse{}, utils.ErrNotFound
-	}
-
-	totalExpFloat := utils.NumericToFloat(event.TotalExpenses) 
+	totalExp := event.TotalExpenses
     totalPartInt := int(event.TotalParticipants) 
-    var average float64
-    if totalPartInt > 0 {
-        average = totalExpFloat / float64(totalPartInt)
-    } 
+    average := totalExp.Div(int64(totalPartInt), event.Currency)
 
 	return models.EventDetailResponse{
         Event: models.EventInfoDTO{
@@ -121,18 +136,16 @@ func (s *EventService) GetEvent(ctx context.Context, userID int64, eventUUID str
             Name:        event.Name,
             Description: utils.GetStringFromPointer(event.Description),
 			Currency:    event.Currency,
+			Timezone:    event.Timezone,
 			Status:      utils.GetStringFromPointer(event.Status),
-			CreatedBy: models.CreatorDTO{
-				ID:   creator.UserUuid.String(),
-				Name: creator.Name,
-			},
+			CreatedBy: s.eventCreator(ctx, event),
 			CreatedAt: event.CreatedAt.Time,
 			UpdatedAt: event.LastUpdatedAt.Time,
         },
         Stats: models.EventStatsDTO{
             TotalParticipants: totalPartInt,
             TotalTransactions: int(event.TotalTransactions),
-            TotalExpenses:     totalExpFloat,
+            TotalExpenses:     totalExp,
             AveragePerPerson:  average,
         },
     }, nil
@@ -141,7 +154,7 @@ func (s *EventService) GetEvent(ctx context.Context, userID int64, eventUUID str
 
 func (s *EventService) UpdateEvent(ctx context.Context, userID int64, eventUUID string, req models.UpdateEventRequest) (models.EventDetailResponse, error) {
     // Logic:
-    // - Check xem userID có phải CreatorID của event không
+    // - Check xem user có quyền admin trở lên không
     // - Gọi s.store.UpdateEvent
 	eventUUIDType, err := utils.StringToUUID(eventUUID)
 	if err != nil {
@@ -151,52 +164,102 @@ func (s *EventService) UpdateEvent(ctx context.Context, userID int64, eventUUID
 	if err != nil {
 		return models.EventDetailResponse{}, utils.ErrNotFound
 	}
-	if *event.CreatorID != userID{
-		return models.EventDetailResponse{}, utils.ErrPermissionDenied
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleAdmin); err != nil {
+		return models.EventDetailResponse{}, err
+	}
+	if event.IsClosed {
+		return models.EventDetailResponse{}, utils.ErrEventClosed
+	}
+	if req.Status != nil {
+		return models.EventDetailResponse{}, fmt.Errorf("%w: use close/reopen to change event status", utils.ErrInvalidInput)
 	}
 
 	arg := database.UpdateEventParams{
 		EventID: event.EventID,
 		Name: req.Name,
 		Description: req.Description,
-		Status: req.Status,
 	}
-	updatedEvent, err := s.store.UpdateEvent(ctx, arg)
+	if req.Timezone != nil {
+		timezone, err := normalizeTimezone(*req.Timezone)
+		if err != nil {
+			return models.EventDetailResponse{}, err
+		}
+		arg.Timezone = &timezone
+	}
+	// Doi currency thi quy doi toan bo du lieu cu theo ty gia nguoi goi dua vao
+	var conversionRate *big.Rat
+	if req.Currency != nil {
+		currency := money.NormalizeCurrency(*req.Currency)
+		if !money.IsCurrencyCode(currency) {
+			return models.EventDetailResponse{}, fmt.Errorf("%w: invalid currency %q", utils.ErrInvalidInput, *req.Currency)
+		}
+		if currency != money.NormalizeCurrency(event.Currency) {
+			if req.ConversionRate == nil {
+				return models.EventDetailResponse{}, fmt.Errorf("%w: conversionRate is required to change the event currency", utils.ErrInvalidInput)
+			}
+			conversionRate, err = parseExchangeRate(*req.ConversionRate)
+			if err != nil {
+				return models.EventDetailResponse{}, err
+			}
+			arg.Currency = &currency
+		}
+	}
+
+	var updatedEvent database.Event
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		if arg.Currency != nil {
+			if err := changeEventCurrency(ctx, q, event, *arg.Currency, conversionRate, userID); err != nil {
+				return err
+			}
+		}
+		var err error
+		updatedEvent, err = q.UpdateEvent(ctx, arg)
+		return err
+	})
 	if err != nil {
 		return models.EventDetailResponse{}, err
 	}
 
-	totalExpFloat := utils.NumericToFloat(updatedEvent.TotalExpenses) 
+	totalExp := updatedEvent.TotalExpenses
     totalPartInt := int(updatedEvent.TotalParticipants) 
-    var average float64
-    if totalPartInt > 0 {
-        average = totalExpFloat / float64(totalPartInt)
-    } 
+    average := totalExp.Div(int64(totalPartInt), updatedEvent.Currency)
 	
-	creator, _ := s.store.GetUserByID(ctx, userID)
 	return models.EventDetailResponse{
 		Event: models.EventInfoDTO{
 			ID:          updatedEvent.EventUuid.String(),
 			Name:        updatedEvent.Name,
 			Description: utils.GetStringFromPointer(updatedEvent.Description),
 			Currency:    updatedEvent.Currency,
+			Timezone:    updatedEvent.Timezone,
 			Status:      utils.GetStringFromPointer(updatedEvent.Status),
-            CreatedBy: models.CreatorDTO{
-				ID:   creator.UserUuid.String(),
-				Name: creator.Name,
-			},
+            CreatedBy:   s.eventCreator(ctx, updatedEvent),
 			CreatedAt:   updatedEvent.CreatedAt.Time,
 			UpdatedAt:   updatedEvent.LastUpdatedAt.Time,
 		},
 		Stats: models.EventStatsDTO{
 			TotalParticipants: totalPartInt,
 			TotalTransactions: int(updatedEvent.TotalTransactions),
-			TotalExpenses:     totalExpFloat,
+			TotalExpenses:     totalExp,
 			AveragePerPerson:  average,
 		},
 	}, nil
 }
 
+// Helper: owner cua event. creator_id NULL (owner xoa tai khoan, khong co nguoi ke nhiem) thi de trong
+func (s *EventService) eventCreator(ctx context.Context, event database.Event) models.CreatorDTO {
+	if event.CreatorID == nil {
+		return models.CreatorDTO{}
+	}
+	creator, err := s.store.GetUserByID(ctx, *event.CreatorID)
+	if err != nil {
+		return models.CreatorDTO{}
+	}
+	return models.CreatorDTO{
+		ID:   creator.UserUuid.String(),
+		Name: creator.Name,
+	}
+}
+
 func (s *EventService) ListEvents(ctx context.Context, userID int64) ([]models.EventInfoDTO, error) {
     // Logic:
     // - Gọi s.store.ListEventsByUserID 
@@ -206,20 +269,14 @@ func (s *EventService) ListEvents(ctx context.Context, userID int64) ([]models.E
 	}
 	result := make([]models.EventInfoDTO, 0, len(events))
 	for _, event := range events {
-		creator, err := s.store.GetUserByID(ctx, *event.CreatorID)
-		if err != nil {
-			return nil,  utils.ErrNotFound
-		}
 		dto := models.EventInfoDTO{
 			ID: event.EventUuid.String(),
 			Name: event.Name,
 			Description: utils.GetStringFromPointer(event.Description),
 			Currency: event.Currency,
+			Timezone: event.Timezone,
 			Status: utils.GetStringFromPointer(event.Status),
-			CreatedBy: models.CreatorDTO{
-				ID:   creator.UserUuid.String(),
-				Name: creator.Name,
-			},
+			CreatedBy: s.eventCreator(ctx, event),
 			CreatedAt: event.CreatedAt.Time,
 			UpdatedAt: event.LastUpdatedAt.Time,
 		}
@@ -228,61 +285,44 @@ func (s *EventService) ListEvents(ctx context.Context, userID int64) ([]models.E
 	return result, nil
 }
 
-func (s *EventService) JoinEvent(ctx context.Context, userID int64, eventUUID string, req models.UpsertParticipantRequest) error {
-    // Logic:
-    // - Lấy eventID (int64) từ UUID
-    // - Gọi s.store.AddParticipant
+func (s *EventService) LeaveEvent(ctx context.Context, userID int64, eventUUID string, reassignToUUID string) error {
 	eventUUIDType, err := utils.StringToUUID(eventUUID)
 	if err != nil {
 		return utils.ErrInvalidInput
 	}
+
 	event, err := s.store.GetEventByUUID(ctx, eventUUIDType)
 	if err != nil {
 		return utils.ErrNotFound
 	}
-	// Kiem tra user da la participant chua
-	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+	if event.IsClosed {
+		return utils.ErrEventClosed
+	}
+
+	// find participant record first
+	part, err := s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
 		EventID: event.EventID,
 		UserID:  &userID,
 	})
-	if err == nil {
-		return utils.ErrAlreadyExists
-	}
-	// Auto fill bank info
-	user, err := s.store.GetUserByID(ctx, userID)
 	if err != nil {
 		return utils.ErrNotFound
 	}
-	var bankName, bankAcc, bankOwner *string
-	getVal := func(reqVal string, dbVal *string) *string {
-		if reqVal != "" {
-			return &reqVal
-		}
-		return dbVal
-	}
-	if req.BankInfo != nil {
-		bankName = getVal(req.BankInfo.BankName, user.BankName)
-		bankAcc = getVal(req.BankInfo.AccountNumber, user.BankAccount)
-		bankOwner = getVal(req.BankInfo.AccountName, user.BankOwner)
-	} else {
-		bankName, bankAcc, bankOwner = user.BankName, user.BankAccount, user.BankOwner
+	if part.Role == roleOwner {
+		return fmt.Errorf("%w: owner must transfer ownership before leaving", utils.ErrInvalidInput)
 	}
-	arg := database.AddParticipantParams{
-		EventID: event.EventID,
-		Name: req.Name,
-		UserID: &userID,
-		BankName: bankName,
-		BankAccount: bankAcc,
-		BankOwner: bankOwner,
+
+	// Xoa mem, so du khac 0 thi phai chuyen cho nguoi khac
+	reassignTo, err := getReassignTarget(ctx, s.store, part, reassignToUUID)
+	if err != nil {
+		return err
 	}
-	_, err = s.store.AddParticipant(ctx, arg)
-	return err
+	return removeParticipant(ctx, s.store, part, reassignTo, userID, activityParticipantLeft)
 }
 
-func (s *EventService) LeaveEvent(ctx context.Context, userID int64, eventUUID string) error {
-    // Logic:
-    // - Kiểm tra xem user có đang nợ tiền (Balance < 0) trong event không? Nếu có thì chặn.
-    // - Gọi s.store.RemoveParticipant (Cần viết thêm query này nếu chưa có)
+func (s *EventService) DeleteEvent(ctx context.Context, userID int64, eventUUID string) error {
+	// Logic:
+	// - Kiểm tra xem user có phải owner của event không
+	// - Gọi s.store.DeleteEvent 
 	eventUUIDType, err := utils.StringToUUID(eventUUID)
 	if err != nil {
 		return utils.ErrInvalidInput
@@ -293,55 +333,441 @@ func (s *EventService) LeaveEvent(ctx context.Context, userID int64, eventUUID s
 		return utils.ErrNotFound
 	}
 
-	// find participant record first
-	part, err := s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
-		EventID: event.EventID,
-		UserID:  &userID,
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleOwner); err != nil {
+		return err
+	}
+
+	// Xoa mem, event nam trong thung rac cua owner cho den khi bi purge
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		affected, err := q.SoftDeleteEvent(ctx, event.EventID)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return utils.ErrNotFound
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityEventDeleted, entityEvent, event.EventUuid, map[string]any{"name": event.Name})
 	})
 	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return err
+		}
+		return utils.ErrInternalDB
+	}
+	return nil
+}
+
+// Khoi phuc event tu thung rac (chi owner, trong han luu tru)
+func (s *EventService) RestoreEvent(ctx context.Context, userID int64, eventUUID string) error {
+	eventUUIDType, err := utils.StringToUUID(eventUUID)
+	if err != nil {
+		return utils.ErrInvalidInput
+	}
+	event, err := s.store.GetDeletedEventByUUID(ctx, eventUUIDType)
+	if err != nil || trashExpired(event.DeletedAt, s.trashRetention) {
 		return utils.ErrNotFound
 	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleOwner); err != nil {
+		return err
+	}
 
-	balance, err := s.store.GetParticipantBalance(ctx, database.GetParticipantBalanceParams{
-		EventID: event.EventID,
-		UserID:  &userID,
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		if _, err := q.RestoreEvent(ctx, event.EventID); err != nil {
+			return err
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityEventRestored, entityEvent, event.EventUuid, map[string]any{"name": event.Name})
 	})
-	if err == nil {
-		balanceFloat := utils.NumericToFloat(balance)
-		if balanceFloat > 0.01 || balanceFloat < -0.01 {
-			return utils.ErrBalanceNotZero
+	if err != nil {
+		// Vua duoc khoi phuc hoac job purge vua xoa
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.ErrNotFound
 		}
+		return utils.ErrInternalDB
 	}
+	return nil
+}
 
-	err = s.store.RemoveParticipantByID(ctx, part.ParticipantID)
+// Thung rac: event da xoa ma user la owner, con trong han luu tru
+func (s *EventService) ListDeletedEvents(ctx context.Context, userID int64) ([]models.DeletedEventDTO, error) {
+	events, err := s.store.ListDeletedEventsByOwner(ctx, &userID)
 	if err != nil {
-		return utils.ErrInternalDB
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.DeletedEventDTO, 0, len(events))
+	for _, e := range events {
+		if trashExpired(e.DeletedAt, s.trashRetention) {
+			continue
+		}
+		result = append(result, models.DeletedEventDTO{
+			ID:        e.EventUuid.String(),
+			Name:      e.Name,
+			Currency:  e.Currency,
+			DeletedAt: e.DeletedAt.Time,
+			PurgeAt:   purgeAt(e.DeletedAt, s.trashRetention),
+		})
 	}
+	return result, nil
+}
 
-	return nil
+// Dong event (chi owner): chot summary cuoi cung vao snapshot, sau do moi thao tac ghi deu bi chan
+func (s *EventService) CloseEvent(ctx context.Context, userID int64, eventUUID string, req models.CloseEventRequest) (models.EventSnapshotDTO, error) {
+	eventUUIDType, err := utils.StringToUUID(eventUUID)
+	if err != nil {
+		return models.EventSnapshotDTO{}, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUIDType)
+	if err != nil {
+		return models.EventSnapshotDTO{}, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleOwner); err != nil {
+		return models.EventSnapshotDTO{}, err
+	}
+	if event.IsClosed {
+		return models.EventSnapshotDTO{}, utils.ErrEventClosed
+	}
+
+	strategy, err := checkSummaryOptions(req.Strategy, "")
+	if err != nil {
+		return models.EventSnapshotDTO{}, err
+	}
+
+	var snapshot database.EventSnapshot
+	var summaryErr error
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		// Dong event truoc de khoa dong events, summary tinh trong cung tx nen khop voi luc khoa
+		closed, err := q.SetEventClosed(ctx, database.SetEventClosedParams{EventID: event.EventID, IsClosed: true})
+		if err != nil {
+			return err
+		}
+		summary, err := NewSettlementService(s.store).buildEventSummary(ctx, q, userID, closed, strategy, "")
+		if err != nil {
+			summaryErr = err
+			return err
+		}
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		snapshot, err = q.CreateEventSnapshot(ctx, database.CreateEventSnapshotParams{
+			EventID:  event.EventID,
+			ClosedBy: &userID,
+			Summary:  data,
+		})
+		return err
+	})
+	if err != nil {
+		if summaryErr != nil {
+			return models.EventSnapshotDTO{}, summaryErr
+		}
+		// Event vua bi dong boi request khac
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.EventSnapshotDTO{}, utils.ErrEventClosed
+		}
+		return models.EventSnapshotDTO{}, utils.ErrInternalDB
+	}
+	return toEventSnapshotDTO(snapshot, eventUUID)
 }
 
-func (s *EventService) DeleteEvent(ctx context.Context, userID int64, eventUUID string) error {
-	// Logic:
-	// - Kiểm tra xem user có phải CreatorID của event không
-	// - Gọi s.store.DeleteEvent 
+// Mo lai event da dong (chi owner), snapshot cu van duoc giu lai
+func (s *EventService) ReopenEvent(ctx context.Context, userID int64, eventUUID string) error {
 	eventUUIDType, err := utils.StringToUUID(eventUUID)
 	if err != nil {
 		return utils.ErrInvalidInput
 	}
-
 	event, err := s.store.GetEventByUUID(ctx, eventUUIDType)
 	if err != nil {
 		return utils.ErrNotFound
 	}
-
-	if *event.CreatorID != userID {
-		return utils.ErrPermissionDenied
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleOwner); err != nil {
+		return err
+	}
+	if !event.IsClosed {
+		return fmt.Errorf("%w: event is not closed", utils.ErrInvalidInput)
 	}
 
-	err = s.store.DeleteEvent(ctx, event.EventID)
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		if _, err := q.SetEventClosed(ctx, database.SetEventClosedParams{EventID: event.EventID, IsClosed: false}); err != nil {
+			return err
+		}
+		return q.MarkEventSnapshotReopened(ctx, event.EventID)
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: event is not closed", utils.ErrInvalidInput)
+		}
 		return utils.ErrInternalDB
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// Lay snapshot cua lan dong gan nhat
+func (s *EventService) GetEventSnapshot(ctx context.Context, userID int64, eventUUID string) (models.EventSnapshotDTO, error) {
+	eventUUIDType, err := utils.StringToUUID(eventUUID)
+	if err != nil {
+		return models.EventSnapshotDTO{}, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUIDType)
+	if err != nil {
+		return models.EventSnapshotDTO{}, utils.ErrNotFound
+	}
+	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+		EventID: event.EventID,
+		UserID:  &userID,
+	})
+	if err != nil {
+		return models.EventSnapshotDTO{}, utils.ErrPermissionDenied
+	}
+	snapshot, err := s.store.GetLatestEventSnapshot(ctx, event.EventID)
+	if err != nil {
+		return models.EventSnapshotDTO{}, utils.ErrNotFound
+	}
+	return toEventSnapshotDTO(snapshot, eventUUID)
+}
+
+func toEventSnapshotDTO(snapshot database.EventSnapshot, eventUUID string) (models.EventSnapshotDTO, error) {
+	dto := models.EventSnapshotDTO{
+		ID:       snapshot.SnapshotUuid.String(),
+		EventID:  eventUUID,
+		ClosedAt: snapshot.CreatedAt,
+	}
+	if snapshot.ReopenedAt.Valid {
+		dto.ReopenedAt = &snapshot.ReopenedAt.Time
+	}
+	if err := json.Unmarshal(snapshot.Summary, &dto.Summary); err != nil {
+		return models.EventSnapshotDTO{}, utils.ErrInternalDB
+	}
+	return dto, nil
+}
+
+// Owner de nghi chuyen quyen cho thanh vien khac, chi co hieu luc khi nguoi nhan chap nhan.
+// De nghi moi thay the de nghi dang cho. Cho phep ca khi event da dong.
+func (s *EventService) TransferOwnership(ctx context.Context, userID int64, eventUUID string, req models.TransferOwnershipRequest) (models.OwnershipTransferDTO, error) {
+	event, err := s.getEvent(ctx, eventUUID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	owner, err := requireRole(ctx, s.store, event.EventID, userID, roleOwner)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	targetUUID, err := utils.StringToUUID(req.ParticipantID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, utils.ErrInvalidInput
+	}
+	target, err := s.store.GetParticipantByUUID(ctx, targetUUID)
+	if err != nil || target.EventID != event.EventID || target.RemovedAt.Valid {
+		return models.OwnershipTransferDTO{}, utils.ErrNotFound
+	}
+	if target.ParticipantID == owner.ParticipantID {
+		return models.OwnershipTransferDTO{}, fmt.Errorf("%w: cannot transfer ownership to yourself", utils.ErrInvalidInput)
+	}
+	if target.UserID == nil {
+		return models.OwnershipTransferDTO{}, fmt.Errorf("%w: new owner must have an account", utils.ErrInvalidInput)
+	}
+
+	var transfer database.EventOwnershipTransfer
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		if err := q.CancelPendingOwnershipTransfers(ctx, event.EventID); err != nil {
+			return err
+		}
+		transfer, err = q.CreateOwnershipTransfer(ctx, database.CreateOwnershipTransferParams{
+			EventID:           event.EventID,
+			FromParticipantID: owner.ParticipantID,
+			ToParticipantID:   target.ParticipantID,
+		})
+		return err
+	})
+	if err != nil {
+		return models.OwnershipTransferDTO{}, utils.ErrInternalDB
+	}
+	return toOwnershipTransferDTO(transfer, eventUUID, owner, target), nil
+}
+
+// Xem de nghi chuyen quyen dang cho (moi thanh vien)
+func (s *EventService) GetOwnershipTransfer(ctx context.Context, userID int64, eventUUID string) (models.OwnershipTransferDTO, error) {
+	event, err := s.getEvent(ctx, eventUUID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleViewer); err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	transfer, from, to, err := s.getPendingTransfer(ctx, event.EventID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	return toOwnershipTransferDTO(transfer, eventUUID, from, to), nil
+}
+
+// Nguoi duoc de nghi chap nhan: tro thanh owner, owner cu xuong admin
+func (s *EventService) AcceptOwnershipTransfer(ctx context.Context, userID int64, eventUUID string) (models.OwnershipTransferDTO, error) {
+	event, err := s.getEvent(ctx, eventUUID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	transfer, from, to, err := s.getPendingTransfer(ctx, event.EventID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	if to.UserID == nil || *to.UserID != userID {
+		return models.OwnershipTransferDTO{}, utils.ErrPermissionDenied
+	}
+	// Owner da doi (vd xoa tai khoan) hoac nguoi nhan da roi event sau khi gui de nghi
+	if from.Role != roleOwner || to.RemovedAt.Valid {
+		return models.OwnershipTransferDTO{}, fmt.Errorf("%w: ownership transfer is no longer valid", utils.ErrInvalidInput)
+	}
+
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		transfer, err = q.ResolveOwnershipTransfer(ctx, database.ResolveOwnershipTransferParams{
+			TransferID: transfer.TransferID,
+			Status:     transferStatusAccepted,
+		})
+		if err != nil {
+			return err
+		}
+		return handOverOwnership(ctx, q, event.EventID, from, to)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.OwnershipTransferDTO{}, utils.ErrNotFound
+		}
+		return models.OwnershipTransferDTO{}, utils.ErrInternalDB
+	}
+	from.Role, to.Role = roleAdmin, roleOwner
+	return toOwnershipTransferDTO(transfer, eventUUID, from, to), nil
+}
+
+// Nguoi duoc de nghi tu choi, hoac owner huy de nghi dang cho
+func (s *EventService) DeclineOwnershipTransfer(ctx context.Context, userID int64, eventUUID string) (models.OwnershipTransferDTO, error) {
+	event, err := s.getEvent(ctx, eventUUID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	transfer, from, to, err := s.getPendingTransfer(ctx, event.EventID)
+	if err != nil {
+		return models.OwnershipTransferDTO{}, err
+	}
+	var status string
+	switch {
+	case to.UserID != nil && *to.UserID == userID:
+		status = transferStatusDeclined
+	case from.UserID != nil && *from.UserID == userID:
+		status = transferStatusCancelled
+	default:
+		return models.OwnershipTransferDTO{}, utils.ErrPermissionDenied
+	}
+
+	transfer, err = s.store.ResolveOwnershipTransfer(ctx, database.ResolveOwnershipTransferParams{
+		TransferID: transfer.TransferID,
+		Status:     status,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.OwnershipTransferDTO{}, utils.ErrNotFound
+		}
+		return models.OwnershipTransferDTO{}, utils.ErrInternalDB
+	}
+	return toOwnershipTransferDTO(transfer, eventUUID, from, to), nil
+}
+
+// Helper: lay event theo UUID
+func (s *EventService) getEvent(ctx context.Context, eventUUID string) (database.Event, error) {
+	eventUUIDType, err := utils.StringToUUID(eventUUID)
+	if err != nil {
+		return database.Event{}, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUIDType)
+	if err != nil {
+		return database.Event{}, utils.ErrNotFound
+	}
+	return event, nil
+}
+
+// Helper: de nghi chuyen quyen dang cho cung 2 participant lien quan
+func (s *EventService) getPendingTransfer(ctx context.Context, eventID int64) (database.EventOwnershipTransfer, database.Participant, database.Participant, error) {
+	transfer, err := s.store.GetPendingOwnershipTransfer(ctx, eventID)
+	if err != nil {
+		return database.EventOwnershipTransfer{}, database.Participant{}, database.Participant{}, utils.ErrNotFound
+	}
+	from, err := s.store.GetParticipantByID(ctx, transfer.FromParticipantID)
+	if err != nil {
+		return database.EventOwnershipTransfer{}, database.Participant{}, database.Participant{}, utils.ErrInternalDB
+	}
+	to, err := s.store.GetParticipantByID(ctx, transfer.ToParticipantID)
+	if err != nil {
+		return database.EventOwnershipTransfer{}, database.Participant{}, database.Participant{}, utils.ErrInternalDB
+	}
+	return transfer, from, to, nil
+}
+
+func toOwnershipTransferDTO(transfer database.EventOwnershipTransfer, eventUUID string, from, to database.Participant) models.OwnershipTransferDTO {
+	dto := models.OwnershipTransferDTO{
+		ID:        transfer.TransferUuid.String(),
+		EventID:   eventUUID,
+		From:      from.ParticipantUuid.String(),
+		FromName:  from.Name,
+		To:        to.ParticipantUuid.String(),
+		ToName:    to.Name,
+		Status:    transfer.Status,
+		CreatedAt: transfer.CreatedAt,
+	}
+	if transfer.RespondedAt.Valid {
+		dto.RespondedAt = &transfer.RespondedAt.Time
+	}
+	return dto
+}
+
+// Activity feed cua event (moi nhat truoc), moi thanh vien deu xem duoc.
+// before la id activity cuoi cung cua trang truoc, bo trong de lay trang dau
+func (s *EventService) ListActivities(ctx context.Context, userID int64, eventUUID string, limit int, before string) (models.ActivityFeedResponse, error) {
+	event, err := s.getEvent(ctx, eventUUID)
+	if err != nil {
+		return models.ActivityFeedResponse{}, err
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleViewer); err != nil {
+		return models.ActivityFeedResponse{}, err
+	}
+	if limit <= 0 {
+		limit = defaultActivityPageSize
+	}
+	if limit > maxActivityPageSize {
+		limit = maxActivityPageSize
+	}
+	var cursor pgtype.UUID
+	if before != "" {
+		beforeUUID, err := utils.StringToUUID(before)
+		if err != nil {
+			return models.ActivityFeedResponse{}, fmt.Errorf("%w: invalid cursor", utils.ErrInvalidInput)
+		}
+		// Con tro khong ton tai hoac cua event khac thi bao loi thay vi tra trang rong
+		if _, err := s.store.GetEventActivityID(ctx, database.GetEventActivityIDParams{
+			EventID:      event.EventID,
+			ActivityUuid: beforeUUID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return models.ActivityFeedResponse{}, fmt.Errorf("%w: unknown cursor", utils.ErrInvalidInput)
+			}
+			return models.ActivityFeedResponse{}, utils.ErrInternalDB
+		}
+		cursor = pgtype.UUID{Bytes: beforeUUID, Valid: true}
+	}
+
+	// Lay du 1 dong de biet con trang sau khong
+	rows, err := s.store.ListEventActivities(ctx, database.ListEventActivitiesParams{
+		EventID: event.EventID,
+		Limit:   int32(limit + 1),
+		Before:  cursor,
+	})
+	if err != nil {
+		return models.ActivityFeedResponse{}, utils.ErrInternalDB
+	}
+	resp := models.ActivityFeedResponse{Items: make([]models.ActivityDTO, 0, len(rows))}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		resp.NextCursor = rows[limit-1].ActivityUuid.String()
+	}
+	for _, row := range rows {
+		resp.Items = append(resp.Items, toActivityDTO(row))
+	}
+	return resp, nil
+}
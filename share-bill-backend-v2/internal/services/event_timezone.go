@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// Image alpine khong co tzdata, nhung vao binary de LoadLocation luon chay
+	_ "time/tzdata"
+
+	database "BACKEND/internal/db/sqlc"
+	utils "BACKEND/internal/utils"
+)
+
+// Mui gio mac dinh cua event, dong bo voi DEFAULT cua events.timezone
+const defaultEventTimezone = "Asia/Ho_Chi_Minh"
+
+// Dinh dang ngay/gio khong kem mui gio, hieu theo mui gio cua event
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+const dateLayout = "2006-01-02"
+
+// Helper: chuan hoa ten mui gio IANA, bo trong thi dung mac dinh
+func normalizeTimezone(input string) (string, error) {
+	name := strings.TrimSpace(input)
+	if name == "" {
+		return defaultEventTimezone, nil
+	}
+	// "Local" phu thuoc may chu nen khong nhan
+	if strings.EqualFold(name, "local") {
+		return "", fmt.Errorf("%w: timezone must be an IANA name such as Asia/Ho_Chi_Minh", utils.ErrInvalidInput)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: unknown timezone %q", utils.ErrInvalidInput, name)
+	}
+	return loc.String(), nil
+}
+
+// Helper: mui gio cua event, ten khong hop le (du lieu cu) thi dung UTC
+func eventLocation(event database.Event) *time.Location {
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Helper: doc thoi diem chi tieu. Nhan RFC3339 (co offset), ngay gio hoac chi ngay theo mui gio cua event.
+// Chi co ngay thi lay 12:00 de doi mui gio khong bi lech sang ngay khac
+func parseSpentAt(input string, loc *time.Location) (time.Time, error) {
+	value := strings.TrimSpace(input)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	if d, err := time.ParseInLocation(dateLayout, value, loc); err == nil {
+		return d.Add(12 * time.Hour), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: spentAt must be RFC3339, YYYY-MM-DDTHH:MM or YYYY-MM-DD", utils.ErrInvalidInput)
+}
+
+// Helper: moc loc theo khoang thoi gian. Chi co ngay thi tinh ca ngay do:
+// from = 00:00 cua ngay, to = 00:00 cua ngay hom sau (khong lay moc to)
+func parseDateBound(input string, loc *time.Location, end bool) (*time.Time, error) {
+	value := strings.TrimSpace(input)
+	if value == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseInLocation(dateLayout, value, loc); err == nil {
+		if end {
+			d = d.AddDate(0, 0, 1)
+		}
+		return &d, nil
+	}
+	t, err := parseSpentAt(value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date %q", utils.ErrInvalidInput, value)
+	}
+	return &t, nil
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	database "BACKEND/internal/db/sqlc"
+	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
+	utils "BACKEND/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Nguon cua ty gia trong bang ty gia cua event
+const (
+	rateSourceManual = "manual"
+	rateSourceFile   = "file"
+)
+
+// So chu so le luu trong DB (NUMERIC(20,10))
+const exchangeRateScale = 10
+
+type ExchangeRateService struct {
+	store     database.Store
+	ratesFile string
+}
+
+// Khoi tao ExchangeRateService, ratesFile la file ty gia local (bo trong thi chi nhap tay)
+func NewExchangeRateService(store database.Store, ratesFile string) *ExchangeRateService {
+	return &ExchangeRateService{store: store, ratesFile: ratesFile}
+}
+
+// Quy doi tu currency cua expense sang currency cua event
+type currencyConversion struct {
+	currency      string   // Currency cua expense
+	eventCurrency string   // Currency cua event
+	rate          *big.Rat // 1 don vi currency = rate don vi eventCurrency
+}
+
+func (c currencyConversion) same() bool {
+	return c.currency == c.eventCurrency
+}
+
+// Quy doi 1 so tien, lam tron theo currency cua event
+func (c currencyConversion) convert(amount money.Amount) (money.Amount, error) {
+	if c.same() {
+		return amount, nil
+	}
+	converted, err := amount.MulRat(c.rate, c.eventCurrency)
+	if err != nil {
+		return 0, utils.ErrInvalidInput
+	}
+	if amount > 0 && converted <= 0 {
+		return 0, fmt.Errorf("%w: amount is too small to convert to %s", utils.ErrInvalidInput, c.eventCurrency)
+	}
+	return converted, nil
+}
+
+// Quy doi cac phan cua total: total quy doi 1 lan roi chia lai theo ty le cac phan nen tong van khop
+func (c currencyConversion) convertParts(total money.Amount, parts []money.Amount) ([]money.Amount, error) {
+	if c.same() {
+		return parts, nil
+	}
+	converted, err := c.convert(total)
+	if err != nil {
+		return nil, err
+	}
+	weights := make([]*big.Rat, len(parts))
+	for i, part := range parts {
+		weights[i] = big.NewRat(part.Cents(), 1)
+	}
+	result, err := money.AllocateRat(converted, weights, c.eventCurrency)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	return result, nil
+}
+
+// Helper: xac dinh currency va ty gia cho expense. Bo trong currency la currency cua event.
+// exchangeRate bo trong thi giu ty gia cu (khi sua ma khong doi currency) hoac lay tu bang ty gia cua event
+func resolveConversion(ctx context.Context, q database.Querier, event database.Event, req models.CreateTransactionRequest, current *database.Expense) (currencyConversion, error) {
+	conv := currencyConversion{
+		currency:      money.NormalizeCurrency(req.Currency),
+		eventCurrency: money.NormalizeCurrency(event.Currency),
+		rate:          big.NewRat(1, 1),
+	}
+	if conv.currency == "" || conv.same() {
+		conv.currency = conv.eventCurrency
+		return conv, nil
+	}
+	if !money.IsCurrencyCode(conv.currency) {
+		return conv, fmt.Errorf("%w: currency must be a 3-letter ISO code", utils.ErrInvalidInput)
+	}
+	switch {
+	case req.ExchangeRate != nil:
+		rate, err := parseExchangeRate(*req.ExchangeRate)
+		if err != nil {
+			return conv, err
+		}
+		conv.rate = rate
+	case current != nil && money.NormalizeCurrency(current.Currency) == conv.currency:
+		conv.rate = numericRat(current.ExchangeRate)
+	default:
+		row, err := q.GetEventExchangeRate(ctx, database.GetEventExchangeRateParams{
+			EventID: event.EventID, Currency: conv.currency,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return conv, fmt.Errorf("%w: no exchange rate for %s, set one for the event or send exchangeRate", utils.ErrInvalidInput, conv.currency)
+		}
+		if err != nil {
+			return conv, utils.ErrInternalDB
+		}
+		conv.rate = numericRat(row.Rate)
+	}
+	return conv, nil
+}
+
+// Helper: doc ty gia nguoi dung nhap, lam tron ve so chu so le luu trong DB
+func parseExchangeRate(f float64) (*big.Rat, error) {
+	r, ok := money.WeightRat(f)
+	if !ok {
+		return nil, utils.ErrInvalidInput
+	}
+	r, _ = new(big.Rat).SetString(r.FloatString(exchangeRateScale))
+	if r.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: exchange rate must be greater than 0", utils.ErrInvalidInput)
+	}
+	return r, nil
+}
+
+// Helper: phan so -> NUMERIC de luu ty gia
+func ratNumeric(r *big.Rat) pgtype.Numeric {
+	var n pgtype.Numeric
+	_ = n.Scan(r.FloatString(exchangeRateScale))
+	return n
+}
+
+// Helper: NUMERIC -> phan so chinh xac, NULL coi nhu ty gia 1
+func numericRat(n pgtype.Numeric) *big.Rat {
+	if !n.Valid || n.Int == nil {
+		return big.NewRat(1, 1)
+	}
+	r := new(big.Rat).SetInt(n.Int)
+	exp := int64(n.Exp)
+	if exp < 0 {
+		exp = -exp
+	}
+	pow := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	if n.Exp > 0 {
+		r.Mul(r, pow)
+	} else if n.Exp < 0 {
+		r.Quo(r, pow)
+	}
+	return r
+}
+
+// Chuyen 1 dong ty gia sang DTO
+func toExchangeRateDTO(row database.EventExchangeRate) models.ExchangeRateDTO {
+	rate, _ := numericRat(row.Rate).Float64()
+	return models.ExchangeRateDTO{
+		Currency:  row.Currency,
+		Rate:      rate,
+		Source:    row.Source,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+// Bang ty gia cua event, moi thanh vien deu xem duoc
+func (s *ExchangeRateService) ListRates(ctx context.Context, userID int64, eventUUIDStr string) (models.ExchangeRatesResponse, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleViewer)
+	if err != nil {
+		return models.ExchangeRatesResponse{}, err
+	}
+	rows, err := s.store.ListEventExchangeRates(ctx, event.EventID)
+	if err != nil {
+		return models.ExchangeRatesResponse{}, utils.ErrInternalDB
+	}
+	resp := models.ExchangeRatesResponse{
+		EventCurrency: money.NormalizeCurrency(event.Currency),
+		Rates:         make([]models.ExchangeRateDTO, 0, len(rows)),
+	}
+	for _, row := range rows {
+		resp.Rates = append(resp.Rates, toExchangeRateDTO(row))
+	}
+	return resp, nil
+}
+
+// Nhap tay ty gia (admin tro len). Currency da co thi ghi de
+func (s *ExchangeRateService) SetRates(ctx context.Context, userID int64, eventUUIDStr string, req models.SetExchangeRatesRequest) (models.ExchangeRatesResponse, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleAdmin)
+	if err != nil {
+		return models.ExchangeRatesResponse{}, err
+	}
+	if len(req.Rates) == 0 {
+		return models.ExchangeRatesResponse{}, fmt.Errorf("%w: at least one rate is required", utils.ErrInvalidInput)
+	}
+	rates := make(map[string]*big.Rat, len(req.Rates))
+	for _, input := range req.Rates {
+		currency, err := s.rateCurrency(event, input.Currency)
+		if err != nil {
+			return models.ExchangeRatesResponse{}, err
+		}
+		if _, dup := rates[currency]; dup {
+			return models.ExchangeRatesResponse{}, fmt.Errorf("%w: duplicate currency %s", utils.ErrInvalidInput, currency)
+		}
+		rate, err := parseExchangeRate(input.Rate)
+		if err != nil {
+			return models.ExchangeRatesResponse{}, err
+		}
+		rates[currency] = rate
+	}
+	if err := s.saveRates(ctx, userID, event, rates, rateSourceManual); err != nil {
+		return models.ExchangeRatesResponse{}, err
+	}
+	return s.ListRates(ctx, userID, eventUUIDStr)
+}
+
+// Nap ty gia tu file local (admin tro len). currencies bo trong thi nap moi currency co trong file
+func (s *ExchangeRateService) ImportRates(ctx context.Context, userID int64, eventUUIDStr string, req models.ImportExchangeRatesRequest) (models.ExchangeRatesResponse, error) {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleAdmin)
+	if err != nil {
+		return models.ExchangeRatesResponse{}, err
+	}
+	if s.ratesFile == "" {
+		return models.ExchangeRatesResponse{}, fmt.Errorf("%w: exchange rates file is not configured", utils.ErrNotFound)
+	}
+	table, err := loadRatesFile(s.ratesFile)
+	if err != nil {
+		return models.ExchangeRatesResponse{}, err
+	}
+	eventCurrency := money.NormalizeCurrency(event.Currency)
+	eventPrice, ok := table[eventCurrency]
+	if !ok {
+		return models.ExchangeRatesResponse{}, fmt.Errorf("%w: rates file has no rate for event currency %s", utils.ErrNotFound, eventCurrency)
+	}
+
+	currencies := req.Currencies
+	if len(currencies) == 0 {
+		for currency := range table {
+			if currency != eventCurrency {
+				currencies = append(currencies, currency)
+			}
+		}
+	}
+	rates := make(map[string]*big.Rat, len(currencies))
+	for _, input := range currencies {
+		currency, err := s.rateCurrency(event, input)
+		if err != nil {
+			return models.ExchangeRatesResponse{}, err
+		}
+		price, ok := table[currency]
+		if !ok {
+			return models.ExchangeRatesResponse{}, fmt.Errorf("%w: rates file has no rate for %s", utils.ErrNotFound, currency)
+		}
+		// Ty gia cheo qua currency goc cua file
+		rate := new(big.Rat).Quo(eventPrice, price)
+		rate, _ = new(big.Rat).SetString(rate.FloatString(exchangeRateScale))
+		if rate.Sign() <= 0 {
+			return models.ExchangeRatesResponse{}, fmt.Errorf("%w: rate for %s is too small", utils.ErrInvalidInput, currency)
+		}
+		rates[currency] = rate
+	}
+	if len(rates) == 0 {
+		return models.ExchangeRatesResponse{}, fmt.Errorf("%w: rates file has no other currency", utils.ErrInvalidInput)
+	}
+	if err := s.saveRates(ctx, userID, event, rates, rateSourceFile); err != nil {
+		return models.ExchangeRatesResponse{}, err
+	}
+	return s.ListRates(ctx, userID, eventUUIDStr)
+}
+
+// Xoa ty gia cua 1 currency (admin tro len). Transaction da tao van giu ty gia da dung
+func (s *ExchangeRateService) DeleteRate(ctx context.Context, userID int64, eventUUIDStr string, currencyStr string) error {
+	event, err := s.getEvent(ctx, userID, eventUUIDStr, roleAdmin)
+	if err != nil {
+		return err
+	}
+	currency := money.NormalizeCurrency(currencyStr)
+	return s.store.ExecTx(ctx, func(q *database.Queries) error {
+		affected, err := q.DeleteEventExchangeRate(ctx, database.DeleteEventExchangeRateParams{
+			EventID: event.EventID, Currency: currency,
+		})
+		if err != nil {
+			return utils.ErrInternalDB
+		}
+		if affected == 0 {
+			return utils.ErrNotFound
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityExchangeRateDeleted, entityEvent, event.EventUuid, map[string]any{
+			"currency": currency,
+		})
+	})
+}
+
+// Helper: luu nhieu ty gia trong 1 transaction va ghi 1 dong activity
+func (s *ExchangeRateService) saveRates(ctx context.Context, userID int64, event database.Event, rates map[string]*big.Rat, source string) error {
+	err := s.store.ExecTx(ctx, func(q *database.Queries) error {
+		logged := make(map[string]float64, len(rates))
+		for currency, rate := range rates {
+			_, err := q.UpsertEventExchangeRate(ctx, database.UpsertEventExchangeRateParams{
+				EventID:   event.EventID,
+				Currency:  currency,
+				Rate:      ratNumeric(rate),
+				Source:    source,
+				UpdatedBy: &userID,
+			})
+			if err != nil {
+				return err
+			}
+			logged[currency], _ = rate.Float64()
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityExchangeRatesSet, entityEvent, event.EventUuid, map[string]any{
+			"source": source,
+			"rates":  logged,
+		})
+	})
+	if err != nil {
+		return utils.ErrInternalDB
+	}
+	return nil
+}
+
+// Helper: chuan hoa currency cua 1 ty gia, khong cho dat ty gia cho chinh currency cua event
+func (s *ExchangeRateService) rateCurrency(event database.Event, input string) (string, error) {
+	currency := money.NormalizeCurrency(input)
+	if !money.IsCurrencyCode(currency) {
+		return "", fmt.Errorf("%w: currency must be a 3-letter ISO code", utils.ErrInvalidInput)
+	}
+	if currency == money.NormalizeCurrency(event.Currency) {
+		return "", fmt.Errorf("%w: %s is already the event currency", utils.ErrInvalidInput, currency)
+	}
+	return currency, nil
+}
+
+// Helper: lay event va kiem tra quyen; sua bang ty gia thi event phai con mo
+func (s *ExchangeRateService) getEvent(ctx context.Context, userID int64, eventUUIDStr string, min string) (database.Event, error) {
+	eventUUID, err := utils.StringToUUID(eventUUIDStr)
+	if err != nil {
+		return database.Event{}, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUID)
+	if err != nil {
+		return database.Event{}, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, min); err != nil {
+		return database.Event{}, err
+	}
+	if min != roleViewer && event.IsClosed {
+		return database.Event{}, utils.ErrEventClosed
+	}
+	return event, nil
+}
+
+// File ty gia local: rates la gia cua 1 don vi base tinh theo tung currency,
+// vd {"base": "USD", "rates": {"VND": 25400, "THB": 36.2}}
+type ratesFile struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Helper: doc file ty gia, tra ve gia cua 1 don vi base theo tung currency (base = 1)
+func loadRatesFile(path string) (map[string]*big.Rat, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read exchange rates file: %w", err)
+	}
+	var file ratesFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse exchange rates file: %w", err)
+	}
+	table := make(map[string]*big.Rat, len(file.Rates)+1)
+	for currency, price := range file.Rates {
+		r, ok := money.WeightRat(price)
+		if !ok || r.Sign() <= 0 {
+			continue
+		}
+		table[money.NormalizeCurrency(currency)] = r
+	}
+	if base := money.NormalizeCurrency(file.Base); base != "" {
+		table[base] = big.NewRat(1, 1)
+	}
+	return table, nil
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	database "BACKEND/internal/db/sqlc"
+	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
+	utils "BACKEND/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	adjustmentSurcharge = "surcharge"
+	adjustmentDiscount  = "discount"
+)
+
+// Ket qua chia tien cho 1 mon sau khi da phan bo phu thu/giam gia
+type itemSplit struct {
+	amount  money.Amount
+	weights []float64
+	shares  []money.Amount
+}
+
+// Ket qua chia tien cho ca hoa don itemized
+type itemizedSplit struct {
+	total       money.Amount
+	items       []itemSplit
+	adjustments []money.Amount // So tien cua tung adjustment, cung thu tu voi request
+}
+
+// Tinh tong hoa don va share cua tung nguoi tren tung mon.
+// Phu thu/giam gia duoc phan bo theo gia mon nen tong cac share luon bang total.
+func computeItemizedSplit(currency string, items []models.TransactionItem, adjustments []models.TransactionAdjustment) (itemizedSplit, error) {
+	if len(items) == 0 {
+		return itemizedSplit{}, fmt.Errorf("%w: itemized split needs at least one item", utils.ErrInvalidInput)
+	}
+	itemAmounts := make([]money.Amount, len(items))
+	for i, item := range items {
+		amount := item.Amount.Round(currency)
+		if amount < 0 {
+			return itemizedSplit{}, fmt.Errorf("%w: item amount must not be negative", utils.ErrInvalidInput)
+		}
+		itemAmounts[i] = amount
+	}
+	subtotal := money.Sum(itemAmounts)
+	if subtotal <= 0 {
+		return itemizedSplit{}, fmt.Errorf("%w: items must have a positive subtotal", utils.ErrInvalidInput)
+	}
+
+	result := itemizedSplit{
+		items:       make([]itemSplit, len(items)),
+		adjustments: make([]money.Amount, len(adjustments)),
+	}
+	var net money.Amount
+	for i, adj := range adjustments {
+		amount, err := resolveAdjustmentAmount(subtotal, currency, adj)
+		if err != nil {
+			return itemizedSplit{}, err
+		}
+		result.adjustments[i] = amount
+		if adj.Kind == adjustmentDiscount {
+			net -= amount
+		} else {
+			net += amount
+		}
+	}
+	result.total = subtotal + net
+	if result.total <= 0 {
+		return itemizedSplit{}, fmt.Errorf("%w: discounts exceed the bill subtotal", utils.ErrInvalidInput)
+	}
+
+	// Phan bo phan chenh lech (phu thu - giam gia) theo gia tung mon
+	weights := make([]*big.Rat, len(items))
+	for i, amount := range itemAmounts {
+		weights[i] = big.NewRat(amount.Cents(), 1)
+	}
+	netShares, err := money.AllocateRat(net, weights, currency)
+	if err != nil {
+		return itemizedSplit{}, utils.ErrInvalidInput
+	}
+	for i, item := range items {
+		split, err := splitItem(itemAmounts[i]+netShares[i], currency, item)
+		if err != nil {
+			return itemizedSplit{}, err
+		}
+		result.items[i] = split
+	}
+	return result, nil
+}
+
+// Helper: so tien cua 1 adjustment, percent (neu co) tinh tren subtotal cua cac mon va uu tien hon amount
+func resolveAdjustmentAmount(subtotal money.Amount, currency string, adj models.TransactionAdjustment) (money.Amount, error) {
+	if adj.Kind != adjustmentSurcharge && adj.Kind != adjustmentDiscount {
+		return 0, fmt.Errorf("%w: adjustment kind must be surcharge or discount", utils.ErrInvalidInput)
+	}
+	switch {
+	case adj.Percent != nil:
+		if *adj.Percent < 0 {
+			return 0, fmt.Errorf("%w: adjustment percent must not be negative", utils.ErrInvalidInput)
+		}
+		pct, ok := money.WeightRat(*adj.Percent)
+		if !ok {
+			return 0, utils.ErrInvalidInput
+		}
+		amount, err := subtotal.MulRat(pct.Quo(pct, big.NewRat(100, 1)), currency)
+		if err != nil {
+			return 0, utils.ErrInvalidInput
+		}
+		return amount, nil
+	case adj.Amount != nil:
+		amount := adj.Amount.Round(currency)
+		if amount < 0 {
+			return 0, fmt.Errorf("%w: adjustment amount must not be negative", utils.ErrInvalidInput)
+		}
+		return amount, nil
+	}
+	return 0, fmt.Errorf("%w: adjustment needs either percent or amount", utils.ErrInvalidInput)
+}
+
+// Helper: chia tien 1 mon cho nguoi an theo weight, tat ca weight = 0 thi chia deu
+func splitItem(amount money.Amount, currency string, item models.TransactionItem) (itemSplit, error) {
+	if len(item.Beneficiaries) == 0 {
+		return itemSplit{}, fmt.Errorf("%w: item %q has no beneficiaries", utils.ErrInvalidInput, item.Name)
+	}
+	seen := make(map[string]bool, len(item.Beneficiaries))
+	allZero := true
+	for _, b := range item.Beneficiaries {
+		if seen[b.ParticipantID] {
+			return itemSplit{}, fmt.Errorf("%w: duplicate beneficiary %s on item %q", utils.ErrInvalidInput, b.ParticipantID, item.Name)
+		}
+		seen[b.ParticipantID] = true
+		if b.Weight < 0 {
+			return itemSplit{}, fmt.Errorf("%w: weight must not be negative", utils.ErrInvalidInput)
+		}
+		if b.Weight > 0 {
+			allZero = false
+		}
+	}
+
+	weights := make([]float64, len(item.Beneficiaries))
+	for i, b := range item.Beneficiaries {
+		weights[i] = b.Weight
+		if allZero {
+			weights[i] = 1
+		}
+	}
+	shares, err := money.Allocate(amount, weights, currency)
+	if err != nil {
+		return itemSplit{}, utils.ErrInvalidInput
+	}
+	return itemSplit{amount: amount, weights: weights, shares: shares}, nil
+}
+
+// Helper: luu cac mon, adjustments va cong don share tung nguoi vao expense_beneficiaries
+func insertItemizedDetails(
+	ctx context.Context,
+	q *database.Queries,
+	expenseID int64,
+	totalAmount money.Amount,
+	conv currencyConversion,
+	req models.CreateTransactionRequest,
+	partMap map[string]int64,
+) error {
+	currency := conv.currency
+	split, err := computeItemizedSplit(currency, req.Items, req.Adjustments)
+	if err != nil {
+		return err
+	}
+	if split.total != totalAmount {
+		return fmt.Errorf("%w: itemized total %s does not match amount %s", utils.ErrInvalidInput, split.total, totalAmount)
+	}
+
+	// Giu thu tu xuat hien dau tien cua tung nguoi
+	var order []string
+	totals := make(map[string]money.Amount)
+	for i, item := range req.Items {
+		created, err := q.CreateExpenseItem(ctx, database.CreateExpenseItemParams{
+			ExpenseID: expenseID,
+			Name:      item.Name,
+			Amount:    item.Amount.Round(currency),
+		})
+		if err != nil {
+			return err
+		}
+		for j, b := range item.Beneficiaries {
+			benID, exists := partMap[b.ParticipantID]
+			if !exists {
+				return errors.New("beneficiary not found: " + b.ParticipantID)
+			}
+			err := q.CreateExpenseItemBeneficiary(ctx, database.CreateExpenseItemBeneficiaryParams{
+				ItemID:        created.ItemID,
+				ParticipantID: benID,
+				Weight:        utils.FloatToNumeric(split.items[i].weights[j]),
+				ShareAmount:   split.items[i].shares[j],
+			})
+			if err != nil {
+				return err
+			}
+			if _, ok := totals[b.ParticipantID]; !ok {
+				order = append(order, b.ParticipantID)
+			}
+			totals[b.ParticipantID] += split.items[i].shares[j]
+		}
+	}
+
+	for i, adj := range req.Adjustments {
+		var percent pgtype.Numeric
+		if adj.Percent != nil {
+			percent = utils.FloatToNumeric(*adj.Percent)
+		}
+		err := q.CreateExpenseAdjustment(ctx, database.CreateExpenseAdjustmentParams{
+			ExpenseID: expenseID,
+			Kind:      adj.Kind,
+			Name:      adj.Name,
+			Percent:   percent,
+			Amount:    split.adjustments[i],
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Mon va adjustments giu currency cua expense, chi share tong cua tung nguoi duoc quy doi
+	shares := make([]money.Amount, len(order))
+	for i, participantID := range order {
+		shares[i] = totals[participantID]
+	}
+	sharesConverted, err := conv.convertParts(totalAmount, shares)
+	if err != nil {
+		return err
+	}
+	for i, participantID := range order {
+		benID := partMap[participantID]
+		share := shares[i]
+		err := q.CreateExpenseBeneficiary(ctx, database.CreateExpenseBeneficiaryParams{
+			ExpenseID:     &expenseID,
+			ParticipantID: &benID,
+			SplitRatio:    utils.FloatToNumeric(share.Float64() / totalAmount.Float64()),
+			ShareAmount:   sharesConverted[i],
+			OriginalShare: share,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Helper: doc lai cac mon va adjustments de tra ve cho client
+func loadExpenseItems(ctx context.Context, q database.Querier, expenseID int64) ([]models.TransactionItem, []models.TransactionAdjustment, error) {
+	items, err := q.GetExpenseItems(ctx, expenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	itemBens, err := q.GetExpenseItemBeneficiaries(ctx, expenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	adjustments, err := q.GetExpenseAdjustments(ctx, expenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bensByItem := make(map[int64][]models.TransactionBeneficiary)
+	for _, b := range itemBens {
+		bensByItem[b.ItemID] = append(bensByItem[b.ItemID], models.TransactionBeneficiary{
+			ParticipantID: b.ParticipantUuid.String(),
+			Weight:        utils.NumericToFloat(b.Weight),
+			Share:         b.ShareAmount,
+		})
+	}
+	itemsResp := make([]models.TransactionItem, 0, len(items))
+	for _, item := range items {
+		itemsResp = append(itemsResp, models.TransactionItem{
+			ID:            item.ItemUuid.String(),
+			Name:          item.Name,
+			Amount:        item.Amount,
+			Beneficiaries: bensByItem[item.ItemID],
+		})
+	}
+	adjustmentsResp := make([]models.TransactionAdjustment, 0, len(adjustments))
+	for _, adj := range adjustments {
+		amount := adj.Amount
+		resp := models.TransactionAdjustment{
+			Kind:   adj.Kind,
+			Name:   adj.Name,
+			Amount: &amount,
+		}
+		if adj.Percent.Valid {
+			pct := utils.NumericToFloat(adj.Percent)
+			resp.Percent = &pct
+		}
+		adjustmentsResp = append(adjustmentsResp, resp)
+	}
+	return itemsResp, adjustmentsResp, nil
+}
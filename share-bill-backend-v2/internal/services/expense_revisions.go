@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	database "BACKEND/internal/db/sqlc"
+	models "BACKEND/internal/dto"
+	utils "BACKEND/internal/utils"
+)
+
+// Helper: chup noi dung hien tai cua expense thanh revision theo version cua no.
+// Revision da ton tai thi giu nguyen (ON CONFLICT DO NOTHING)
+func saveExpenseRevision(ctx context.Context, q database.Querier, expense database.Expense, editedBy *int64) (models.TransactionDetailResponse, error) {
+	detail, err := loadTransactionDetail(ctx, q, expense)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	snapshot, err := json.Marshal(detail)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	// Version 1 chua co revision la do nguoi tao
+	if editedBy == nil && expense.Version == 1 {
+		editedBy = expense.CreatedBy
+	}
+	err = q.CreateExpenseRevision(ctx, database.CreateExpenseRevisionParams{
+		ExpenseID: expense.ExpenseID,
+		Version:   expense.Version,
+		Snapshot:  snapshot,
+		EditedBy:  editedBy,
+	})
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	return detail, nil
+}
+
+// Helper: dung lai request tu ban chup de ghi de nhu 1 lan sua binh thuong
+func revisionRequest(detail models.TransactionDetailResponse) models.CreateTransactionRequest {
+	req := models.CreateTransactionRequest{
+		Description:   detail.Description,
+		Amount:        detail.Amount,
+		SplitMode:     detail.SplitMode,
+		Beneficiaries: detail.Beneficiaries,
+		Items:         detail.Items,
+		Adjustments:   detail.Adjustments,
+		Tags:          detail.Tags,
+	}
+	if detail.Category != nil {
+		req.CategoryID = detail.Category.ID
+	}
+	if !detail.Date.IsZero() {
+		req.SpentAt = detail.Date.Format(time.RFC3339Nano)
+	}
+	// Ban chup truoc khi co multi-currency khong co currency/so tien goc, tinh theo currency cua event.
+	// Khong lay lai ty gia trong ban chup vi event co the da doi currency, dung ty gia hien tai
+	if detail.Currency != "" {
+		req.Currency = detail.Currency
+		req.Amount = detail.OriginalAmount
+	}
+	for _, p := range detail.Payers {
+		amount := p.PaidAmount
+		if detail.Currency != "" {
+			amount = p.OriginalAmount
+		}
+		req.Payers = append(req.Payers, models.TransactionPayer{ParticipantID: p.ID, Amount: &amount})
+	}
+	return req
+}
+
+// Lich su version cua transaction (moi nhat truoc), moi thanh vien deu xem duoc
+func (s *ExpenseService) ListRevisions(ctx context.Context, userID int64, transactionUUIDStr string) ([]models.TransactionRevisionDTO, error) {
+	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	expense, err := s.store.GetExpenseByUUID(ctx, txnUUID)
+	if err != nil {
+		return nil, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, expense.EventID, userID, roleViewer); err != nil {
+		return nil, err
+	}
+	rows, err := s.store.ListExpenseRevisions(ctx, expense.ExpenseID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.TransactionRevisionDTO, 0, len(rows))
+	for _, row := range rows {
+		dto := models.TransactionRevisionDTO{
+			Version:    row.Version,
+			EditedAt:   row.CreatedAt,
+			EditorName: utils.GetStringFromPointer(row.EditorName),
+		}
+		if row.EditorUuid.Valid {
+			dto.EditorID = row.EditorUuid.String()
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
+
+// Noi dung day du cua 1 version
+func (s *ExpenseService) GetRevision(ctx context.Context, userID int64, transactionUUIDStr string, version int32) (models.TransactionRevisionDetail, error) {
+	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
+	if err != nil {
+		return models.TransactionRevisionDetail{}, utils.ErrInvalidInput
+	}
+	expense, err := s.store.GetExpenseByUUID(ctx, txnUUID)
+	if err != nil {
+		return models.TransactionRevisionDetail{}, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, expense.EventID, userID, roleViewer); err != nil {
+		return models.TransactionRevisionDetail{}, err
+	}
+	rev, err := s.store.GetExpenseRevision(ctx, database.GetExpenseRevisionParams{
+		ExpenseID: expense.ExpenseID,
+		Version:   version,
+	})
+	if err != nil {
+		return models.TransactionRevisionDetail{}, utils.ErrNotFound
+	}
+	var detail models.TransactionDetailResponse
+	if err := json.Unmarshal(rev.Snapshot, &detail); err != nil {
+		return models.TransactionRevisionDetail{}, utils.ErrInternalDB
+	}
+	// Ten nguoi sua lay tu danh sach version de khong can them query
+	result := models.TransactionRevisionDetail{
+		TransactionRevisionDTO: models.TransactionRevisionDTO{Version: rev.Version, EditedAt: rev.CreatedAt},
+		Transaction:            detail,
+	}
+	rows, err := s.store.ListExpenseRevisions(ctx, expense.ExpenseID)
+	if err != nil {
+		return models.TransactionRevisionDetail{}, utils.ErrInternalDB
+	}
+	for _, row := range rows {
+		if row.Version == rev.Version {
+			result.EditorName = utils.GetStringFromPointer(row.EditorName)
+			if row.EditorUuid.Valid {
+				result.EditorID = row.EditorUuid.String()
+			}
+			break
+		}
+	}
+	return result, nil
+}
+
+// Khoi phuc transaction ve noi dung cua 1 version cu. Tao version moi, khong xoa lich su
+func (s *ExpenseService) RestoreRevision(ctx context.Context, userID int64, transactionUUIDStr string, version int32, expectedVersion *int32) (models.TransactionDetailResponse, error) {
+	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
+	if err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrInvalidInput
+	}
+	expense, err := s.store.GetExpenseByUUID(ctx, txnUUID)
+	if err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrNotFound
+	}
+	if err := s.requireExpenseEditor(ctx, expense, userID); err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	if expectedVersion != nil && *expectedVersion != expense.Version {
+		return models.TransactionDetailResponse{}, utils.ErrVersionConflict
+	}
+	if version == expense.Version {
+		return models.TransactionDetailResponse{}, fmt.Errorf("%w: version %d is already the current version", utils.ErrInvalidInput, version)
+	}
+	rev, err := s.store.GetExpenseRevision(ctx, database.GetExpenseRevisionParams{
+		ExpenseID: expense.ExpenseID,
+		Version:   version,
+	})
+	if err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrNotFound
+	}
+	var detail models.TransactionDetailResponse
+	if err := json.Unmarshal(rev.Snapshot, &detail); err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrInternalDB
+	}
+	req := revisionRequest(detail)
+	// Danh muc rieng da bi xoa thi khoi phuc thanh chua phan loai
+	if _, err := resolveCategory(ctx, s.store, expense.EventID, req.CategoryID); errors.Is(err, utils.ErrInvalidInput) {
+		req.CategoryID = ""
+	}
+	return s.applyTransactionUpdate(ctx, userID, expense, req, &version)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
+)
+
+// Helper: ban chup luu trong DB la JSON cua detail
+func snapshotRoundTrip(t *testing.T, detail models.TransactionDetailResponse) models.TransactionDetailResponse {
+	t.Helper()
+	raw, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out models.TransactionDetailResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// Expense 12,50 USD trong event VND (ty gia 25.000): request khoi phuc phai dung so tien goc
+func TestRevisionRequestForeignCurrency(t *testing.T) {
+	detail := snapshotRoundTrip(t, models.TransactionDetailResponse{
+		Description:    "Dinner",
+		Amount:         money.FromUnits(312500),
+		SplitMode:      splitModeEqual,
+		Currency:       "USD",
+		ExchangeRate:   25000,
+		OriginalAmount: money.FromCents(1250),
+		Payers: []models.PayerInfo{
+			{ID: "a", PaidAmount: money.FromUnits(200000), OriginalAmount: money.FromCents(800)},
+			{ID: "b", PaidAmount: money.FromUnits(112500), OriginalAmount: money.FromCents(450)},
+		},
+		Beneficiaries: []models.TransactionBeneficiary{{ParticipantID: "a"}, {ParticipantID: "b"}},
+	})
+
+	req := revisionRequest(detail)
+	if req.Currency != "USD" {
+		t.Fatalf("currency = %q, want USD", req.Currency)
+	}
+	amount, err := resolveTotalAmount(req, req.Currency, req.SplitMode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount != detail.OriginalAmount {
+		t.Fatalf("amount = %s, want %s", amount, detail.OriginalAmount)
+	}
+	paid, err := resolvePayerAmounts(amount, req.Currency, req.Payers)
+	if err != nil {
+		t.Fatalf("payers rejected on restore: %v", err)
+	}
+	if paid[0] != money.FromCents(800) || paid[1] != money.FromCents(450) {
+		t.Fatalf("payers = %v", paid)
+	}
+	if _, err := computeBeneficiarySplits(amount, req.Currency, req.SplitMode, req.Beneficiaries); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ban chup cu chua co currency: tinh theo so tien da luu (currency cua event)
+func TestRevisionRequestLegacySnapshot(t *testing.T) {
+	detail := snapshotRoundTrip(t, models.TransactionDetailResponse{
+		Description: "Taxi",
+		Amount:      money.FromUnits(90000),
+		SplitMode:   splitModeEqual,
+		Payers: []models.PayerInfo{
+			{ID: "a", PaidAmount: money.FromUnits(90000)},
+		},
+		Beneficiaries: []models.TransactionBeneficiary{{ParticipantID: "a"}, {ParticipantID: "b"}},
+	})
+
+	req := revisionRequest(detail)
+	if req.Currency != "" || req.Amount != detail.Amount {
+		t.Fatalf("got currency %q amount %s", req.Currency, req.Amount)
+	}
+	if _, err := resolvePayerAmounts(req.Amount, "VND", req.Payers); err != nil {
+		t.Fatal(err)
+	}
+}
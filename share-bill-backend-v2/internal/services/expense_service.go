@@ -3,19 +3,27 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	database "BACKEND/internal/db/sqlc"
 	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
 	utils "BACKEND/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type ExpenseService struct {
-	store database.Store
+	store          database.Store
+	trashRetention time.Duration
 }
 
-// Khoi tao ExpenseService
-func NewExpenseService(store database.Store) *ExpenseService {
-	return &ExpenseService{store: store}
+// Khoi tao ExpenseService, transaction da xoa duoc giu trashRetention truoc khi bi purge
+func NewExpenseService(store database.Store, retention time.Duration) *ExpenseService {
+	return &ExpenseService{store: store, trashRetention: trashRetention(retention)}
 } 
 
 // Tao transaction va chen payers + beneficiaries trong DB
@@ -27,43 +35,90 @@ func (s *ExpenseService) CreateTransaction(ctx context.Context, userID int64, ev
 	if len(req.Payers) == 0 {
 		return models.TransactionResponse{}, errors.New("at least one payer is required")
 	}
-	if len(req.Beneficiaries) == 0 {
-		return models.TransactionResponse{}, errors.New("at least one beneficiary is required")
-	}
 	event, err := s.store.GetEventByUUID(ctx, eventUUID)
 	if err != nil {
 		return models.TransactionResponse{}, utils.ErrNotFound
 	}
-	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
-		EventID: event.EventID,
-		UserID:  &userID,
-	})
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleMember); err != nil {
+		return models.TransactionResponse{}, err
+	}
+	if event.IsClosed {
+		return models.TransactionResponse{}, utils.ErrEventClosed
+	}
+	splitMode, err := normalizeSplitMode(req.SplitMode)
 	if err != nil {
-		return models.TransactionResponse{}, utils.ErrPermissionDenied
+		return models.TransactionResponse{}, err
+	}
+	if len(req.Beneficiaries) == 0 && splitMode != splitModeItemized {
+		return models.TransactionResponse{}, errors.New("at least one beneficiary is required")
+	}
+	conv, err := resolveConversion(ctx, s.store, event, req, nil)
+	if err != nil {
+		return models.TransactionResponse{}, err
+	}
+	category, err := resolveCategory(ctx, s.store, event.EventID, req.CategoryID)
+	if err != nil {
+		return models.TransactionResponse{}, err
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return models.TransactionResponse{}, err
+	}
+	spentAt := time.Now()
+	if req.SpentAt != "" {
+		spentAt, err = parseSpentAt(req.SpentAt, eventLocation(event))
+		if err != nil {
+			return models.TransactionResponse{}, err
+		}
+	}
+	amount, err := resolveTotalAmount(req, conv.currency, splitMode)
+	if err != nil {
+		return models.TransactionResponse{}, err
+	}
+	converted, err := conv.convert(amount)
+	if err != nil {
+		return models.TransactionResponse{}, err
 	}
 
 	participantsDB, err := s.store.ListParticipantsByEventID(ctx, event.EventID)
 	if err != nil {
 		return models.TransactionResponse{}, utils.ErrInternalDB
 	}
-	partMap := make(map[string]int64)
-	for _, p := range participantsDB {
-		partMap[p.ParticipantUuid.String()] = p.ParticipantID
-	}
+	partMap := participantIDMap(participantsDB, nil)
 
 	var createdExpenseUUID string
 
 	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
 		expense, err := q.CreateExpense(ctx, database.CreateExpenseParams{
-			EventID:     event.EventID,
-			Description: req.Description, 
-			TotalAmount: utils.FloatToNumeric(req.Amount),
+			EventID:        event.EventID,
+			Description:    req.Description, 
+			TotalAmount:    converted,
+			SplitMode:      splitMode,
+			CreatedBy:      &userID,
+			Currency:       conv.currency,
+			ExchangeRate:   ratNumeric(conv.rate),
+			OriginalAmount: amount,
+			CategoryID:     categoryID(category),
+			SpentAt:        spentAt,
 		})
 		if err != nil {
 			return utils.ErrInternalDB
 		}
 		createdExpenseUUID = expense.ExpenseUuid.String()
-		return s.insertExpenseDetails(ctx, q, expense.ExpenseID, req.Amount, req.Payers, req.Beneficiaries, partMap)
+		if err := s.insertExpenseDetails(ctx, q, expense.ExpenseID, amount, conv, splitMode, req, partMap); err != nil {
+			return err
+		}
+		if err := saveExpenseTags(ctx, q, expense.ExpenseID, tags); err != nil {
+			return utils.ErrInternalDB
+		}
+		if _, err := saveExpenseRevision(ctx, q, expense, &userID); err != nil {
+			return utils.ErrInternalDB
+		}
+		after, err := loadExpenseSnapshot(ctx, q, expense)
+		if err != nil {
+			return utils.ErrInternalDB
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityExpenseCreated, entityExpense, expense.ExpenseUuid, map[string]any{"after": after})
 	})
 
 	if err != nil {
@@ -97,67 +152,185 @@ func (s *ExpenseService) GetTransaction(ctx context.Context, userID int64, trans
 		return models.TransactionDetailResponse{}, utils.ErrPermissionDenied
 	}
 
-	payers, _ := s.store.GetExpensePayers(ctx, expense.ExpenseID)
-	bens, _ := s.store.GetExpenseBeneficiaries(ctx, &expense.ExpenseID)
+	resp, err := loadTransactionDetail(ctx, s.store, expense)
+	if err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrInternalDB
+	}
+	resp.Attachments, err = loadAttachmentDTOs(ctx, s.store, expense)
+	if err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrInternalDB
+	}
+	return resp, nil
+}
+
+// Helper: doc day du transaction (payers, shares, mon), dung cho response va ban chup revision
+func loadTransactionDetail(ctx context.Context, q database.Querier, expense database.Expense) (models.TransactionDetailResponse, error) {
+	payers, err := q.GetExpensePayers(ctx, expense.ExpenseID)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	bens, err := q.GetExpenseBeneficiaries(ctx, &expense.ExpenseID)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
 	var payersResp []models.PayerInfo
 	for _, p := range payers {
 		payersResp = append(payersResp, models.PayerInfo{
-			ID:   p.ParticipantUuid.String(),
-			Name: p.Name, 
+			ID:             p.ParticipantUuid.String(),
+			Name:           p.Name, 
+			PaidAmount:     p.PaidAmount,
+			OriginalAmount: p.OriginalAmount,
 		})
 	}
 
 	var bensResp []models.TransactionBeneficiary
 	for _, b := range bens {
-		bensResp = append(bensResp, models.TransactionBeneficiary{
+		originalShare := b.OriginalShare
+		ben := models.TransactionBeneficiary{
 			ParticipantID: b.ParticipantUuid.String(),
-			Weight:        utils.NumericToFloat(b.SplitRatio), 
-		})
+			Share:         b.ShareAmount,
+			OriginalShare: &originalShare,
+		}
+		applySplitInput(&ben, expense.SplitMode, b.SplitInput, b.SplitRatio)
+		bensResp = append(bensResp, ben)
 	}
-	return models.TransactionDetailResponse{
+	resp := models.TransactionDetailResponse{
 		ID:            expense.ExpenseUuid.String(),
 		Description:   expense.Description,
-		Amount:        utils.NumericToFloat(expense.TotalAmount),
-		Date:          expense.CreatedAt.Time,
+		Amount:        expense.TotalAmount,
+		Date:          expense.SpentAt,
+		CreatedAt:     expense.CreatedAt.Time,
 		Payers:        payersResp,
 		Beneficiaries: bensResp,
-	}, nil
+		SplitMode:     expense.SplitMode,
+		Version:       expense.Version,
+		Currency:      expense.Currency,
+		OriginalAmount: expense.OriginalAmount,
+	}
+	resp.ExchangeRate, _ = numericRat(expense.ExchangeRate).Float64()
+	if expense.CategoryID != nil {
+		category, err := q.GetCategoryByID(ctx, *expense.CategoryID)
+		if err != nil {
+			return models.TransactionDetailResponse{}, err
+		}
+		dto := toCategoryDTO(category)
+		resp.Category = &dto
+	}
+	resp.Tags, err = q.ListExpenseTags(ctx, expense.ExpenseID)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if expense.SplitMode == splitModeItemized {
+		resp.Items, resp.Adjustments, err = loadExpenseItems(ctx, q, expense.ExpenseID)
+		if err != nil {
+			return models.TransactionDetailResponse{}, err
+		}
+	}
+	return resp, nil
 }
 
-
-// Cap nhat transaction (xoa va chen lai chi tiet)
-func (s *ExpenseService) UpdateTransaction(ctx context.Context, userID int64, transactionUUIDStr string, req models.CreateTransactionRequest) error {
+// Cap nhat transaction (xoa va chen lai chi tiet). expectedVersion lay tu If-Match, nil thi khong kiem tra
+func (s *ExpenseService) UpdateTransaction(ctx context.Context, userID int64, transactionUUIDStr string, req models.CreateTransactionRequest, expectedVersion *int32) (models.TransactionDetailResponse, error) {
 	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
 	if err != nil {
-		return utils.ErrInvalidInput
+		return models.TransactionDetailResponse{}, utils.ErrInvalidInput
 	}
 	expense, err := s.store.GetExpenseByUUID(ctx, txnUUID)
 	if err != nil {
-		return utils.ErrNotFound
+		return models.TransactionDetailResponse{}, utils.ErrNotFound
 	}
-	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
-		EventID: expense.EventID,
-		UserID:  &userID,
-	})
+	if err := s.requireExpenseEditor(ctx, expense, userID); err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	if expectedVersion != nil && *expectedVersion != expense.Version {
+		return models.TransactionDetailResponse{}, utils.ErrVersionConflict
+	}
+	return s.applyTransactionUpdate(ctx, userID, expense, req, nil)
+}
+
+// Helper: ghi de noi dung expense theo req, tang version va luu revision moi.
+// restoredFrom khac nil khi dang khoi phuc tu 1 revision cu
+func (s *ExpenseService) applyTransactionUpdate(ctx context.Context, userID int64, expense database.Expense, req models.CreateTransactionRequest, restoredFrom *int32) (models.TransactionDetailResponse, error) {
+	event, err := s.store.GetEventByID(ctx, expense.EventID)
+	if err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrInternalDB
+	}
+	if event.IsClosed {
+		return models.TransactionDetailResponse{}, utils.ErrEventClosed
+	}
+	splitMode, err := normalizeSplitMode(req.SplitMode)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	conv, err := resolveConversion(ctx, s.store, event, req, &expense)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	category, err := resolveCategory(ctx, s.store, event.EventID, req.CategoryID)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	tags, err := normalizeTags(req.Tags)
 	if err != nil {
-		return utils.ErrPermissionDenied
+		return models.TransactionDetailResponse{}, err
+	}
+	// Khong gui spentAt thi giu thoi diem chi tieu cu
+	spentAt := expense.SpentAt
+	if req.SpentAt != "" {
+		spentAt, err = parseSpentAt(req.SpentAt, eventLocation(event))
+		if err != nil {
+			return models.TransactionDetailResponse{}, err
+		}
+	}
+	amount, err := resolveTotalAmount(req, conv.currency, splitMode)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	converted, err := conv.convert(amount)
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
 	}
 
 	participants, err := s.store.ListParticipantsByEventID(ctx, expense.EventID)
 	if err != nil {
-		return utils.ErrInternalDB
+		return models.TransactionDetailResponse{}, utils.ErrInternalDB
 	}
-	partMap := make(map[string]int64)
-	for _, p := range participants {
-		partMap[p.ParticipantUuid.String()] = p.ParticipantID
+	// Participant da bi xoa van duoc giu neu da co san trong expense nay
+	existing, err := s.expenseParticipantUUIDs(ctx, expense.ExpenseID)
+	if err != nil {
+		return models.TransactionDetailResponse{}, utils.ErrInternalDB
 	}
+	partMap := participantIDMap(participants, existing)
 
-	return s.store.ExecTx(ctx, func(q *database.Queries) error {
-		_, err := q.UpdateExpense(ctx, database.UpdateExpenseParams{
-			ExpenseID:   expense.ExpenseID,
-			Description: req.Description,
-			TotalAmount: utils.FloatToNumeric(req.Amount),
+	var detail models.TransactionDetailResponse
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		// Expense tao truoc khi co lich su thi chup lai version hien tai truoc khi ghi de
+		if _, err := saveExpenseRevision(ctx, q, expense, nil); err != nil {
+			return err
+		}
+		before, err := loadExpenseSnapshot(ctx, q, expense)
+		if err != nil {
+			return err
+		}
+		updated, err := q.UpdateExpense(ctx, database.UpdateExpenseParams{
+			ExpenseID:       expense.ExpenseID,
+			Description:     req.Description,
+			TotalAmount:     converted,
+			SplitMode:       splitMode,
+			Currency:        conv.currency,
+			ExchangeRate:    ratNumeric(conv.rate),
+			OriginalAmount:  amount,
+			CategoryID:      categoryID(category),
+			SpentAt:         spentAt,
+			ExpectedVersion: expense.Version,
 		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Co nguoi khac vua sua (hoac xoa) giua luc doc va ghi
+			return utils.ErrVersionConflict
+		}
 		if err != nil {
 			return err
 		}
@@ -167,12 +340,45 @@ func (s *ExpenseService) UpdateTransaction(ctx context.Context, userID int64, tr
 		if err := q.DeleteExpenseBeneficiaries(ctx, &expense.ExpenseID); err != nil {
 			return err
 		}
-		return s.insertExpenseDetails(ctx, q, expense.ExpenseID, req.Amount, req.Payers, req.Beneficiaries, partMap)
+		if err := q.DeleteExpenseItems(ctx, expense.ExpenseID); err != nil {
+			return err
+		}
+		if err := q.DeleteExpenseAdjustments(ctx, expense.ExpenseID); err != nil {
+			return err
+		}
+		if err := s.insertExpenseDetails(ctx, q, expense.ExpenseID, amount, conv, splitMode, req, partMap); err != nil {
+			return err
+		}
+		if err := saveExpenseTags(ctx, q, expense.ExpenseID, tags); err != nil {
+			return err
+		}
+		detail, err = saveExpenseRevision(ctx, q, updated, &userID)
+		if err != nil {
+			return err
+		}
+		after, err := loadExpenseSnapshot(ctx, q, updated)
+		if err != nil {
+			return err
+		}
+		changes := diffExpenseSnapshots(before, after)
+		if len(changes) == 0 && restoredFrom == nil {
+			return nil
+		}
+		data := map[string]any{"changes": changes, "version": updated.Version}
+		if restoredFrom != nil {
+			data["restoredFromVersion"] = *restoredFrom
+		}
+		return recordActivity(ctx, q, expense.EventID, userID, activityExpenseUpdated, entityExpense, expense.ExpenseUuid, data)
 	})
+	if err != nil {
+		return models.TransactionDetailResponse{}, err
+	}
+	return detail, nil
 }
 
-// Xoa transaction
-func (s *ExpenseService) DeleteTransaction(ctx context.Context, userID int64, transactionUUIDStr string) error {
+// Xoa mem transaction: chuyen vao thung rac, khoi phuc duoc trong han luu tru.
+// expectedVersion lay tu If-Match, nil thi khong kiem tra
+func (s *ExpenseService) DeleteTransaction(ctx context.Context, userID int64, transactionUUIDStr string, expectedVersion *int32) error {
 	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
 	if err != nil {
 		return utils.ErrInvalidInput
@@ -181,18 +387,175 @@ func (s *ExpenseService) DeleteTransaction(ctx context.Context, userID int64, tr
 	if err != nil {
 		return utils.ErrNotFound
 	}
-	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
-		EventID: expense.EventID,
-		UserID:  &userID,
+	if err := s.requireExpenseEditor(ctx, expense, userID); err != nil {
+		return err
+	}
+	if expectedVersion != nil && *expectedVersion != expense.Version {
+		return utils.ErrVersionConflict
+	}
+	event, err := s.store.GetEventByID(ctx, expense.EventID)
+	if err != nil {
+		return utils.ErrInternalDB
+	}
+	if event.IsClosed {
+		return utils.ErrEventClosed
+	}
+	return s.store.ExecTx(ctx, func(q *database.Queries) error {
+		before, err := loadExpenseSnapshot(ctx, q, expense)
+		if err != nil {
+			return err
+		}
+		affected, err := q.SoftDeleteExpense(ctx, database.SoftDeleteExpenseParams{
+			ExpenseID:       expense.ExpenseID,
+			ExpectedVersion: expectedVersion,
+		})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			// Bi sua dong thoi sau lan doc o tren thi tra conflict, con lai la da bi xoa
+			current, err := q.GetExpenseByUUID(ctx, txnUUID)
+			if err == nil && expectedVersion != nil && current.Version != *expectedVersion {
+				return utils.ErrVersionConflict
+			}
+			return utils.ErrNotFound
+		}
+		return recordActivity(ctx, q, expense.EventID, userID, activityExpenseDeleted, entityExpense, expense.ExpenseUuid, map[string]any{"before": before})
 	})
+}
+
+// Khoi phuc transaction tu thung rac. Cung quyen nhu xoa; khong khoi phuc neu co participant da bi xoa khoi event
+func (s *ExpenseService) RestoreTransaction(ctx context.Context, userID int64, transactionUUIDStr string) error {
+	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
 	if err != nil {
-		return utils.ErrPermissionDenied
+		return utils.ErrInvalidInput
 	}
-	return s.store.DeleteExpense(ctx, expense.ExpenseID)
+	expense, err := s.store.GetDeletedExpenseByUUID(ctx, txnUUID)
+	if err != nil || trashExpired(expense.DeletedAt, s.trashRetention) {
+		return utils.ErrNotFound
+	}
+	if err := s.requireExpenseEditor(ctx, expense, userID); err != nil {
+		return err
+	}
+	event, err := s.store.GetEventByID(ctx, expense.EventID)
+	if err != nil {
+		return utils.ErrNotFound
+	}
+	if event.IsClosed {
+		return utils.ErrEventClosed
+	}
+	// So du cua nguoi da roi event da duoc chot, khoi phuc se lam lech lai
+	participants, err := s.store.ListParticipantsByEventID(ctx, expense.EventID)
+	if err != nil {
+		return utils.ErrInternalDB
+	}
+	involved, err := s.expenseParticipantUUIDs(ctx, expense.ExpenseID)
+	if err != nil {
+		return utils.ErrInternalDB
+	}
+	for _, p := range participants {
+		if p.RemovedAt.Valid && involved[p.ParticipantUuid.String()] {
+			return fmt.Errorf("%w: transaction involves removed participant %s", utils.ErrInvalidInput, p.Name)
+		}
+	}
+
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		restored, err := q.RestoreExpense(ctx, expense.ExpenseID)
+		if err != nil {
+			return err
+		}
+		after, err := loadExpenseSnapshot(ctx, q, restored)
+		if err != nil {
+			return err
+		}
+		return recordActivity(ctx, q, expense.EventID, userID, activityExpenseRestored, entityExpense, expense.ExpenseUuid, map[string]any{"after": after})
+	})
+	if err != nil {
+		// Nguoi khac vua khoi phuc hoac job purge vua xoa
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.ErrNotFound
+		}
+		return utils.ErrInternalDB
+	}
+	return nil
 }
 
-// Liet ke transactions trong event
-func (s *ExpenseService) ListTransactions(ctx context.Context, userID int64, eventUUIDStr string) ([]models.TransactionDTO, error) {
+// Liet ke transaction trong thung rac cua event (moi xoa truoc)
+func (s *ExpenseService) ListDeletedTransactions(ctx context.Context, userID int64, eventUUIDStr string) ([]models.DeletedTransactionDTO, error) {
+	eventUUID, err := utils.StringToUUID(eventUUIDStr)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUID)
+	if err != nil {
+		return nil, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleViewer); err != nil {
+		return nil, err
+	}
+	rows, err := s.store.ListDeletedExpensesByEventID(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.DeletedTransactionDTO, 0, len(rows))
+	for _, row := range rows {
+		if trashExpired(row.DeletedAt, s.trashRetention) {
+			continue
+		}
+		result = append(result, models.DeletedTransactionDTO{
+			ID:          row.ExpenseUuid.String(),
+			Description: row.Description,
+			Amount:      row.TotalAmount,
+			Date:        row.SpentAt,
+			DeletedAt:   row.DeletedAt.Time,
+			PurgeAt:     purgeAt(row.DeletedAt, s.trashRetention),
+		})
+	}
+	return result, nil
+}
+
+// Lich su thay doi cua transaction (cu nhat truoc), moi thanh vien deu xem duoc
+func (s *ExpenseService) GetTransactionHistory(ctx context.Context, userID int64, transactionUUIDStr string) ([]models.ActivityDTO, error) {
+	txnUUID, err := utils.StringToUUID(transactionUUIDStr)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	expense, err := s.store.GetExpenseByUUID(ctx, txnUUID)
+	if err != nil {
+		return nil, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, expense.EventID, userID, roleViewer); err != nil {
+		return nil, err
+	}
+	rows, err := s.store.ListEntityActivities(ctx, database.ListEntityActivitiesParams{
+		EventID:    expense.EventID,
+		EntityType: entityExpense,
+		EntityUuid: expense.ExpenseUuid,
+	})
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.ActivityDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toActivityDTO(database.ListEventActivitiesRow(row)))
+	}
+	return result, nil
+}
+
+// Helper: admin tro len sua/xoa moi transaction, member chi sua/xoa transaction do minh tao
+func (s *ExpenseService) requireExpenseEditor(ctx context.Context, expense database.Expense, userID int64) error {
+	me, err := requireRole(ctx, s.store, expense.EventID, userID, roleMember)
+	if err != nil {
+		return err
+	}
+	if hasRole(me.Role, roleAdmin) || (expense.CreatedBy != nil && *expense.CreatedBy == userID) {
+		return nil
+	}
+	return utils.ErrPermissionDenied
+}
+
+// Liet ke transactions trong event theo thoi diem chi tieu (moi nhat truoc), loc theo filter
+func (s *ExpenseService) ListTransactions(ctx context.Context, userID int64, eventUUIDStr string, filter models.TransactionFilter) ([]models.TransactionDTO, error) {
 	eventUUID, err := utils.StringToUUID(eventUUIDStr)
 	if err != nil {
 		return nil, utils.ErrInvalidInput
@@ -209,7 +572,41 @@ func (s *ExpenseService) ListTransactions(ctx context.Context, userID int64, eve
 		return nil, utils.ErrPermissionDenied
 	}
 
-	rawList, err := s.store.ListExpensesByEventID(ctx, event.EventID)
+	arg := database.ListExpensesByEventIDParams{EventID: event.EventID}
+	if strings.EqualFold(strings.TrimSpace(filter.Category), categoryFilterNone) {
+		arg.Uncategorized = true
+	} else {
+		found, err := resolveCategory(ctx, s.store, event.EventID, filter.Category)
+		if err != nil {
+			return nil, err
+		}
+		arg.CategoryID = categoryID(found)
+	}
+	if tag := normalizeTag(filter.Tag); tag != "" {
+		arg.Tag = &tag
+	}
+	// Ngay trong filter hieu theo mui gio cua event
+	loc := eventLocation(event)
+	from, err := parseDateBound(filter.From, loc, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDateBound(filter.To, loc, true)
+	if err != nil {
+		return nil, err
+	}
+	if from != nil {
+		arg.SpentFrom = pgtype.Timestamptz{Time: *from, Valid: true}
+	}
+	if to != nil {
+		arg.SpentTo = pgtype.Timestamptz{Time: *to, Valid: true}
+	}
+	categories, err := loadCategoryDTOs(ctx, s.store, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+
+	rawList, err := s.store.ListExpensesByEventID(ctx, arg)
 	if err != nil {
 		return nil, utils.ErrInternalDB
 	}
@@ -224,9 +621,17 @@ func (s *ExpenseService) ListTransactions(ctx context.Context, userID int64, eve
 		dto := models.TransactionDTO{
 			ID:          row.ExpenseUuid.String(),
 			Description: row.Description,
-			Amount:      utils.NumericToFloat(row.TotalAmount),
-			Date:        row.CreatedAt.Time,
+			Amount:      row.TotalAmount,
+			Date:        row.SpentAt,
+			CreatedAt:   row.CreatedAt.Time,
 			PayerNames:  payerNames, 
+			Currency:       row.Currency,
+			OriginalAmount: row.OriginalAmount,
+			Category:       categoryDTOByID(categories, row.CategoryID),
+			Tags:           row.Tags,
+		}
+		if dto.Tags == nil {
+			dto.Tags = []string{}
 		}
 		result = append(result, dto)
 	}
@@ -234,56 +639,82 @@ func (s *ExpenseService) ListTransactions(ctx context.Context, userID int64, eve
 }
 
 // Helper: chen payers va beneficiaries cho expense
+// Tien cua payers va beneficiaries duoc chia bang money.Allocate nen tong luon bang totalAmount.
+// Share cua beneficiaries tinh theo splitMode (xem expense_split.go).
+// totalAmount va moi so tien trong req tinh theo currency cua expense, conv quy doi sang currency cua event
 func (s *ExpenseService) insertExpenseDetails(
 	ctx context.Context,
 	q *database.Queries,
 	expenseID int64,
-	totalAmount float64,
-	payerUUIDs []string, 
-	beneficiaries []models.TransactionBeneficiary, 
+	totalAmount money.Amount,
+	conv currencyConversion,
+	splitMode string,
+	req models.CreateTransactionRequest,
 	partMap map[string]int64,
 ) error {
-	if len(payerUUIDs) == 0 {
+	currency := conv.currency
+	payers := req.Payers
+	if len(payers) == 0 {
 		return errors.New("at least one payer required")
 	}
-	amountPerPayer := totalAmount / float64(len(payerUUIDs))
+	paidAmounts, err := resolvePayerAmounts(totalAmount, currency, payers)
+	if err != nil {
+		return err
+	}
+	paidConverted, err := conv.convertParts(totalAmount, paidAmounts)
+	if err != nil {
+		return err
+	}
 
-	for _, payerUUID := range payerUUIDs {
-		payerID, exists := partMap[payerUUID]
+	for i, payer := range payers {
+		payerID, exists := partMap[payer.ParticipantID]
 		if !exists {
-			return errors.New("payer not found: " + payerUUID)
+			return errors.New("payer not found: " + payer.ParticipantID)
 		}
 
 		err := q.CreateExpensePayer(ctx, database.CreateExpensePayerParams{
-			ExpenseID:     expenseID,
-			ParticipantID: &payerID,
-			PaidAmount:    utils.FloatToNumeric(amountPerPayer),
+			ExpenseID:      expenseID,
+			ParticipantID:  &payerID,
+			PaidAmount:     paidConverted[i],
+			OriginalAmount: paidAmounts[i],
 		})
 		if err != nil {
 			return err
 		}
 	}
 
-	var totalWeight float64 = 0
-	for _, b := range beneficiaries {
-		totalWeight += b.Weight
+	if splitMode == splitModeItemized {
+		return insertItemizedDetails(ctx, q, expenseID, totalAmount, conv, req, partMap)
 	}
 
-	if totalWeight <= 0 {
-		return errors.New("total weight must be greater than 0")
+	splits, err := computeBeneficiarySplits(totalAmount, currency, splitMode, req.Beneficiaries)
+	if err != nil {
+		return err
+	}
+	shares := make([]money.Amount, len(splits))
+	for i, split := range splits {
+		shares[i] = split.share
+	}
+	sharesConverted, err := conv.convertParts(totalAmount, shares)
+	if err != nil {
+		return err
 	}
 
-	for _, b := range beneficiaries {
+	for i, b := range req.Beneficiaries {
 		benID, exists := partMap[b.ParticipantID]
 		if !exists {
 			return errors.New("beneficiary not found: " + b.ParticipantID)
 		}
-		ratio := b.Weight / totalWeight
+		// split_ratio chi con la thong tin tham khao, so tien that nam o share_amount
+		ratio := splits[i].share.Float64() / totalAmount.Float64()
 		
 		err := q.CreateExpenseBeneficiary(ctx, database.CreateExpenseBeneficiaryParams{
 			ExpenseID:     &expenseID,
 			ParticipantID: &benID,
 			SplitRatio:    utils.FloatToNumeric(ratio),
+			ShareAmount:   sharesConverted[i],
+			SplitInput:    splits[i].input,
+			OriginalShare: splits[i].share,
 		})
 		if err != nil {
 			return err
@@ -291,4 +722,103 @@ func (s *ExpenseService) insertExpenseDetails(
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// Helper: xac dinh tong tien da lam tron theo currency (VND: 0 so le, USD: 2).
+// Voi itemized thi tong tinh tu cac mon, amount gui len (neu co) phai khop.
+func resolveTotalAmount(req models.CreateTransactionRequest, currency string, splitMode string) (money.Amount, error) {
+	amount := req.Amount.Round(currency)
+	if splitMode == splitModeItemized {
+		split, err := computeItemizedSplit(currency, req.Items, req.Adjustments)
+		if err != nil {
+			return 0, err
+		}
+		if amount != 0 && amount != split.total {
+			return 0, fmt.Errorf("%w: amount %s does not match itemized total %s", utils.ErrInvalidInput, amount, split.total)
+		}
+		return split.total, nil
+	}
+	if amount <= 0 {
+		return 0, utils.ErrInvalidInput
+	}
+	return amount, nil
+}
+
+// Helper: tinh so tien tung payer da tra.
+// Neu khong payer nao co amount thi chia deu; neu co thi tat ca phai co va tong phai bang totalAmount.
+func resolvePayerAmounts(totalAmount money.Amount, currency string, payers []models.TransactionPayer) ([]money.Amount, error) {
+	seen := make(map[string]bool, len(payers))
+	withAmount := 0
+	for _, p := range payers {
+		if seen[p.ParticipantID] {
+			return nil, fmt.Errorf("%w: duplicate payer %s", utils.ErrInvalidInput, p.ParticipantID)
+		}
+		seen[p.ParticipantID] = true
+		if p.Amount != nil {
+			withAmount++
+		}
+	}
+	if withAmount == 0 {
+		amounts, err := money.AllocateEqual(totalAmount, len(payers), currency)
+		if err != nil {
+			return nil, utils.ErrInvalidInput
+		}
+		return amounts, nil
+	}
+	if withAmount != len(payers) {
+		return nil, fmt.Errorf("%w: either all payers or none must have an amount", utils.ErrInvalidInput)
+	}
+
+	amounts := make([]money.Amount, len(payers))
+	for i, p := range payers {
+		amount := p.Amount.Round(currency)
+		if amount <= 0 {
+			return nil, fmt.Errorf("%w: payer amount must be greater than 0", utils.ErrInvalidInput)
+		}
+		amounts[i] = amount
+	}
+	if sum := money.Sum(amounts); sum != totalAmount {
+		return nil, fmt.Errorf("%w: payer amounts sum to %s but total is %s", utils.ErrInvalidInput, sum, totalAmount)
+	}
+	return amounts, nil
+}
+
+// Helper: map participant UUID -> ID cho expense moi/sua, bo qua nguoi da bi xoa tru khi co trong keep
+func participantIDMap(participants []database.ListParticipantsByEventIDRow, keep map[string]bool) map[string]int64 {
+	partMap := make(map[string]int64)
+	for _, p := range participants {
+		uuidStr := p.ParticipantUuid.String()
+		if p.RemovedAt.Valid && !keep[uuidStr] {
+			continue
+		}
+		partMap[uuidStr] = p.ParticipantID
+	}
+	return partMap
+}
+
+// Helper: UUID cac participant dang co trong expense (payer, beneficiary, mon)
+func (s *ExpenseService) expenseParticipantUUIDs(ctx context.Context, expenseID int64) (map[string]bool, error) {
+	result := make(map[string]bool)
+	payers, err := s.store.GetExpensePayers(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payers {
+		result[p.ParticipantUuid.String()] = true
+	}
+	beneficiaries, err := s.store.GetExpenseBeneficiaries(ctx, &expenseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range beneficiaries {
+		result[b.ParticipantUuid.String()] = true
+	}
+	items, err := s.store.GetExpenseItemBeneficiaries(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ib := range items {
+		result[ib.ParticipantUuid.String()] = true
+	}
+	return result, nil
+}
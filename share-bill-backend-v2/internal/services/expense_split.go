@@ -0,0 +1,216 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+
+	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
+	utils "BACKEND/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	splitModeEqual      = "equal"
+	splitModeShares     = "shares"
+	splitModePercentage = "percentage"
+	splitModeExact      = "exact"
+	splitModeAdjustment = "adjustment"
+	splitModeItemized   = "itemized" // Chia theo tung mon, xem expense_items.go
+)
+
+// Ket qua chia tien cho 1 beneficiary: so tien phai chiu va gia tri nguoi dung da nhap
+type beneficiarySplit struct {
+	share money.Amount
+	input pgtype.Numeric // NULL khi mode khong co input (equal, exact bo trong)
+}
+
+// Chuan hoa split mode, mac dinh la "shares" (weight tuong doi nhu truoc day)
+func normalizeSplitMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return splitModeShares, nil
+	case splitModeEqual, splitModeShares, splitModePercentage, splitModeExact, splitModeAdjustment, splitModeItemized:
+		return mode, nil
+	}
+	return "", fmt.Errorf("%w: unknown split mode %q", utils.ErrInvalidInput, mode)
+}
+
+// Tinh so tien tung beneficiary phai chiu theo split mode. Tong cac share luon bang total.
+func computeBeneficiarySplits(total money.Amount, currency string, mode string, bens []models.TransactionBeneficiary) ([]beneficiarySplit, error) {
+	if len(bens) == 0 {
+		return nil, fmt.Errorf("%w: at least one beneficiary is required", utils.ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(bens))
+	for _, b := range bens {
+		if seen[b.ParticipantID] {
+			return nil, fmt.Errorf("%w: duplicate beneficiary %s", utils.ErrInvalidInput, b.ParticipantID)
+		}
+		seen[b.ParticipantID] = true
+	}
+
+	switch mode {
+	case splitModeEqual:
+		return splitEqual(total, currency, bens)
+	case splitModeShares:
+		return splitShares(total, currency, bens)
+	case splitModePercentage:
+		return splitPercentage(total, currency, bens)
+	case splitModeExact:
+		return splitExact(total, currency, bens)
+	case splitModeAdjustment:
+		return splitAdjustment(total, currency, bens)
+	}
+	return nil, fmt.Errorf("%w: unknown split mode %q", utils.ErrInvalidInput, mode)
+}
+
+func splitEqual(total money.Amount, currency string, bens []models.TransactionBeneficiary) ([]beneficiarySplit, error) {
+	shares, err := money.AllocateEqual(total, len(bens), currency)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	result := make([]beneficiarySplit, len(bens))
+	for i := range bens {
+		result[i] = beneficiarySplit{share: shares[i]}
+	}
+	return result, nil
+}
+
+func splitShares(total money.Amount, currency string, bens []models.TransactionBeneficiary) ([]beneficiarySplit, error) {
+	weights := make([]float64, len(bens))
+	for i, b := range bens {
+		if b.Weight < 0 {
+			return nil, fmt.Errorf("%w: weight must not be negative", utils.ErrInvalidInput)
+		}
+		weights[i] = b.Weight
+	}
+	shares, err := money.Allocate(total, weights, currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: total weight must be greater than 0", utils.ErrInvalidInput)
+	}
+	result := make([]beneficiarySplit, len(bens))
+	for i, b := range bens {
+		result[i] = beneficiarySplit{share: shares[i], input: utils.FloatToNumeric(b.Weight)}
+	}
+	return result, nil
+}
+
+func splitPercentage(total money.Amount, currency string, bens []models.TransactionBeneficiary) ([]beneficiarySplit, error) {
+	weights := make([]*big.Rat, len(bens))
+	sum := new(big.Rat)
+	for i, b := range bens {
+		if b.Percentage == nil || *b.Percentage < 0 {
+			return nil, fmt.Errorf("%w: every beneficiary needs a non-negative percentage", utils.ErrInvalidInput)
+		}
+		r, ok := money.WeightRat(*b.Percentage)
+		if !ok {
+			return nil, utils.ErrInvalidInput
+		}
+		weights[i] = r
+		sum.Add(sum, r)
+	}
+	if sum.Cmp(big.NewRat(100, 1)) != 0 {
+		return nil, fmt.Errorf("%w: percentages must sum to 100, got %s", utils.ErrInvalidInput, sum.FloatString(4))
+	}
+	shares, err := money.AllocateRat(total, weights, currency)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	result := make([]beneficiarySplit, len(bens))
+	for i, b := range bens {
+		result[i] = beneficiarySplit{share: shares[i], input: utils.FloatToNumeric(*b.Percentage)}
+	}
+	return result, nil
+}
+
+// Exact: ai co amount thi chiu dung so do, nhung nguoi bo trong chia deu phan con lai
+func splitExact(total money.Amount, currency string, bens []models.TransactionBeneficiary) ([]beneficiarySplit, error) {
+	result := make([]beneficiarySplit, len(bens))
+	var fixed money.Amount
+	var rest []int
+	for i, b := range bens {
+		if b.Amount == nil {
+			rest = append(rest, i)
+			continue
+		}
+		amount := b.Amount.Round(currency)
+		if amount < 0 {
+			return nil, fmt.Errorf("%w: exact amount must not be negative", utils.ErrInvalidInput)
+		}
+		input, _ := amount.NumericValue()
+		result[i] = beneficiarySplit{share: amount, input: input}
+		fixed += amount
+	}
+	remaining := total - fixed
+	if remaining < 0 {
+		return nil, fmt.Errorf("%w: exact amounts (%s) exceed total %s", utils.ErrInvalidInput, fixed, total)
+	}
+	if len(rest) == 0 {
+		if remaining != 0 {
+			return nil, fmt.Errorf("%w: exact amounts sum to %s but total is %s", utils.ErrInvalidInput, fixed, total)
+		}
+		return result, nil
+	}
+	shares, err := money.AllocateEqual(remaining, len(rest), currency)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	for j, i := range rest {
+		result[i] = beneficiarySplit{share: shares[j]}
+	}
+	return result, nil
+}
+
+// Adjustment: chia deu (total - tong dieu chinh) roi cong/tru dieu chinh cua tung nguoi
+func splitAdjustment(total money.Amount, currency string, bens []models.TransactionBeneficiary) ([]beneficiarySplit, error) {
+	adjustments := make([]money.Amount, len(bens))
+	for i, b := range bens {
+		if b.Adjustment != nil {
+			adjustments[i] = b.Adjustment.Round(currency)
+		}
+	}
+	remaining := total - money.Sum(adjustments)
+	if remaining < 0 {
+		return nil, fmt.Errorf("%w: adjustments exceed total %s", utils.ErrInvalidInput, total)
+	}
+	equal, err := money.AllocateEqual(remaining, len(bens), currency)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	result := make([]beneficiarySplit, len(bens))
+	for i := range bens {
+		share := equal[i] + adjustments[i]
+		if share < 0 {
+			return nil, fmt.Errorf("%w: adjustment makes a share negative", utils.ErrInvalidInput)
+		}
+		input, _ := adjustments[i].NumericValue()
+		result[i] = beneficiarySplit{share: share, input: input}
+	}
+	return result, nil
+}
+
+// Dung lai input da luu de tra ve dung nhung gi nguoi dung da nhap
+func applySplitInput(b *models.TransactionBeneficiary, mode string, input pgtype.Numeric, ratio pgtype.Numeric) {
+	b.Weight = utils.NumericToFloat(ratio)
+	if !input.Valid {
+		return
+	}
+	switch mode {
+	case splitModeShares:
+		b.Weight = utils.NumericToFloat(input)
+	case splitModePercentage:
+		pct := utils.NumericToFloat(input)
+		b.Percentage = &pct
+	case splitModeExact:
+		var amount money.Amount
+		if err := amount.ScanNumeric(input); err == nil {
+			b.Amount = &amount
+		}
+	case splitModeAdjustment:
+		var amount money.Amount
+		if err := amount.ScanNumeric(input); err == nil {
+			b.Adjustment = &amount
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	database "BACKEND/internal/db/sqlc"
+	models "BACKEND/internal/dto"
+	utils "BACKEND/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultInviteTTL = 7 * 24 * time.Hour
+	maxInviteTTL     = 90 * 24 * time.Hour
+	inviteTokenSize  = 24
+	inviteQRSize     = 512
+	claimInviteUses  = 1 // Link nhan khach chi dung duoc 1 lan
+)
+
+// Trang thai cua link moi (tinh tu cac cot, khong luu trong DB)
+const (
+	inviteStatusActive    = "active"
+	inviteStatusExpired   = "expired"
+	inviteStatusExhausted = "exhausted"
+	inviteStatusRevoked   = "revoked"
+)
+
+type InviteService struct {
+	store   database.Store
+	baseURL string
+}
+
+// Khoi tao InviteService, baseURL la trang join cua frontend
+func NewInviteService(store database.Store, baseURL string) *InviteService {
+	return &InviteService{store: store, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Tao link moi (admin tro len). Co participantId thi la link nhan khach, chi dung 1 lan
+func (s *InviteService) CreateInvite(ctx context.Context, userID int64, eventUUIDStr string, req models.CreateInviteRequest) (models.InviteDTO, error) {
+	event, err := s.getEventAsAdmin(ctx, userID, eventUUIDStr)
+	if err != nil {
+		return models.InviteDTO{}, err
+	}
+	if event.IsClosed {
+		return models.InviteDTO{}, utils.ErrEventClosed
+	}
+
+	ttl := defaultInviteTTL
+	if req.ExpiresInHours != nil {
+		ttl = time.Duration(*req.ExpiresInHours) * time.Hour
+		if ttl <= 0 || ttl > maxInviteTTL {
+			return models.InviteDTO{}, fmt.Errorf("%w: expiresInHours must be between 1 and %d", utils.ErrInvalidInput, int(maxInviteTTL.Hours()))
+		}
+	}
+	if req.MaxUses != nil && *req.MaxUses <= 0 {
+		return models.InviteDTO{}, fmt.Errorf("%w: maxUses must be positive", utils.ErrInvalidInput)
+	}
+	var guest *database.Participant
+	if req.ParticipantID != "" {
+		g, err := s.getGuest(ctx, event.EventID, req.ParticipantID)
+		if err != nil {
+			return models.InviteDTO{}, err
+		}
+		guest = &g
+		maxUses := int32(claimInviteUses)
+		req.MaxUses = &maxUses
+	}
+	token, err := utils.GenerateToken(inviteTokenSize)
+	if err != nil {
+		return models.InviteDTO{}, err
+	}
+
+	arg := database.CreateEventInviteParams{
+		EventID:   event.EventID,
+		Token:     token,
+		CreatedBy: &userID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+		MaxUses:   req.MaxUses,
+	}
+	if guest != nil {
+		arg.ParticipantID = &guest.ParticipantID
+	}
+	invite, err := s.store.CreateEventInvite(ctx, arg)
+	if err != nil {
+		return models.InviteDTO{}, utils.ErrInternalDB
+	}
+	dto := s.toInviteDTO(invite, eventUUIDStr)
+	if guest != nil {
+		dto.Guest = &models.GuestDTO{ID: guest.ParticipantUuid.String(), Name: guest.Name}
+	}
+	return dto, nil
+}
+
+// Liet ke cac link moi cua event (admin tro len)
+func (s *InviteService) ListInvites(ctx context.Context, userID int64, eventUUIDStr string) ([]models.InviteDTO, error) {
+	event, err := s.getEventAsAdmin(ctx, userID, eventUUIDStr)
+	if err != nil {
+		return nil, err
+	}
+	invites, err := s.store.ListEventInvites(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	participants, err := s.store.ListParticipantsByEventID(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	names := make(map[int64]models.GuestDTO, len(participants))
+	for _, p := range participants {
+		names[p.ParticipantID] = models.GuestDTO{ID: p.ParticipantUuid.String(), Name: p.Name}
+	}
+	result := make([]models.InviteDTO, 0, len(invites))
+	for _, invite := range invites {
+		dto := s.toInviteDTO(invite, eventUUIDStr)
+		if invite.ParticipantID != nil {
+			if guest, ok := names[*invite.ParticipantID]; ok {
+				dto.Guest = &guest
+			}
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
+
+// Thu hoi link moi, nguoi da join van giu nguyen
+func (s *InviteService) RevokeInvite(ctx context.Context, userID int64, inviteUUIDStr string) error {
+	invite, _, err := s.getInviteAsAdmin(ctx, userID, inviteUUIDStr)
+	if err != nil {
+		return err
+	}
+	affected, err := s.store.RevokeEventInvite(ctx, invite.InviteID)
+	if err != nil {
+		return utils.ErrInternalDB
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: invite is already revoked", utils.ErrInvalidInput)
+	}
+	return nil
+}
+
+// Anh QR (PNG) chua link moi
+func (s *InviteService) GetInviteQR(ctx context.Context, userID int64, inviteUUIDStr string) ([]byte, error) {
+	invite, _, err := s.getInviteAsAdmin(ctx, userID, inviteUUIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if inviteStatus(invite) != inviteStatusActive {
+		return nil, utils.ErrInviteInvalid
+	}
+	png, err := qrcode.Encode(s.inviteLink(invite.Token), qrcode.Medium, inviteQRSize)
+	if err != nil {
+		return nil, err
+	}
+	return png, nil
+}
+
+// Xem truoc event tu token truoc khi join
+func (s *InviteService) PreviewInvite(ctx context.Context, userID int64, token string) (models.InvitePreviewResponse, error) {
+	invite, err := s.getActiveInvite(ctx, token)
+	if err != nil {
+		return models.InvitePreviewResponse{}, err
+	}
+	event, err := s.store.GetEventByID(ctx, invite.EventID)
+	if err != nil {
+		return models.InvitePreviewResponse{}, utils.ErrInviteInvalid
+	}
+	resp := models.InvitePreviewResponse{
+		EventID:           event.EventUuid.String(),
+		EventName:         event.Name,
+		TotalParticipants: int(event.TotalParticipants),
+	}
+	if event.CreatorID != nil {
+		if creator, err := s.store.GetUserByID(ctx, *event.CreatorID); err == nil {
+			resp.CreatedBy = creator.Name
+		}
+	}
+	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+		EventID: event.EventID,
+		UserID:  &userID,
+	})
+	resp.AlreadyJoined = err == nil
+
+	if invite.ParticipantID != nil {
+		guest, err := s.store.GetParticipantByID(ctx, *invite.ParticipantID)
+		if err != nil || guest.UserID != nil || guest.RemovedAt.Valid {
+			return models.InvitePreviewResponse{}, utils.ErrInviteInvalid
+		}
+		resp.Guest = &models.GuestDTO{ID: guest.ParticipantUuid.String(), Name: guest.Name}
+		return resp, nil
+	}
+	participants, err := s.store.ListParticipantsByEventID(ctx, event.EventID)
+	if err != nil {
+		return models.InvitePreviewResponse{}, utils.ErrInternalDB
+	}
+	for _, p := range participants {
+		if p.UserID == nil && !p.RemovedAt.Valid {
+			resp.Guests = append(resp.Guests, models.GuestDTO{ID: p.ParticipantUuid.String(), Name: p.Name})
+		}
+	}
+	return resp, nil
+}
+
+// Tham gia event bang token; moi lan join thanh cong tru 1 luot dung cua link.
+// Link nhan khach hoac req.ParticipantID: user thay the khach va giu toan bo lich su cua khach
+func (s *InviteService) JoinByInvite(ctx context.Context, userID int64, token string, req models.JoinByInviteRequest) (string, error) {
+	invite, err := s.getActiveInvite(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	event, err := s.store.GetEventByID(ctx, invite.EventID)
+	if err != nil {
+		return "", utils.ErrInviteInvalid
+	}
+	if event.IsClosed {
+		return "", utils.ErrEventClosed
+	}
+	// Kiem tra user da la participant chua
+	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+		EventID: event.EventID,
+		UserID:  &userID,
+	})
+	if err == nil {
+		return "", utils.ErrAlreadyExists
+	}
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", utils.ErrNotFound
+	}
+
+	// Xac dinh khach duoc nhan (neu co)
+	var guest *database.Participant
+	switch {
+	case invite.ParticipantID != nil:
+		g, err := s.store.GetParticipantByID(ctx, *invite.ParticipantID)
+		if err != nil || g.UserID != nil || g.RemovedAt.Valid {
+			return "", utils.ErrInviteInvalid
+		}
+		if req.ParticipantID != "" && req.ParticipantID != g.ParticipantUuid.String() {
+			return "", fmt.Errorf("%w: this link is for another guest", utils.ErrInvalidInput)
+		}
+		guest = &g
+	case req.ParticipantID != "":
+		g, err := s.getGuest(ctx, event.EventID, req.ParticipantID)
+		if err != nil {
+			return "", err
+		}
+		guest = &g
+	}
+
+	params := joinParticipantParams(event.EventID, user, req)
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		if _, err := q.UseEventInvite(ctx, invite.InviteID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.ErrInviteInvalid
+			}
+			return err
+		}
+		var joined database.Participant
+		data := map[string]any{"name": params.Name}
+		if guest == nil {
+			// User tung bi xoa khoi event: khoi phuc dong cu de giu lich su
+			var err error
+			joined, err = q.RestoreParticipant(ctx, database.RestoreParticipantParams{
+				EventID: event.EventID,
+				UserID:  &userID,
+				Role:    roleMember,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				joined, err = q.AddParticipant(ctx, params)
+			} else if err == nil {
+				data["restored"] = true
+			}
+			if err != nil {
+				return err
+			}
+		} else {
+			var err error
+			joined, err = q.ClaimGuestParticipant(ctx, database.ClaimGuestParticipantParams{
+				ParticipantID: guest.ParticipantID,
+				UserID:        params.UserID,
+				BankName:      params.BankName,
+				BankAccount:   params.BankAccount,
+				BankOwner:     params.BankOwner,
+			})
+			// Khach vua duoc nguoi khac nhan giua chung
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: guest has already been claimed", utils.ErrAlreadyExists)
+			}
+			if err != nil {
+				return err
+			}
+			data["claimedGuest"] = guest.Name
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityParticipantJoined, entityParticipant, joined.ParticipantUuid, data)
+	})
+	if err != nil {
+		// Link vua het luot/het han giua chung
+		if errors.Is(err, utils.ErrInviteInvalid) || errors.Is(err, utils.ErrAlreadyExists) {
+			return "", err
+		}
+		return "", utils.ErrInternalDB
+	}
+	return event.EventUuid.String(), nil
+}
+
+// Helper: thong tin participant khi user join, bank info bo trong thi lay tu profile
+func joinParticipantParams(eventID int64, user database.User, req models.JoinByInviteRequest) database.AddParticipantParams {
+	getVal := func(reqVal string, dbVal *string) *string {
+		if reqVal != "" {
+			return &reqVal
+		}
+		return dbVal
+	}
+	bankName, bankAcc, bankOwner := user.BankName, user.BankAccount, user.BankOwner
+	if req.BankInfo != nil {
+		bankName = getVal(req.BankInfo.BankName, user.BankName)
+		bankAcc = getVal(req.BankInfo.AccountNumber, user.BankAccount)
+		bankOwner = getVal(req.BankInfo.AccountName, user.BankOwner)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = user.Name
+	}
+	return database.AddParticipantParams{
+		EventID:     eventID,
+		Name:        name,
+		Role:        roleMember,
+		UserID:      &user.UserID,
+		BankName:    bankName,
+		BankAccount: bankAcc,
+		BankOwner:   bankOwner,
+	}
+}
+
+// Helper: lay invite con hieu luc theo token, token sai cung tra ErrInviteInvalid de khong lo thong tin
+func (s *InviteService) getActiveInvite(ctx context.Context, token string) (database.EventInvite, error) {
+	if token == "" {
+		return database.EventInvite{}, utils.ErrInviteInvalid
+	}
+	invite, err := s.store.GetEventInviteByToken(ctx, token)
+	if err != nil {
+		return database.EventInvite{}, utils.ErrInviteInvalid
+	}
+	if inviteStatus(invite) != inviteStatusActive {
+		return database.EventInvite{}, utils.ErrInviteInvalid
+	}
+	return invite, nil
+}
+
+// Helper: participant khach (chua co tai khoan) thuoc event
+func (s *InviteService) getGuest(ctx context.Context, eventID int64, participantUUIDStr string) (database.Participant, error) {
+	partUUID, err := utils.StringToUUID(participantUUIDStr)
+	if err != nil {
+		return database.Participant{}, utils.ErrInvalidInput
+	}
+	guest, err := s.store.GetParticipantByUUID(ctx, partUUID)
+	if err != nil || guest.EventID != eventID || guest.RemovedAt.Valid {
+		return database.Participant{}, utils.ErrNotFound
+	}
+	if guest.UserID != nil {
+		return database.Participant{}, fmt.Errorf("%w: participant is not a guest", utils.ErrInvalidInput)
+	}
+	return guest, nil
+}
+
+func (s *InviteService) getEventAsAdmin(ctx context.Context, userID int64, eventUUIDStr string) (database.Event, error) {
+	eventUUID, err := utils.StringToUUID(eventUUIDStr)
+	if err != nil {
+		return database.Event{}, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUID)
+	if err != nil {
+		return database.Event{}, utils.ErrNotFound
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleAdmin); err != nil {
+		return database.Event{}, err
+	}
+	return event, nil
+}
+
+func (s *InviteService) getInviteAsAdmin(ctx context.Context, userID int64, inviteUUIDStr string) (database.EventInvite, database.Event, error) {
+	inviteUUID, err := utils.StringToUUID(inviteUUIDStr)
+	if err != nil {
+		return database.EventInvite{}, database.Event{}, utils.ErrInvalidInput
+	}
+	invite, err := s.store.GetEventInviteByUUID(ctx, inviteUUID)
+	if err != nil {
+		return database.EventInvite{}, database.Event{}, utils.ErrNotFound
+	}
+	event, err := s.store.GetEventByID(ctx, invite.EventID)
+	if err != nil {
+		return database.EventInvite{}, database.Event{}, utils.ErrInternalDB
+	}
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleAdmin); err != nil {
+		return database.EventInvite{}, database.Event{}, err
+	}
+	return invite, event, nil
+}
+
+func (s *InviteService) inviteLink(token string) string {
+	if s.baseURL == "" {
+		return token
+	}
+	return s.baseURL + "/" + token
+}
+
+func inviteStatus(invite database.EventInvite) string {
+	switch {
+	case invite.RevokedAt.Valid:
+		return inviteStatusRevoked
+	case invite.ExpiresAt.Valid && !invite.ExpiresAt.Time.After(time.Now()):
+		return inviteStatusExpired
+	case invite.MaxUses != nil && invite.UseCount >= *invite.MaxUses:
+		return inviteStatusExhausted
+	}
+	return inviteStatusActive
+}
+
+func (s *InviteService) toInviteDTO(invite database.EventInvite, eventUUIDStr string) models.InviteDTO {
+	dto := models.InviteDTO{
+		ID:        invite.InviteUuid.String(),
+		EventID:   eventUUIDStr,
+		Token:     invite.Token,
+		Link:      s.inviteLink(invite.Token),
+		Status:    inviteStatus(invite),
+		MaxUses:   invite.MaxUses,
+		UseCount:  invite.UseCount,
+		CreatedAt: invite.CreatedAt,
+	}
+	if invite.ExpiresAt.Valid {
+		dto.ExpiresAt = &invite.ExpiresAt.Time
+	}
+	return dto
+}
@@ -3,12 +3,22 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	database "BACKEND/internal/db/sqlc"
 	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
 	utils "BACKEND/internal/utils"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// Ly do ghi tren settlement khi chuyen so du cua participant bi xoa
+const balanceReassignReason = "balance reassigned on removal"
+
+// Ly do tu choi settlement dang cho cua participant bi xoa
+const removedParticipantRejectReason = "participant removed from the event"
+
 type ParticipantService struct {
 	store database.Store
 }
@@ -18,8 +28,8 @@ func NewParticipantService(store database.Store) *ParticipantService {
 	return &ParticipantService{store: store}
 } 
 
-// Liet ke participants va tra DTO
-func (s *ParticipantService) ListParticipants(ctx context.Context, userID int64, eventUUIDStr string) (models.ParticipantListResponse, error) {
+// Liet ke participants va tra DTO, includeRemoved = true thi kem ca nguoi da bi xoa
+func (s *ParticipantService) ListParticipants(ctx context.Context, userID int64, eventUUIDStr string, includeRemoved bool) (models.ParticipantListResponse, error) {
 	eventUUID, err := utils.StringToUUID(eventUUIDStr)
 	if err != nil {
 		return models.ParticipantListResponse{}, utils.ErrInvalidInput
@@ -42,6 +52,9 @@ func (s *ParticipantService) ListParticipants(ctx context.Context, userID int64,
 	}
 	dtos := make([]models.ParticipantDTO, 0)
 	for _, row := range rows {
+		if row.RemovedAt.Valid && !includeRemoved {
+			continue
+		}
 		isGuest := true
 		userUUIDStr := ""
 		email := ""
@@ -65,6 +78,10 @@ func (s *ParticipantService) ListParticipants(ctx context.Context, userID int64,
 			UserID:  userUUIDStr,
 			Email:   email,
 			IsGuest: isGuest,
+			Role:    row.Role,
+		}
+		if row.RemovedAt.Valid {
+			dto.RemovedAt = &row.RemovedAt.Time
 		}
 		dtos = append(dtos, dto)
 	}
@@ -83,12 +100,11 @@ func (s *ParticipantService) AddVirtualParticipant(ctx context.Context, userID i
 	if err != nil {
 		return models.ParticipantDTO{}, utils.ErrNotFound
 	}
-	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
-		EventID: event.EventID,
-		UserID:  &userID,
-	})
-	if err != nil {
-		return models.ParticipantDTO{}, utils.ErrPermissionDenied
+	if _, err := requireRole(ctx, s.store, event.EventID, userID, roleMember); err != nil {
+		return models.ParticipantDTO{}, err
+	}
+	if event.IsClosed {
+		return models.ParticipantDTO{}, utils.ErrEventClosed
 	}
 	var bName, bAcc, bOwner *string
 	if req.BankInfo != nil {
@@ -102,13 +118,23 @@ func (s *ParticipantService) AddVirtualParticipant(ctx context.Context, userID i
 			bOwner = &req.BankInfo.AccountName 
 		}
 	}
-	newPart, err := s.store.AddParticipant(ctx, database.AddParticipantParams{
-		EventID:     event.EventID,
-		UserID:      nil,
-		Name:        req.Name,
-		BankName:    bName,
-		BankAccount: bAcc,
-		BankOwner:   bOwner,
+	var newPart database.Participant
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		newPart, err = q.AddParticipant(ctx, database.AddParticipantParams{
+			EventID:     event.EventID,
+			UserID:      nil,
+			Name:        req.Name,
+			Role:        roleMember,
+			BankName:    bName,
+			BankAccount: bAcc,
+			BankOwner:   bOwner,
+		})
+		if err != nil {
+			return err
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activityParticipantAdded, entityParticipant, newPart.ParticipantUuid, map[string]any{
+			"name": newPart.Name,
+		})
 	})
 	if err != nil {
 		return models.ParticipantDTO{}, utils.ErrInternalDB
@@ -119,36 +145,43 @@ func (s *ParticipantService) AddVirtualParticipant(ctx context.Context, userID i
 		Name:     newPart.Name,
 		JoinedAt: newPart.JoinedAt.Time,
 		IsGuest:  true,
+		Role:     newPart.Role,
 		BankInfo: req.BankInfo,
 	}, nil
 }
 
-// Cap nhat participant (check quyen owner/creator)
+// Cap nhat participant: user tu sua minh, admin tro len sua khach
 func (s *ParticipantService) UpdateParticipant(ctx context.Context, userID int64, participantUUIDStr string, req models.UpsertParticipantRequest) (models.ParticipantDTO, error) {
 	partUUID, err := utils.StringToUUID(participantUUIDStr)
 	if err != nil {
 		return models.ParticipantDTO{}, utils.ErrInvalidInput
 	}
 	part, err := s.store.GetParticipantByUUID(ctx, partUUID)
-	if err != nil {
+	if err != nil || part.RemovedAt.Valid {
 		return models.ParticipantDTO{}, utils.ErrNotFound
 	}
 
 	// Case A: User thật sửa mình
-	// Case B: Creator sửa Guest
+	// Case B: Admin/owner sửa Guest
 	canEdit := false
 	if part.UserID != nil && *part.UserID == userID {
 		canEdit = true // Case A
 	} else if part.UserID == nil {
 		// Case B
-		event, _ := s.store.GetEventByID(ctx, part.EventID) 
-		if event.CreatorID != nil && *event.CreatorID == userID {
+		if _, err := requireRole(ctx, s.store, part.EventID, userID, roleAdmin); err == nil {
 			canEdit = true
 		}
 	}
 	if !canEdit {
 		return models.ParticipantDTO{}, utils.ErrPermissionDenied
 	}
+	event, err := s.store.GetEventByID(ctx, part.EventID)
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInternalDB
+	}
+	if event.IsClosed {
+		return models.ParticipantDTO{}, utils.ErrEventClosed
+	}
 
 	var bName, bAcc, bOwner *string
 	if req.BankInfo != nil {
@@ -172,6 +205,7 @@ func (s *ParticipantService) UpdateParticipant(ctx context.Context, userID int64
 		Name:     updated.Name,
 		JoinedAt: updated.JoinedAt.Time,
 		IsGuest:  updated.UserID == nil,
+		Role:     updated.Role,
 		BankInfo: &models.BankInfoDTO{
 			BankName:      utils.GetStringFromPointer(updated.BankName),
 			AccountNumber: utils.GetStringFromPointer(updated.BankAccount),
@@ -180,43 +214,326 @@ func (s *ParticipantService) UpdateParticipant(ctx context.Context, userID int64
 	}, nil
 }
 
-// Kick participant (chi creator, khong kick chinh minh neu co user)
-func (s *ParticipantService) KickParticipant(ctx context.Context, requesterID int64, participantUUIDStr string) error {
+// Kick participant (admin tro len, khong kick chinh minh; chi owner kick duoc admin, khong ai kick duoc owner).
+// Xoa mem: lich su giu nguyen. So du khac 0 thi phai chuyen cho reassignTo, neu khong se bi chan
+func (s *ParticipantService) KickParticipant(ctx context.Context, requesterID int64, participantUUIDStr string, reassignToUUIDStr string) error {
 	partUUID, err := utils.StringToUUID(participantUUIDStr)
 	if err != nil {
 		return utils.ErrInvalidInput
 	}
 	part, err := s.store.GetParticipantByUUID(ctx, partUUID)
-	if err != nil {
+	if err != nil || part.RemovedAt.Valid {
 		return utils.ErrNotFound
 	}
 	event, err := s.store.GetEventByID(ctx, part.EventID)
 	if err != nil {
 		return utils.ErrInternalDB
 	}
-	if event.CreatorID == nil || *event.CreatorID != requesterID {
+	me, err := requireRole(ctx, s.store, event.EventID, requesterID, roleAdmin)
+	if err != nil {
+		return err
+	}
+	if part.Role == roleOwner || (part.Role == roleAdmin && me.Role != roleOwner) {
 		return utils.ErrPermissionDenied
 	}
+	if event.IsClosed {
+		return utils.ErrEventClosed
+	}
 	if part.UserID != nil && *part.UserID == requesterID {
 		return errors.New("cannot kick yourself")
 	}
-	// Banlance > 0 ko duoc kick
-	if part.UserID != nil {
-		bal, err := s.store.GetParticipantBalance(ctx, database.GetParticipantBalanceParams{
-			EventID: event.EventID,
-			UserID:  part.UserID,
+	reassignTo, err := getReassignTarget(ctx, s.store, part, reassignToUUIDStr)
+	if err != nil {
+		return err
+	}
+	return removeParticipant(ctx, s.store, part, reassignTo, requesterID, activityParticipantKicked)
+}
+
+// Doi role cua thanh vien. Owner gan/go admin; admin chi doi qua lai member/viewer.
+// Khong doi duoc role owner (dung chuyen quyen so huu) va khach khong co role rieng.
+func (s *ParticipantService) ChangeRole(ctx context.Context, requesterID int64, participantUUIDStr string, req models.ChangeRoleRequest) (models.ParticipantDTO, error) {
+	if req.Role != roleAdmin && req.Role != roleMember && req.Role != roleViewer {
+		return models.ParticipantDTO{}, fmt.Errorf("%w: role must be admin, member or viewer", utils.ErrInvalidInput)
+	}
+	partUUID, err := utils.StringToUUID(participantUUIDStr)
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInvalidInput
+	}
+	part, err := s.store.GetParticipantByUUID(ctx, partUUID)
+	if err != nil || part.RemovedAt.Valid {
+		return models.ParticipantDTO{}, utils.ErrNotFound
+	}
+	me, err := requireRole(ctx, s.store, part.EventID, requesterID, roleAdmin)
+	if err != nil {
+		return models.ParticipantDTO{}, err
+	}
+	event, err := s.store.GetEventByID(ctx, part.EventID)
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInternalDB
+	}
+	if event.IsClosed {
+		return models.ParticipantDTO{}, utils.ErrEventClosed
+	}
+	if part.UserID == nil {
+		return models.ParticipantDTO{}, fmt.Errorf("%w: guests cannot be assigned a role", utils.ErrInvalidInput)
+	}
+	if part.ParticipantID == me.ParticipantID || part.Role == roleOwner {
+		return models.ParticipantDTO{}, utils.ErrPermissionDenied
+	}
+	// Chi owner moi gan hoac go quyen admin
+	if (part.Role == roleAdmin || req.Role == roleAdmin) && me.Role != roleOwner {
+		return models.ParticipantDTO{}, utils.ErrPermissionDenied
+	}
+
+	var updated database.Participant
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		updated, err = q.UpdateParticipantRole(ctx, database.UpdateParticipantRoleParams{
+			ParticipantID: part.ParticipantID,
+			Role:          req.Role,
 		})
-		if err == nil {
-			balFloat := utils.NumericToFloat(bal)
-			if balFloat > 0.01 || balFloat < -0.01 {
-				return utils.ErrBalanceNotZero
-			}
+		if err != nil {
+			return err
+		}
+		return recordActivity(ctx, q, part.EventID, requesterID, activityParticipantRole, entityParticipant, part.ParticipantUuid, map[string]any{
+			"name": part.Name,
+			"from": part.Role,
+			"to":   req.Role,
+		})
+	})
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInternalDB
+	}
+	return models.ParticipantDTO{
+		ID:       updated.ParticipantUuid.String(),
+		Name:     updated.Name,
+		JoinedAt: updated.JoinedAt.Time,
+		IsGuest:  false,
+		Role:     updated.Role,
+	}, nil
+}
+
+// Gop participant nguon vao dich trong 1 tx: chuyen payer/beneficiary/settlement/payment request/collector,
+// cong don dong trung tren cung expense, xoa giao dich giua 2 nguoi roi xoa nguon.
+// Nguon co tai khoan ma dich la khach thi dich nhan tai khoan va role cua nguon.
+func (s *ParticipantService) MergeParticipant(ctx context.Context, requesterID int64, participantUUIDStr string, req models.MergeParticipantRequest) (models.ParticipantDTO, error) {
+	sourceUUID, err := utils.StringToUUID(participantUUIDStr)
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInvalidInput
+	}
+	targetUUID, err := utils.StringToUUID(req.IntoParticipantID)
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInvalidInput
+	}
+	// Nguon da bi xoa khong duoc gop: tranh dua nguoi da bi kick tro lai va gop ca settlement chuyen so du
+	source, err := s.store.GetParticipantByUUID(ctx, sourceUUID)
+	if err != nil || source.RemovedAt.Valid {
+		return models.ParticipantDTO{}, utils.ErrNotFound
+	}
+	target, err := s.store.GetParticipantByUUID(ctx, targetUUID)
+	if err != nil || target.EventID != source.EventID || target.RemovedAt.Valid {
+		return models.ParticipantDTO{}, utils.ErrNotFound
+	}
+	if source.ParticipantID == target.ParticipantID {
+		return models.ParticipantDTO{}, fmt.Errorf("%w: cannot merge a participant into itself", utils.ErrInvalidInput)
+	}
+	if source.UserID != nil && target.UserID != nil {
+		return models.ParticipantDTO{}, fmt.Errorf("%w: cannot merge two registered users", utils.ErrInvalidInput)
+	}
+
+	event, err := s.store.GetEventByID(ctx, source.EventID)
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInternalDB
+	}
+	me, err := requireRole(ctx, s.store, event.EventID, requesterID, roleAdmin)
+	if err != nil {
+		return models.ParticipantDTO{}, err
+	}
+	// Giong kick: chi owner dung cham toi admin
+	for _, p := range []database.Participant{source, target} {
+		if p.ParticipantID != me.ParticipantID && (p.Role == roleOwner || (p.Role == roleAdmin && me.Role != roleOwner)) {
+			return models.ParticipantDTO{}, utils.ErrPermissionDenied
+		}
+	}
+	if event.IsClosed {
+		return models.ParticipantDTO{}, utils.ErrEventClosed
+	}
+
+	var merged database.Participant
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		if err := q.MergeDuplicatePayers(ctx, database.MergeDuplicatePayersParams{
+			SourceID: source.ParticipantID, TargetID: target.ParticipantID,
+		}); err != nil {
+			return err
+		}
+		if err := q.MergeDuplicateBeneficiaries(ctx, database.MergeDuplicateBeneficiariesParams{
+			SourceID: source.ParticipantID, TargetID: target.ParticipantID,
+		}); err != nil {
+			return err
+		}
+		if err := q.MergeDuplicateItemBeneficiaries(ctx, database.MergeDuplicateItemBeneficiariesParams{
+			SourceID: source.ParticipantID, TargetID: target.ParticipantID,
+		}); err != nil {
+			return err
+		}
+		if err := q.DeleteTransactionsBetween(ctx, database.DeleteTransactionsBetweenParams{
+			SourceID: source.ParticipantID, TargetID: target.ParticipantID,
+		}); err != nil {
+			return err
+		}
+		if err := q.ReassignParticipantReferences(ctx, database.ReassignParticipantReferencesParams{
+			SourceID: source.ParticipantID, TargetID: target.ParticipantID,
+		}); err != nil {
+			return err
+		}
+		if err := q.RemoveParticipantByID(ctx, source.ParticipantID); err != nil {
+			return err
+		}
+		if err := recordActivity(ctx, q, event.EventID, requesterID, activityParticipantMerged, entityParticipant, target.ParticipantUuid, map[string]any{
+			"sourceId":   source.ParticipantUuid.String(),
+			"sourceName": source.Name,
+			"name":       target.Name,
+		}); err != nil {
+			return err
+		}
+
+		if source.UserID == nil {
+			merged, err = q.GetParticipantByID(ctx, target.ParticipantID)
+			return err
+		}
+		// Chuyen tai khoan cua nguon sang dich (sau khi xoa nguon de khong trung UNIQUE(event_id, user_id))
+		if _, err := q.ClaimGuestParticipant(ctx, database.ClaimGuestParticipantParams{
+			ParticipantID: target.ParticipantID,
+			UserID:        source.UserID,
+			BankName:      source.BankName,
+			BankAccount:   source.BankAccount,
+			BankOwner:     source.BankOwner,
+		}); err != nil {
+			return err
+		}
+		merged, err = q.UpdateParticipantRole(ctx, database.UpdateParticipantRoleParams{
+			ParticipantID: target.ParticipantID,
+			Role:          source.Role,
+		})
+		return err
+	})
+	if err != nil {
+		return models.ParticipantDTO{}, utils.ErrInternalDB
+	}
+
+	return models.ParticipantDTO{
+		ID:       merged.ParticipantUuid.String(),
+		Name:     merged.Name,
+		JoinedAt: merged.JoinedAt.Time,
+		IsGuest:  merged.UserID == nil,
+		Role:     merged.Role,
+		BankInfo: &models.BankInfoDTO{
+			BankName:      utils.GetStringFromPointer(merged.BankName),
+			AccountNumber: utils.GetStringFromPointer(merged.BankAccount),
+			AccountName:   utils.GetStringFromPointer(merged.BankOwner),
+		},
+	}, nil
+}
+
+// Helper: participant nhan lai so du khi xoa (bo trong thi khong chuyen)
+func getReassignTarget(ctx context.Context, q database.Querier, part database.Participant, reassignToUUIDStr string) (*database.Participant, error) {
+	if reassignToUUIDStr == "" {
+		return nil, nil
+	}
+	targetUUID, err := utils.StringToUUID(reassignToUUIDStr)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	target, err := q.GetParticipantByUUID(ctx, targetUUID)
+	if err != nil || target.EventID != part.EventID || target.RemovedAt.Valid {
+		return nil, utils.ErrNotFound
+	}
+	if target.ParticipantID == part.ParticipantID {
+		return nil, fmt.Errorf("%w: cannot reassign balance to the removed participant", utils.ErrInvalidInput)
+	}
+	return &target, nil
+}
+
+// Helper: so du rong cua participant, da tinh settlement da xac nhan
+func participantBalance(ctx context.Context, q database.Querier, eventID int64, participantID int64) (money.Amount, error) {
+	rows, err := q.GetEventBalances(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range rows {
+		if row.ParticipantID == participantID {
+			return (row.TotalPaid - row.TotalShare) + (row.TotalSettledSent - row.TotalSettledReceived), nil
 		}
 	}
+	return 0, nil
+}
 
-	err = s.store.RemoveParticipantByID(ctx, part.ParticipantID)
+// Helper: xoa mem participant trong 1 tx. So du con lai duoc chuyen cho reassignTo bang 1 settlement da xac nhan,
+// khong co reassignTo thi so du phai bang 0. Huy payment request, settlement dang cho va collector cua participant.
+// action la activityParticipantKicked hoac activityParticipantLeft
+func removeParticipant(ctx context.Context, store database.Store, part database.Participant, reassignTo *database.Participant, recordedBy int64, action string) error {
+	err := store.ExecTx(ctx, func(q *database.Queries) error {
+		balance, err := participantBalance(ctx, q, part.EventID, part.ParticipantID)
+		if err != nil {
+			return err
+		}
+		if !balance.IsZero() {
+			if reassignTo == nil {
+				return utils.ErrBalanceNotZero
+			}
+			// Duong: nguoi nhan tiep quan khoan duoc no; am: nguoi nhan ganh khoan no
+			payer, receiver, amount := reassignTo.ParticipantID, part.ParticipantID, balance
+			if balance < 0 {
+				payer, receiver, amount = part.ParticipantID, reassignTo.ParticipantID, -balance
+			}
+			reason := balanceReassignReason
+			if _, err := q.CreateSettlement(ctx, database.CreateSettlementParams{
+				EventID:    part.EventID,
+				PayerID:    &payer,
+				ReceiverID: &receiver,
+				Amount:     amount,
+				CreatedBy:  &recordedBy,
+				Reason:     &reason,
+				Status:     settlementStatusConfirmed,
+				ResolvedBy: &recordedBy,
+			}); err != nil {
+				return err
+			}
+		}
+		if _, err := q.SoftRemoveParticipant(ctx, part.ParticipantID); err != nil {
+			return err
+		}
+		if err := q.CancelParticipantPaymentRequests(ctx, part.ParticipantID); err != nil {
+			return err
+		}
+		rejectReason := removedParticipantRejectReason
+		if err := q.RejectParticipantSettlements(ctx, database.RejectParticipantSettlementsParams{
+			ResolvedBy:    &recordedBy,
+			RejectReason:  &rejectReason,
+			ParticipantID: part.ParticipantID,
+		}); err != nil {
+			return err
+		}
+		collector, err := q.GetActiveCollectorByEventID(ctx, part.EventID)
+		if err == nil && collector.ParticipantUuid == part.ParticipantUuid {
+			if err := q.DeactivateCollector(ctx, collector.CollectorID); err != nil {
+				return err
+			}
+		}
+		data := map[string]any{"name": part.Name, "balance": balance}
+		if reassignTo != nil && !balance.IsZero() {
+			data["reassignedTo"] = reassignTo.ParticipantUuid.String()
+		}
+		return recordActivity(ctx, q, part.EventID, recordedBy, action, entityParticipant, part.ParticipantUuid, data)
+	})
 	if err != nil {
+		if errors.Is(err, utils.ErrBalanceNotZero) {
+			return err
+		}
+		// Participant vua bi xoa boi request khac
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.ErrNotFound
+		}
 		return utils.ErrInternalDB
 	}
 	return nil
-}
\ No newline at end of file
+}
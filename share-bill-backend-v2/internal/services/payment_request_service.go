@@ -7,11 +7,11 @@ import (
 
 	database "BACKEND/internal/db/sqlc"
 	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
 	utils "BACKEND/internal/utils"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -39,7 +39,7 @@ type paymentRequestRow struct {
 	eventID      int64
 	payerID      int64
 	receiverID   int64
-	amount       pgtype.Numeric
+	amount       money.Amount
 	status       string
 	createdAt    time.Time
 	updatedAt    time.Time
@@ -63,12 +63,15 @@ func (s *PaymentRequestService) CreatePaymentRequest(ctx context.Context, userID
 	if err != nil {
 		return models.PaymentRequestDTO{}, utils.ErrNotFound
 	}
+	if event.IsClosed {
+		return models.PaymentRequestDTO{}, utils.ErrEventClosed
+	}
 
 	requesterPart, err := s.queries.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
 		EventID: event.EventID,
 		UserID:  &userID,
 	})
-	if err != nil {
+	if err != nil || !hasRole(requesterPart.Role, roleMember) {
 		return models.PaymentRequestDTO{}, utils.ErrPermissionDenied
 	}
 
@@ -86,7 +89,7 @@ func (s *PaymentRequestService) CreatePaymentRequest(ctx context.Context, userID
 		return models.PaymentRequestDTO{}, utils.ErrNotFound
 	}
 	receiverPart, err := s.queries.GetParticipantByUUID(ctx, receiverUUID)
-	if err != nil {
+	if err != nil || receiverPart.RemovedAt.Valid {
 		return models.PaymentRequestDTO{}, utils.ErrNotFound
 	}
 
@@ -96,18 +99,28 @@ func (s *PaymentRequestService) CreatePaymentRequest(ctx context.Context, userID
 	if requesterPart.ParticipantID != payerPart.ParticipantID {
 		return models.PaymentRequestDTO{}, utils.ErrPermissionDenied
 	}
+	reqAmount := req.Amount.Round(event.Currency)
+	if reqAmount <= 0 {
+		return models.PaymentRequestDTO{}, utils.ErrInvalidInput
+	}
 
 	var requestUUID uuid.UUID
 	var status string
-	var amount pgtype.Numeric
+	var amount money.Amount
 	var createdAt time.Time
 	var updatedAt time.Time
 
-	err = s.pool.QueryRow(ctx, `
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return models.PaymentRequestDTO{}, utils.ErrInternalDB
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
 		INSERT INTO payment_requests (event_id, payer_id, receiver_id, amount, status)
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING payment_request_uuid, status, amount, created_at, updated_at
-	`, event.EventID, payerPart.ParticipantID, receiverPart.ParticipantID, utils.FloatToNumeric(req.Amount), paymentStatusPending).Scan(
+	`, event.EventID, payerPart.ParticipantID, receiverPart.ParticipantID, reqAmount, paymentStatusPending).Scan(
 		&requestUUID,
 		&status,
 		&amount,
@@ -117,6 +130,17 @@ func (s *PaymentRequestService) CreatePaymentRequest(ctx context.Context, userID
 	if err != nil {
 		return models.PaymentRequestDTO{}, utils.ErrInternalDB
 	}
+	err = recordActivity(ctx, database.New(tx), event.EventID, userID, activityPaymentRequestCreated, entityPaymentRequest, requestUUID, map[string]any{
+		"payerId":    req.PayerID,
+		"receiverId": req.ReceiverID,
+		"amount":     amount,
+	})
+	if err != nil {
+		return models.PaymentRequestDTO{}, utils.ErrInternalDB
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.PaymentRequestDTO{}, utils.ErrInternalDB
+	}
 
 	return models.PaymentRequestDTO{
 		ID:      requestUUID.String(),
@@ -129,7 +153,7 @@ func (s *PaymentRequestService) CreatePaymentRequest(ctx context.Context, userID
 			ID:   req.ReceiverID,
 			Name: receiverPart.Name,
 		},
-		Amount:    utils.NumericToFloat(amount),
+		Amount:    amount,
 		Status:    status,
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
@@ -204,7 +228,7 @@ func (s *PaymentRequestService) ListPaymentRequests(ctx context.Context, userID
 				ID:   row.receiverUUID.String(),
 				Name: row.receiverName,
 			},
-			Amount:    utils.NumericToFloat(row.amount),
+			Amount:    row.amount,
 			Status:    row.status,
 			CreatedAt: row.createdAt,
 			UpdatedAt: row.updatedAt,
@@ -216,6 +240,7 @@ func (s *PaymentRequestService) ListPaymentRequests(ctx context.Context, userID
 	return items, nil
 }
 
+// Xac nhan payment request, van cho phep khi event da dong de tat toan cac khoan con pending
 func (s *PaymentRequestService) ConfirmPaymentRequest(ctx context.Context, userID int64, eventUUIDStr string, requestUUIDStr string) (models.PaymentRequestDTO, error) {
 	eventUUID, err := utils.StringToUUID(eventUUIDStr)
 	if err != nil {
@@ -235,7 +260,7 @@ func (s *PaymentRequestService) ConfirmPaymentRequest(ctx context.Context, userI
 		EventID: event.EventID,
 		UserID:  &userID,
 	})
-	if err != nil {
+	if err != nil || !hasRole(requesterPart.Role, roleMember) {
 		return models.PaymentRequestDTO{}, utils.ErrPermissionDenied
 	}
 
@@ -276,6 +301,15 @@ func (s *PaymentRequestService) ConfirmPaymentRequest(ctx context.Context, userI
 		PayerID:    &row.payerID,
 		ReceiverID: &row.receiverID,
 		Amount:     row.amount,
+		CreatedBy:  &userID,
+		Status:     settlementStatusConfirmed, // Nguoi nhan da xac nhan payment request
+		ResolvedBy: &userID,
+	})
+	if err != nil {
+		return models.PaymentRequestDTO{}, utils.ErrInternalDB
+	}
+	err = recordActivity(ctx, q, row.eventID, userID, activityPaymentRequestConfirmed, entityPaymentRequest, row.uuid, map[string]any{
+		"amount": row.amount,
 	})
 	if err != nil {
 		return models.PaymentRequestDTO{}, utils.ErrInternalDB
@@ -304,12 +338,15 @@ func (s *PaymentRequestService) CancelPaymentRequest(ctx context.Context, userID
 	if err != nil {
 		return models.PaymentRequestDTO{}, utils.ErrNotFound
 	}
+	if event.IsClosed {
+		return models.PaymentRequestDTO{}, utils.ErrEventClosed
+	}
 
 	requesterPart, err := s.queries.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
 		EventID: event.EventID,
 		UserID:  &userID,
 	})
-	if err != nil {
+	if err != nil || !hasRole(requesterPart.Role, roleMember) {
 		return models.PaymentRequestDTO{}, utils.ErrPermissionDenied
 	}
 
@@ -324,8 +361,14 @@ func (s *PaymentRequestService) CancelPaymentRequest(ctx context.Context, userID
 		return models.PaymentRequestDTO{}, utils.ErrInvalidInput
 	}
 
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return models.PaymentRequestDTO{}, utils.ErrInternalDB
+	}
+	defer tx.Rollback(ctx)
+
 	var updatedAt time.Time
-	err = s.pool.QueryRow(ctx, `
+	err = tx.QueryRow(ctx, `
 		UPDATE payment_requests
 		SET status = $1, updated_at = NOW()
 		WHERE payment_request_id = $2 AND status = $3
@@ -337,6 +380,15 @@ func (s *PaymentRequestService) CancelPaymentRequest(ctx context.Context, userID
 		}
 		return models.PaymentRequestDTO{}, utils.ErrInternalDB
 	}
+	err = recordActivity(ctx, database.New(tx), row.eventID, userID, activityPaymentRequestCanceled, entityPaymentRequest, row.uuid, map[string]any{
+		"amount": row.amount,
+	})
+	if err != nil {
+		return models.PaymentRequestDTO{}, utils.ErrInternalDB
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.PaymentRequestDTO{}, utils.ErrInternalDB
+	}
 
 	row.status = paymentStatusCanceled
 	row.updatedAt = updatedAt
@@ -401,7 +453,7 @@ func (s *PaymentRequestService) toPaymentRequestDTO(row paymentRequestRow, event
 			ID:   row.receiverUUID.String(),
 			Name: row.receiverName,
 		},
-		Amount:    utils.NumericToFloat(row.amount),
+		Amount:    row.amount,
 		Status:    row.status,
 		CreatedAt: row.createdAt,
 		UpdatedAt: row.updatedAt,
@@ -3,11 +3,10 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
-	"net/url"
 
 	database "BACKEND/internal/db/sqlc"
 	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
 	utils "BACKEND/internal/utils"
 )
 
@@ -35,11 +34,14 @@ func (s *PaymentService) SetCollector(ctx context.Context, requesterID int64, ev
 	if err != nil { 
 		return utils.ErrNotFound 
 	}
-	if event.CreatorID == nil || *event.CreatorID != requesterID {
-		return utils.ErrPermissionDenied
+	if _, err := requireRole(ctx, s.store, event.EventID, requesterID, roleAdmin); err != nil {
+		return err
+	}
+	if event.IsClosed {
+		return utils.ErrEventClosed
 	}
 	part, err := s.store.GetParticipantByUUID(ctx, targetPartUUID)
-	if err != nil { 
+	if err != nil || part.RemovedAt.Valid {
 		return utils.ErrNotFound 
 	}
 	if part.EventID != event.EventID {
@@ -49,7 +51,10 @@ func (s *PaymentService) SetCollector(ctx context.Context, requesterID int64, ev
 	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
 		// Get active collector for event to deactivate it
 		activeCollector, err := q.GetActiveCollectorByEventID(ctx, event.EventID)
+		var previous *string
 		if err == nil {
+			prev := activeCollector.ParticipantUuid.String()
+			previous = &prev
 			// Only deactivate if one exists
 			if err := q.DeactivateCollector(ctx, activeCollector.CollectorID); err != nil {
 				return err
@@ -65,14 +70,21 @@ func (s *PaymentService) SetCollector(ctx context.Context, requesterID int64, ev
 			bankAcc = *part.BankAccount
 			bankOwner = *part.BankOwner
 		}
-		_, err = q.CreateCollector(ctx, database.CreateCollectorParams{
+		collector, err := q.CreateCollector(ctx, database.CreateCollectorParams{
 			EventID:       event.EventID,
 			ParticipantID: &part.ParticipantID, 
 			BankName:      bankName,
 			BankAccount:   bankAcc,
 			BankOwner:     bankOwner, 
 		})
-		return err
+		if err != nil {
+			return err
+		}
+		return recordActivity(ctx, q, event.EventID, requesterID, activityCollectorSet, entityCollector, collector.CollectorUuid, map[string]any{
+			"participantId":         part.ParticipantUuid.String(),
+			"name":                  part.Name,
+			"previousParticipantId": previous,
+		})
 	})
 	if err != nil { 
 		return utils.ErrInternalDB 
@@ -114,7 +126,7 @@ func (s *PaymentService) GetActiveCollector(ctx context.Context, userID int64, e
 }
 
 // Tao URL QR cho thanh toan (quicklink)
-func (s *PaymentService) GeneratePaymentQR(ctx context.Context, userID int64, eventUUIDStr string, receiverUUIDStr string, amount float64) (models.PaymentQRResponse, error) {
+func (s *PaymentService) GeneratePaymentQR(ctx context.Context, userID int64, eventUUIDStr string, receiverUUIDStr string, amount money.Amount) (models.PaymentQRResponse, error) {
 	eventUUID, err := utils.StringToUUID(eventUUIDStr)
 	if err != nil { 
 		return models.PaymentQRResponse{}, utils.ErrInvalidInput 
@@ -152,14 +164,9 @@ func (s *PaymentService) GeneratePaymentQR(ctx context.Context, userID int64, ev
 		bankOwner = coll.BankOwner
 	}
 	// Xài quicklink
-	// Format: https://img.vietqr.io/image/<BANK_ID>-<ACCOUNT_NO>-<TEMPLATE>.png?amount=<AMOUNT>&addInfo=<CONTENT>&accountName=<NAME>
-	template := "compact2" 
-	content := fmt.Sprintf("Thanh toan Event %s", event.Name)
-	encodedContent := url.QueryEscape(content)
-	encodedName := url.QueryEscape(bankOwner)
-	qrURL := fmt.Sprintf("https://img.vietqr.io/image/%s-%s-%s.png?amount=%.0f&addInfo=%s&accountName=%s",
-		bankName, bankAcc, template, amount, encodedContent, encodedName,
-	)
+	content := vietQRContent(event.Name)
+	amount = amount.Round(event.Currency)
+	qrURL := buildVietQRURL(bankName, bankAcc, bankOwner, amount, content)
 	return models.PaymentQRResponse{
 		QRCodeURL: qrURL,
 		BankInfo: models.BankInfoDTO{
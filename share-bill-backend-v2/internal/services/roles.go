@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+
+	database "BACKEND/internal/db/sqlc"
+	utils "BACKEND/internal/utils"
+)
+
+// Quyen cua participant trong event, xep theo thu tu giam dan
+const (
+	roleOwner  = "owner"  // Nguoi tao/so huu event, duy nhat
+	roleAdmin  = "admin"  // Quan ly collector, khach, invite va moi transaction
+	roleMember = "member" // Tao transaction/settlement, sua/xoa transaction cua minh
+	roleViewer = "viewer" // Chi xem
+)
+
+var roleRank = map[string]int{
+	roleViewer: 1,
+	roleMember: 2,
+	roleAdmin:  3,
+	roleOwner:  4,
+}
+
+// Helper: role co dat muc toi thieu min khong
+func hasRole(role string, min string) bool {
+	return roleRank[role] >= roleRank[min]
+}
+
+// Helper: lay participant cua user trong event va kiem tra role toi thieu.
+// Khong phai thanh vien hoac khong du quyen deu tra ErrPermissionDenied.
+func requireRole(ctx context.Context, q database.Querier, eventID int64, userID int64, min string) (database.Participant, error) {
+	me, err := q.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+		EventID: eventID,
+		UserID:  &userID,
+	})
+	if err != nil || !hasRole(me.Role, min) {
+		return database.Participant{}, utils.ErrPermissionDenied
+	}
+	return me, nil
+}
+
+// Trang thai yeu cau chuyen quyen owner
+const (
+	transferStatusAccepted  = "accepted"
+	transferStatusDeclined  = "declined"
+	transferStatusCancelled = "cancelled"
+)
+
+// Helper: chuyen owner tu from sang to trong 1 tx: owner cu xuong admin, dong bo events.creator_id
+func handOverOwnership(ctx context.Context, q *database.Queries, eventID int64, from database.Participant, to database.Participant) error {
+	if _, err := q.UpdateParticipantRole(ctx, database.UpdateParticipantRoleParams{
+		ParticipantID: from.ParticipantID,
+		Role:          roleAdmin,
+	}); err != nil {
+		return err
+	}
+	if _, err := q.UpdateParticipantRole(ctx, database.UpdateParticipantRoleParams{
+		ParticipantID: to.ParticipantID,
+		Role:          roleOwner,
+	}); err != nil {
+		return err
+	}
+	if err := q.SetEventCreator(ctx, database.SetEventCreatorParams{
+		EventID:   eventID,
+		CreatorID: to.UserID,
+	}); err != nil {
+		return err
+	}
+	return q.CancelPendingOwnershipTransfers(ctx, eventID)
+}
@@ -2,15 +2,38 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"math"
+	"sort"
 	"strings"
 	"time"
 
 	database "BACKEND/internal/db/sqlc"
 	models "BACKEND/internal/dto"
+	"BACKEND/internal/money"
+	"BACKEND/internal/settlement"
 	utils "BACKEND/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Cach lap settlement plan trong summary (query param ?strategy=)
+const (
+	SummaryStrategyGreedy    = "greedy"    // Ghep nguoi no nhieu nhat voi nguoi nhan nhieu nhat (mac dinh)
+	SummaryStrategyMinimal   = "minimal"   // It giao dich nhat
+	SummaryStrategyCollector = "collector" // Moi khoan deu di qua collector dang active
+)
+
+// Bang no truc tiep giua tung cap trong summary (query param ?pairwise=), bo trong thi khong tinh
+const (
+	PairwiseGross = "gross" // Giu ca 2 chieu cua moi cap
+	PairwiseNet   = "net"   // Bu tru 2 chieu, moi cap toi da 1 dong
+)
+
+// Trang thai settlement: chi "confirmed" moi duoc tinh vao balance
+const (
+	settlementStatusPending   = "pending"
+	settlementStatusConfirmed = "confirmed"
+	settlementStatusRejected  = "rejected"
 )
 
 type SettlementService struct {
@@ -23,39 +46,62 @@ func NewSettlementService(store database.Store) *SettlementService {
 } 
 
 // Tinh balances, tao suggestions va tra summary
-func (s *SettlementService) GetEventSummary(ctx context.Context, userID int64, eventUUIDStr string) (models.EventSummaryResponse, error) {
+func (s *SettlementService) GetEventSummary(ctx context.Context, userID int64, eventUUIDStr string, strategy string, pairwise string) (models.EventSummaryResponse, error) {
 	eventUUID, err := utils.StringToUUID(eventUUIDStr)
 	if err != nil {
 		return models.EventSummaryResponse{}, utils.ErrInvalidInput
 	}
+	strategy, err = checkSummaryOptions(strategy, pairwise)
+	if err != nil {
+		return models.EventSummaryResponse{}, err
+	}
 	event, err := s.store.GetEventByUUID(ctx, eventUUID)
 	if err != nil {
 		return models.EventSummaryResponse{}, utils.ErrNotFound
 	}
+	return s.buildEventSummary(ctx, s.store, userID, event, strategy, pairwise)
+}
+
+// Helper: kiem tra strategy/pairwise cua summary, tra ve strategy (mac dinh greedy)
+func checkSummaryOptions(strategy string, pairwise string) (string, error) {
+	if strategy == "" {
+		strategy = SummaryStrategyGreedy
+	}
+	if strategy != SummaryStrategyGreedy && strategy != SummaryStrategyMinimal && strategy != SummaryStrategyCollector {
+		return "", fmt.Errorf("%w: unknown settlement strategy %q", utils.ErrInvalidInput, strategy)
+	}
+	if pairwise != "" && pairwise != PairwiseGross && pairwise != PairwiseNet {
+		return "", fmt.Errorf("%w: pairwise must be gross or net", utils.ErrInvalidInput)
+	}
+	return strategy, nil
+}
+
+// Helper: tinh summary bang q. Dong event thi goi trong tx da khoa event de snapshot khop voi luc khoa
+func (s *SettlementService) buildEventSummary(ctx context.Context, q database.Querier, userID int64, event database.Event, strategy string, pairwise string) (models.EventSummaryResponse, error) {
+	eventUUIDStr := event.EventUuid.String()
 	// Check Permission
-	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+	_, err := q.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
 		EventID: event.EventID, UserID: &userID,
 	})
 	if err != nil {
 		return models.EventSummaryResponse{}, utils.ErrPermissionDenied
 	}
 
-	rows, err := s.store.GetEventBalances(ctx, event.EventID)
+	rows, err := q.GetEventBalances(ctx, event.EventID)
 	if err != nil {
 		return models.EventSummaryResponse{}, utils.ErrInternalDB
 	}
 	var participantsDTO []models.ParticipantBalDTO
 	
-	debtors := make(map[string]float64)   
-	creditors := make(map[string]float64) 
+	var balances []settlement.Balance
 	nameMap := make(map[string]string)
 
-	var totalExpenses float64 = 0
+	var totalExpenses money.Amount
 	for _, row := range rows {
-		paid := utils.NumericToFloat(row.TotalPaid)
-		share := utils.NumericToFloat(row.TotalShare)
-		sent := utils.NumericToFloat(row.TotalSettledSent)
-		received := utils.NumericToFloat(row.TotalSettledReceived)
+		paid := row.TotalPaid
+		share := row.TotalShare
+		sent := row.TotalSettledSent
+		received := row.TotalSettledReceived
 
 		totalExpenses += paid
 		finalBalance := (paid - share) + (sent - received)	
@@ -72,13 +118,12 @@ func (s *SettlementService) GetEventSummary(ctx context.Context, userID int64, e
 			balanceType = "credit"
 			action = "receive"
 			description = fmt.Sprintf("Receives %v", finalBalance)
-			creditors[uuidStr] = finalBalance
 		} else if finalBalance < 0 {
 			balanceType = "debit"
 			action = "pay"
 			description = fmt.Sprintf("Pays %v", -finalBalance)
-			debtors[uuidStr] = -finalBalance
 		}
+		balances = append(balances, settlement.Balance{ID: uuidStr, Amount: finalBalance})
 
 		dto := models.ParticipantBalDTO{
 			ID:           uuidStr,
@@ -95,58 +140,59 @@ func (s *SettlementService) GetEventSummary(ctx context.Context, userID int64, e
 		}
 		participantsDTO = append(participantsDTO, dto)
 	}
-	var suggestions []models.SettlementPlanDTO
-	for len(debtors) > 0 && len(creditors) > 0 {
-		var maxDebtor string
-		var maxDebtAmount float64
-		for id, amt := range debtors {
-			if amt > maxDebtAmount {
-				maxDebtor = id
-				maxDebtAmount = amt
-			}
+	var transfers []settlement.Transfer
+	var collector database.GetActiveCollectorByEventIDRow
+	switch strategy {
+	case SummaryStrategyMinimal:
+		transfers = settlement.MinTransfers(balances)
+	case SummaryStrategyCollector:
+		collector, err = q.GetActiveCollectorByEventID(ctx, event.EventID)
+		if err != nil {
+			return models.EventSummaryResponse{}, fmt.Errorf("%w: no active collector configured", utils.ErrInvalidInput)
 		}
-
-		var maxCreditor string
-		var maxCreditAmount float64
-		for id, amt := range creditors {
-			if amt > maxCreditAmount {
-				maxCreditor = id
-				maxCreditAmount = amt
-			}
+		transfers = settlement.Hub(balances, collector.ParticipantUuid.String())
+	default:
+		transfers = settlement.Greedy(balances)
+	}
+	suggestions := make([]models.SettlementPlanDTO, 0, len(transfers))
+	for _, t := range transfers {
+		suggestions = append(suggestions, models.SettlementPlanDTO{
+			From: models.SettlementParty{
+				ID:   t.From,
+				Name: nameMap[t.From],
+			},
+			To: models.SettlementParty{
+				ID:   t.To,
+				Name: nameMap[t.To],
+			},
+			Amount: t.Amount,
+		})
+	}
+	if strategy == SummaryStrategyCollector {
+		if err := s.attachCollectorQRCodes(ctx, q, event, collector, suggestions, participantsDTO); err != nil {
+			return models.EventSummaryResponse{}, err
 		}
+	}
 
-		amount := math.Min(maxDebtAmount, maxCreditAmount)
-		if amount > 0.01 {
-			suggestions = append(suggestions, models.SettlementPlanDTO{
-				From: models.SettlementParty{
-					ID:   maxDebtor,
-					Name: nameMap[maxDebtor],
-				},
-				To: models.SettlementParty{
-					ID:   maxCreditor,
-					Name: nameMap[maxCreditor],
-				},
-				Amount: amount,
-			})
+	var pairwiseDebts []models.SettlementPlanDTO
+	if pairwise != "" {
+		pairwiseDebts, err = s.buildPairwiseDebts(ctx, q, event, pairwise == PairwiseNet, nameMap)
+		if err != nil {
+			return models.EventSummaryResponse{}, err
 		}
-
-		debtors[maxDebtor] -= amount
-		creditors[maxCreditor] -= amount
-
-		if debtors[maxDebtor] < 0.01 { delete(debtors, maxDebtor) }
-		if creditors[maxCreditor] < 0.01 { delete(creditors, maxCreditor) }
 	}
 
-	var avgPerPerson float64 = 0
-	if len(rows) > 0 {
-		avgPerPerson = totalExpenses / float64(len(rows))
-	}
+	avgPerPerson := totalExpenses.Div(int64(len(rows)), event.Currency)
 
-	status := "active"
-	if strings.ToUpper(*event.Status) == "CLOSED" {
-		status = "closed"
+	status := eventStatusActive
+	if event.IsClosed {
+		status = eventStatusClosed
+	}
+	collectorDTO := s.getCollectorInfo(ctx, q, event.EventID)
+	byCurrency, err := s.currencyTotals(ctx, q, event.EventID)
+	if err != nil {
+		return models.EventSummaryResponse{}, err
 	}
-	collectorDTO := s.getCollectorInfo(ctx, event.EventID)
 
 	resp := models.EventSummaryResponse{
 		Event: models.SettlementEventDTO{
@@ -161,19 +207,237 @@ func (s *SettlementService) GetEventSummary(ctx context.Context, userID int64, e
 		Summary: models.SummaryInfoDTO{
 			TotalPaidByAll: totalExpenses,
 			Collector:      collectorDTO,
+			ByCurrency:     byCurrency,
 		},
 		Participants:   participantsDTO,
 		SettlementPlan: suggestions,
+		PairwiseDebts:  pairwiseDebts,
 		Meta: models.SummaryMeta{
 			GeneratedAt: time.Now(),
+			Strategy:    strategy,
+			Pairwise:    pairwise,
 		},
 	}
 
 	return resp, nil
 }
+// Liet ke tung expense va settlement (da confirmed) lam thay doi balance cua 1 participant, kem balance luy ke
+func (s *SettlementService) GetParticipantBalanceBreakdown(ctx context.Context, userID int64, participantUUIDStr string) (models.ParticipantBalanceBreakdown, error) {
+	participantUUID, err := utils.StringToUUID(participantUUIDStr)
+	if err != nil {
+		return models.ParticipantBalanceBreakdown{}, utils.ErrInvalidInput
+	}
+	participant, err := s.store.GetParticipantByUUID(ctx, participantUUID)
+	if err != nil {
+		return models.ParticipantBalanceBreakdown{}, utils.ErrNotFound
+	}
+	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+		EventID: participant.EventID, UserID: &userID,
+	})
+	if err != nil {
+		return models.ParticipantBalanceBreakdown{}, utils.ErrPermissionDenied
+	}
+	event, err := s.store.GetEventByID(ctx, participant.EventID)
+	if err != nil {
+		return models.ParticipantBalanceBreakdown{}, utils.ErrInternalDB
+	}
+
+	expenses, err := s.store.ListParticipantExpenseEntries(ctx, database.ListParticipantExpenseEntriesParams{
+		EventID: participant.EventID, ParticipantID: &participant.ParticipantID,
+	})
+	if err != nil {
+		return models.ParticipantBalanceBreakdown{}, utils.ErrInternalDB
+	}
+	settlements, err := s.store.ListSettlementsByEvent(ctx, participant.EventID)
+	if err != nil {
+		return models.ParticipantBalanceBreakdown{}, utils.ErrInternalDB
+	}
+
+	resp := models.ParticipantBalanceBreakdown{
+		Participant: models.SettlementParty{ID: participantUUIDStr, Name: participant.Name},
+		Currency:    event.Currency,
+	}
+	entries := make([]models.BalanceEntryDTO, 0, len(expenses))
+	for _, e := range expenses {
+		resp.TotalPaid += e.PaidAmount
+		resp.TotalBenefit += e.ShareAmount
+		entries = append(entries, models.BalanceEntryDTO{
+			Type:        "expense",
+			ID:          e.ExpenseUuid.String(),
+			Date:        e.SpentAt,
+			Description: e.Description,
+			Paid:        e.PaidAmount,
+			Share:       e.ShareAmount,
+			Amount:      e.PaidAmount - e.ShareAmount,
+		})
+	}
+	for _, st := range settlements {
+		if st.Status != settlementStatusConfirmed {
+			continue
+		}
+		entry := models.BalanceEntryDTO{
+			Type:        "settlement",
+			ID:          st.SettlementUuid.String(),
+			Date:        st.CreatedAt.Time,
+			Description: utils.GetStringFromPointer(st.Reason),
+		}
+		switch participantUUID {
+		case st.PayerUuid:
+			resp.SettledSent += st.Amount
+			entry.Direction = "sent"
+			entry.Counterparty = &models.SettlementParty{ID: st.ReceiverUuid.String(), Name: st.ReceiverName}
+			entry.Amount = st.Amount
+		case st.ReceiverUuid:
+			resp.SettledReceived += st.Amount
+			entry.Direction = "received"
+			entry.Counterparty = &models.SettlementParty{ID: st.PayerUuid.String(), Name: st.PayerName}
+			entry.Amount = -st.Amount
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	// Sap xep theo thoi gian, cung thoi diem thi expense truoc settlement
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].Type == "expense" && entries[j].Type != "expense"
+	})
+	var running money.Amount
+	for i := range entries {
+		running += entries[i].Amount
+		entries[i].RunningBalance = running
+	}
+	resp.Balance = running
+	resp.Entries = entries
+	return resp, nil
+}
+
+// Helper: tinh no truc tiep giua tung cap tu expense_payers x expense_beneficiaries, tru cac settlement da confirmed
+func (s *SettlementService) buildPairwiseDebts(ctx context.Context, q database.Querier, event database.Event, net bool, nameMap map[string]string) ([]models.SettlementPlanDTO, error) {
+	payers, err := q.ListEventExpensePayers(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	beneficiaries, err := q.ListEventExpenseBeneficiaries(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	settlements, err := q.ListSettlementsByEvent(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+
+	// Gom payer va share theo expense, giu thu tu expense_id tu query
+	var order []int64
+	expenses := make(map[int64]*settlement.Expense)
+	get := func(expenseID int64) *settlement.Expense {
+		e, ok := expenses[expenseID]
+		if !ok {
+			e = &settlement.Expense{}
+			expenses[expenseID] = e
+			order = append(order, expenseID)
+		}
+		return e
+	}
+	for _, p := range payers {
+		e := get(p.ExpenseID)
+		e.Payers = append(e.Payers, settlement.Balance{ID: p.ParticipantUuid.String(), Amount: p.PaidAmount})
+	}
+	for _, b := range beneficiaries {
+		if b.ExpenseID == nil {
+			continue
+		}
+		e := get(*b.ExpenseID)
+		e.Shares = append(e.Shares, settlement.Balance{ID: b.ParticipantUuid.String(), Amount: b.ShareAmount})
+	}
+	list := make([]settlement.Expense, 0, len(order))
+	for _, id := range order {
+		list = append(list, *expenses[id])
+	}
+
+	var settled []settlement.Transfer
+	for _, st := range settlements {
+		if st.Status != settlementStatusConfirmed {
+			continue
+		}
+		settled = append(settled, settlement.Transfer{From: st.PayerUuid.String(), To: st.ReceiverUuid.String(), Amount: st.Amount})
+	}
+
+	debts := settlement.PairwiseDebts(list, settled, net, event.Currency)
+	result := make([]models.SettlementPlanDTO, 0, len(debts))
+	for _, d := range debts {
+		result = append(result, models.SettlementPlanDTO{
+			From:   models.SettlementParty{ID: d.From, Name: nameMap[d.From]},
+			To:     models.SettlementParty{ID: d.To, Name: nameMap[d.To]},
+			Amount: d.Amount,
+		})
+	}
+	return result, nil
+}
+
+// Helper: gan link VietQR cho tung dong cua plan collector.
+// Nguoi no quet QR tai khoan collector, collector quet QR tai khoan nguoi duoc hoan tien.
+func (s *SettlementService) attachCollectorQRCodes(ctx context.Context, q database.Querier, event database.Event, collector database.GetActiveCollectorByEventIDRow, plan []models.SettlementPlanDTO, participants []models.ParticipantBalDTO) error {
+	parts, err := q.ListParticipantsByEventID(ctx, event.EventID)
+	if err != nil {
+		return utils.ErrInternalDB
+	}
+	partMap := make(map[string]database.ListParticipantsByEventIDRow)
+	for _, p := range parts {
+		partMap[p.ParticipantUuid.String()] = p
+	}
+
+	collectorID := collector.ParticipantUuid.String()
+	content := vietQRContent(event.Name)
+	qrByParticipant := make(map[string]string)
+	for i, row := range plan {
+		if row.To.ID == collectorID {
+			plan[i].QRCodeURL = buildVietQRURL(collector.BankName, collector.BankAccount, collector.BankOwner, row.Amount, content)
+			qrByParticipant[row.From.ID] = plan[i].QRCodeURL
+			continue
+		}
+		receiver, ok := partMap[row.To.ID]
+		if !ok || receiver.BankName == nil || receiver.BankAccount == nil {
+			continue // Nguoi nhan chua co thong tin ngan hang
+		}
+		owner := ""
+		if receiver.BankOwner != nil {
+			owner = *receiver.BankOwner
+		}
+		plan[i].QRCodeURL = buildVietQRURL(*receiver.BankName, *receiver.BankAccount, owner, row.Amount, content)
+		qrByParticipant[row.To.ID] = plan[i].QRCodeURL
+	}
+	for i := range participants {
+		participants[i].QRCodeURL = qrByParticipant[participants[i].ID]
+	}
+	return nil
+}
+
+// Helper: tong chi theo tung currency goc. Balances da tinh theo currency cua event
+// (so tien duoc quy doi luc luu transaction) nen chi dung de hien thi
+func (s *SettlementService) currencyTotals(ctx context.Context, q database.Querier, eventID int64) ([]models.CurrencyTotalDTO, error) {
+	rows, err := q.ListEventCurrencyTotals(ctx, eventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.CurrencyTotalDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, models.CurrencyTotalDTO{
+			Currency:       row.Currency,
+			Transactions:   row.Transactions,
+			OriginalAmount: row.OriginalTotal,
+			Amount:         row.ConvertedTotal,
+		})
+	}
+	return result, nil
+}
+
 // Lay thong tin collector hien tai (helper)
-func (s *SettlementService) getCollectorInfo(ctx context.Context, eventID int64) *models.CollectorDTO {
-	collector, err := s.store.GetActiveCollectorByEventID(ctx, eventID)
+func (s *SettlementService) getCollectorInfo(ctx context.Context, q database.Querier, eventID int64) *models.CollectorDTO {
+	collector, err := q.GetActiveCollectorByEventID(ctx, eventID)
 	if err != nil {
 		return nil
 	}
@@ -189,44 +453,355 @@ func (s *SettlementService) getCollectorInfo(ctx context.Context, eventID int64)
 	
 }
 
-// Ghi mot settlement trong DB
-func (s *SettlementService) CreateSettlement(ctx context.Context, userID int64, eventUUIDStr string, req models.CreateSettlementRequest) error {
+// Ghi mot settlement trong DB.
+// Settlement o trang thai pending cho den khi nguoi nhan xac nhan, tru khi chinh nguoi nhan ghi nhan.
+func (s *SettlementService) CreateSettlement(ctx context.Context, userID int64, eventUUIDStr string, req models.CreateSettlementRequest) (models.SettlementDTO, error) {
 	eventUUID, err := utils.StringToUUID(eventUUIDStr)
 	if err != nil {
-		return utils.ErrInvalidInput
+		return models.SettlementDTO{}, utils.ErrInvalidInput
 	}
 	if req.PayerUUID == req.ReceiverUUID {
-		return errors.New("payer and receiver cannot be the same")
+		return models.SettlementDTO{}, fmt.Errorf("%w: payer and receiver cannot be the same", utils.ErrInvalidInput)
 	}
 
 	event, err := s.store.GetEventByUUID(ctx, eventUUID)
 	if err != nil {
-		return utils.ErrNotFound
+		return models.SettlementDTO{}, utils.ErrNotFound
 	}
-	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
-		EventID: event.EventID, UserID: &userID,
-	})
+	me, err := requireRole(ctx, s.store, event.EventID, userID, roleMember)
 	if err != nil {
-		return utils.ErrPermissionDenied
+		return models.SettlementDTO{}, err
+	}
+	if event.IsClosed {
+		return models.SettlementDTO{}, utils.ErrEventClosed
 	}
 
 	parts, _ := s.store.ListParticipantsByEventID(ctx, event.EventID)
 	partMap := make(map[string]int64)
 	for _, p := range parts {
-		partMap[p.ParticipantUuid.String()] = p.ParticipantID
+		// Nguoi da bi xoa khong nhan settlement moi
+		if !p.RemovedAt.Valid {
+			partMap[p.ParticipantUuid.String()] = p.ParticipantID
+		}
 	}
 
 	payerID, ok1 := partMap[req.PayerUUID]
 	receiverID, ok2 := partMap[req.ReceiverUUID]
 	if !ok1 || !ok2 {
-		return utils.ErrNotFound
+		return models.SettlementDTO{}, utils.ErrNotFound
+	}
+	amount := req.Amount.Round(event.Currency)
+	if amount <= 0 {
+		return models.SettlementDTO{}, fmt.Errorf("%w: amount must be greater than 0", utils.ErrInvalidInput)
 	}
 
-	_, err = s.store.CreateSettlement(ctx, database.CreateSettlementParams{
+	params := database.CreateSettlementParams{
 		EventID:    event.EventID,
 		PayerID:    &payerID,
 		ReceiverID: &receiverID,
-		Amount:     utils.FloatToNumeric(req.Amount),
+		Amount:     amount,
+		CreatedBy:  &userID,
+		Status:     settlementStatusPending,
+	}
+	if receiverID == me.ParticipantID {
+		params.Status = settlementStatusConfirmed
+		params.ResolvedBy = &userID
+	}
+	var created database.Settlement
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		created, err = q.CreateSettlement(ctx, params)
+		if err != nil {
+			return utils.ErrInternalDB
+		}
+		return recordActivity(ctx, q, event.EventID, userID, activitySettlementCreated, entitySettlement, created.SettlementUuid, map[string]any{
+			"payerId":    req.PayerUUID,
+			"receiverId": req.ReceiverUUID,
+			"amount":     amount,
+			"status":     params.Status,
+		})
+	})
+	if err != nil {
+		return models.SettlementDTO{}, err
+	}
+	row, err := s.store.GetSettlementByUUID(ctx, created.SettlementUuid)
+	if err != nil {
+		return models.SettlementDTO{}, utils.ErrInternalDB
+	}
+	return toSettlementDTO(row), nil
+}
+
+// Liet ke settlements cua event (moi nhat truoc), gom ca cac but toan dao nguoc
+func (s *SettlementService) ListSettlements(ctx context.Context, userID int64, eventUUIDStr string) ([]models.SettlementDTO, error) {
+	eventUUID, err := utils.StringToUUID(eventUUIDStr)
+	if err != nil {
+		return nil, utils.ErrInvalidInput
+	}
+	event, err := s.store.GetEventByUUID(ctx, eventUUID)
+	if err != nil {
+		return nil, utils.ErrNotFound
+	}
+	_, err = s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+		EventID: event.EventID, UserID: &userID,
+	})
+	if err != nil {
+		return nil, utils.ErrPermissionDenied
+	}
+
+	rows, err := s.store.ListSettlementsByEvent(ctx, event.EventID)
+	if err != nil {
+		return nil, utils.ErrInternalDB
+	}
+	result := make([]models.SettlementDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toSettlementDTO(database.GetSettlementByUUIDRow{
+			SettlementUuid: row.SettlementUuid,
+			Amount:         row.Amount,
+			CreatedAt:      row.CreatedAt,
+			Reason:         row.Reason,
+			Status:         row.Status,
+			RejectReason:   row.RejectReason,
+			PayerName:      row.PayerName,
+			PayerUuid:      row.PayerUuid,
+			ReceiverName:   row.ReceiverName,
+			ReceiverUuid:   row.ReceiverUuid,
+			CreatedByName:  row.CreatedByName,
+			ReversalOfUuid: row.ReversalOfUuid,
+			ReversedByUuid: row.ReversedByUuid,
+		}))
+	}
+	return result, nil
+}
+
+// Lay chi tiet 1 settlement
+func (s *SettlementService) GetSettlement(ctx context.Context, userID int64, settlementUUIDStr string) (models.SettlementDTO, error) {
+	row, _, err := s.getSettlementForMember(ctx, userID, settlementUUIDStr)
+	if err != nil {
+		return models.SettlementDTO{}, err
+	}
+	return toSettlementDTO(row), nil
+}
+
+// Dao nguoc settlement ghi nham: tao but toan bu tru (doi chieu payer/receiver), khong xoa ban ghi goc.
+// Chi nguoi ghi nhan, payer, receiver hoac admin cua event duoc dao nguoc.
+// But toan bu tru cung can nguoi nhan (payer goc) xac nhan nhu moi settlement khac.
+func (s *SettlementService) ReverseSettlement(ctx context.Context, userID int64, settlementUUIDStr string, req models.ReverseSettlementRequest) (models.SettlementDTO, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return models.SettlementDTO{}, fmt.Errorf("%w: reason is required", utils.ErrInvalidInput)
+	}
+	row, me, err := s.getSettlementForMember(ctx, userID, settlementUUIDStr)
+	if err != nil {
+		return models.SettlementDTO{}, err
+	}
+	if row.Status != settlementStatusConfirmed {
+		return models.SettlementDTO{}, fmt.Errorf("%w: only confirmed settlements can be reversed, reject pending ones instead", utils.ErrInvalidInput)
+	}
+	if row.ReversalOfUuid.Valid {
+		return models.SettlementDTO{}, fmt.Errorf("%w: a reversal cannot be reversed", utils.ErrInvalidInput)
+	}
+	if row.ReversedByUuid.Valid {
+		return models.SettlementDTO{}, utils.ErrAlreadyExists
+	}
+	// Settlement chuyen so du luc xoa participant: dao nguoc se de lai so du cho nguoi da bi xoa, khong ai tra duoc
+	if row.Reason != nil && *row.Reason == balanceReassignReason {
+		return models.SettlementDTO{}, fmt.Errorf("%w: balance reassignment of a removed participant cannot be reversed", utils.ErrInvalidInput)
+	}
+	if err := s.checkSettlementParticipantsActive(ctx, row, "reversed"); err != nil {
+		return models.SettlementDTO{}, err
+	}
+
+	event, err := s.store.GetEventByID(ctx, row.EventID)
+	if err != nil {
+		return models.SettlementDTO{}, utils.ErrInternalDB
+	}
+	if event.IsClosed {
+		return models.SettlementDTO{}, utils.ErrEventClosed
+	}
+	isPayer := row.PayerID != nil && *row.PayerID == me.ParticipantID
+	isReceiver := row.ReceiverID != nil && *row.ReceiverID == me.ParticipantID
+	allowed := isPayer || isReceiver ||
+		(row.CreatedBy != nil && *row.CreatedBy == userID) ||
+		hasRole(me.Role, roleAdmin)
+	if !allowed || !hasRole(me.Role, roleMember) {
+		return models.SettlementDTO{}, utils.ErrPermissionDenied
+	}
+
+	params := database.CreateSettlementParams{
+		EventID:    row.EventID,
+		PayerID:    row.ReceiverID,
+		ReceiverID: row.PayerID,
+		Amount:     row.Amount,
+		CreatedBy:  &userID,
+		Reason:     &reason,
+		ReversalOf: &row.SettlementID,
+		Status:     settlementStatusPending,
+	}
+	// Payer goc la nguoi nhan cua but toan bu tru
+	if isPayer {
+		params.Status = settlementStatusConfirmed
+		params.ResolvedBy = &userID
+	}
+	var reversal database.Settlement
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		reversal, err = q.CreateSettlement(ctx, params)
+		if err != nil {
+			// Unique index tren reversal_of: co nguoi vua dao nguoc cung luc
+			return utils.ErrAlreadyExists
+		}
+		return recordActivity(ctx, q, row.EventID, userID, activitySettlementReversed, entitySettlement, row.SettlementUuid, map[string]any{
+			"reversalId": reversal.SettlementUuid.String(),
+			"amount":     row.Amount,
+			"reason":     reason,
+		})
+	})
+	if err != nil {
+		return models.SettlementDTO{}, err
+	}
+	created, err := s.store.GetSettlementByUUID(ctx, reversal.SettlementUuid)
+	if err != nil {
+		return models.SettlementDTO{}, utils.ErrInternalDB
+	}
+	return toSettlementDTO(created), nil
+}
+
+// Xac nhan settlement dang pending
+func (s *SettlementService) ConfirmSettlement(ctx context.Context, userID int64, settlementUUIDStr string) (models.SettlementDTO, error) {
+	return s.resolveSettlement(ctx, userID, settlementUUIDStr, settlementStatusConfirmed, nil)
+}
+
+// Tu choi settlement dang pending, bat buoc co ly do
+func (s *SettlementService) RejectSettlement(ctx context.Context, userID int64, settlementUUIDStr string, req models.RejectSettlementRequest) (models.SettlementDTO, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return models.SettlementDTO{}, fmt.Errorf("%w: reason is required", utils.ErrInvalidInput)
+	}
+	return s.resolveSettlement(ctx, userID, settlementUUIDStr, settlementStatusRejected, &reason)
+}
+
+// Helper: nguoi nhan xac nhan/tu choi; nguoi nhan la khach (khong co user) thi admin/owner lam thay
+func (s *SettlementService) resolveSettlement(ctx context.Context, userID int64, settlementUUIDStr string, status string, reason *string) (models.SettlementDTO, error) {
+	row, me, err := s.getSettlementForMember(ctx, userID, settlementUUIDStr)
+	if err != nil {
+		return models.SettlementDTO{}, err
+	}
+	if !hasRole(me.Role, roleMember) {
+		return models.SettlementDTO{}, utils.ErrPermissionDenied
+	}
+	if row.Status != settlementStatusPending {
+		return models.SettlementDTO{}, fmt.Errorf("%w: settlement is already %s", utils.ErrInvalidInput, row.Status)
+	}
+	event, err := s.store.GetEventByID(ctx, row.EventID)
+	if err != nil {
+		return models.SettlementDTO{}, utils.ErrInternalDB
+	}
+	if row.ReceiverUserID != nil {
+		if *row.ReceiverUserID != userID {
+			return models.SettlementDTO{}, utils.ErrPermissionDenied
+		}
+	} else if !hasRole(me.Role, roleAdmin) {
+		return models.SettlementDTO{}, utils.ErrPermissionDenied
+	}
+	if event.IsClosed {
+		return models.SettlementDTO{}, utils.ErrEventClosed
+	}
+	// So du cua nguoi da bi xoa da chot, xac nhan se de lai so du khong ai tra duoc
+	if status == settlementStatusConfirmed {
+		if err := s.checkSettlementParticipantsActive(ctx, row, "confirmed"); err != nil {
+			return models.SettlementDTO{}, err
+		}
+	}
+
+	action := activitySettlementConfirmed
+	if status == settlementStatusRejected {
+		action = activitySettlementRejected
+	}
+	err = s.store.ExecTx(ctx, func(q *database.Queries) error {
+		affected, err := q.ResolveSettlement(ctx, database.ResolveSettlementParams{
+			SettlementID: row.SettlementID,
+			Status:       status,
+			ResolvedBy:   &userID,
+			RejectReason: reason,
+		})
+		if err != nil {
+			return utils.ErrInternalDB
+		}
+		if affected == 0 {
+			return fmt.Errorf("%w: settlement is no longer pending", utils.ErrInvalidInput)
+		}
+		return recordActivity(ctx, q, row.EventID, userID, action, entitySettlement, row.SettlementUuid, map[string]any{
+			"amount": row.Amount,
+			"reason": reason,
+		})
 	})
-	return err
+	if err != nil {
+		return models.SettlementDTO{}, err
+	}
+	updated, err := s.store.GetSettlementByUUID(ctx, row.SettlementUuid)
+	if err != nil {
+		return models.SettlementDTO{}, utils.ErrInternalDB
+	}
+	return toSettlementDTO(updated), nil
+}
+
+// Helper: payer va receiver cua settlement phai con trong event. verb dung trong thong bao loi
+func (s *SettlementService) checkSettlementParticipantsActive(ctx context.Context, row database.GetSettlementByUUIDRow, verb string) error {
+	for _, id := range []*int64{row.PayerID, row.ReceiverID} {
+		if id == nil {
+			continue
+		}
+		p, err := s.store.GetParticipantByID(ctx, *id)
+		if err != nil {
+			return utils.ErrInternalDB
+		}
+		if p.RemovedAt.Valid {
+			return fmt.Errorf("%w: %s has been removed from the event, settlement cannot be %s", utils.ErrInvalidInput, p.Name, verb)
+		}
+	}
+	return nil
+}
+
+// Helper: lay settlement va kiem tra user la thanh vien cua event
+func (s *SettlementService) getSettlementForMember(ctx context.Context, userID int64, settlementUUIDStr string) (database.GetSettlementByUUIDRow, database.Participant, error) {
+	settlementUUID, err := utils.StringToUUID(settlementUUIDStr)
+	if err != nil {
+		return database.GetSettlementByUUIDRow{}, database.Participant{}, utils.ErrInvalidInput
+	}
+	row, err := s.store.GetSettlementByUUID(ctx, settlementUUID)
+	if err != nil {
+		return database.GetSettlementByUUIDRow{}, database.Participant{}, utils.ErrNotFound
+	}
+	me, err := s.store.GetParticipantByEventAndUser(ctx, database.GetParticipantByEventAndUserParams{
+		EventID: row.EventID, UserID: &userID,
+	})
+	if err != nil {
+		return database.GetSettlementByUUIDRow{}, database.Participant{}, utils.ErrPermissionDenied
+	}
+	return row, me, nil
+}
+
+func toSettlementDTO(row database.GetSettlementByUUIDRow) models.SettlementDTO {
+	dto := models.SettlementDTO{
+		ID:        row.SettlementUuid.String(),
+		Payer:     models.SettlementParty{ID: row.PayerUuid.String(), Name: row.PayerName},
+		Receiver:  models.SettlementParty{ID: row.ReceiverUuid.String(), Name: row.ReceiverName},
+		Amount:    row.Amount,
+		Status:    row.Status,
+		CreatedAt: row.CreatedAt.Time,
+	}
+	if row.Reason != nil {
+		dto.Reason = *row.Reason
+	}
+	if row.RejectReason != nil {
+		dto.RejectReason = *row.RejectReason
+	}
+	if row.CreatedByName != nil {
+		dto.CreatedBy = *row.CreatedByName
+	}
+	if row.ReversalOfUuid.Valid {
+		dto.ReversalOf = uuid.UUID(row.ReversalOfUuid.Bytes).String()
+	}
+	if row.ReversedByUuid.Valid {
+		dto.ReversedBy = uuid.UUID(row.ReversedByUuid.Bytes).String()
+	}
+	return dto
 }
\ No newline at end of file
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	database "BACKEND/internal/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	trashPurgeInterval    = time.Hour
+)
+
+// Helper: thoi gian luu thung rac, chua cau hinh thi dung mac dinh
+func trashRetention(d time.Duration) time.Duration {
+	if d <= 0 {
+		return defaultTrashRetention
+	}
+	return d
+}
+
+// Helper: thoi diem ban ghi trong thung rac bi xoa han
+func purgeAt(deletedAt pgtype.Timestamptz, retention time.Duration) time.Time {
+	return deletedAt.Time.Add(retention)
+}
+
+// Helper: da qua han luu tru (job purge chua kip chay) thi coi nhu khong con
+func trashExpired(deletedAt pgtype.Timestamptz, retention time.Duration) bool {
+	return !purgeAt(deletedAt, retention).After(time.Now())
+}
+
+// Job xoa han expense/event nam trong thung rac qua han luu tru
+type TrashPurger struct {
+	store     database.Store
+	retention time.Duration
+	uploads   *UploadService // Xoa file dinh kem tren storage, nil thi bo qua
+}
+
+// Khoi tao TrashPurger
+func NewTrashPurger(store database.Store, retention time.Duration, uploads *UploadService) *TrashPurger {
+	return &TrashPurger{store: store, retention: trashRetention(retention), uploads: uploads}
+}
+
+// Chay purge ngay va lap lai moi trashPurgeInterval cho den khi ctx bi huy
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+	for {
+		p.purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Xoa han event truoc (cascade ca expense ben trong) roi toi expense le
+func (p *TrashPurger) purge(ctx context.Context) {
+	cutoff := time.Now().Add(-p.retention)
+	p.purgeAttachmentFiles(ctx, cutoff)
+	events, err := p.store.PurgeDeletedEvents(ctx, cutoff)
+	if err != nil {
+		log.Println("Purge deleted events failed:", err)
+		return
+	}
+	expenses, err := p.store.PurgeDeletedExpenses(ctx, cutoff)
+	if err != nil {
+		log.Println("Purge deleted expenses failed:", err)
+		return
+	}
+	if events > 0 || expenses > 0 {
+		log.Printf("Purged %d events and %d expenses from trash", events, expenses)
+	}
+}
+
+// Cascade chi xoa ban ghi file dinh kem, file tren storage phai xoa truoc. Loi thi chi log
+func (p *TrashPurger) purgeAttachmentFiles(ctx context.Context, cutoff time.Time) {
+	if p.uploads == nil {
+		return
+	}
+	keys, err := p.store.ListPurgeableAttachmentKeys(ctx, cutoff)
+	if err != nil {
+		log.Println("List purgeable attachments failed:", err)
+		return
+	}
+	for _, key := range keys {
+		if err := p.uploads.DeletePrivateFile(ctx, key); err != nil {
+			log.Println("Delete attachment file failed:", key, err)
+		}
+	}
+}
@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"mime/multipart"
+	"net/http"
 	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
@@ -11,7 +15,8 @@ import (
 )
 
 type UploadService struct {
-	cld *cloudinary.Cloudinary
+	cld    *cloudinary.Cloudinary
+	client *http.Client // Tai file private tu cloudinary ve de tra cho client
 }
 
 // Khoi tao UploadService voi cloudinary url
@@ -20,7 +25,7 @@ func NewUploadService(cloudinaryURL string) (*UploadService, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &UploadService{cld: cld}, nil
+	return &UploadService{cld: cld, client: &http.Client{Timeout: time.Minute}}, nil
 }
 
 // Upload file len cloudinary va tra url
@@ -36,4 +41,74 @@ func (s *UploadService) UploadImage(ctx context.Context, file multipart.File, fi
 		return "", err
 	}
 	return resp.SecureURL, nil
-}
\ No newline at end of file
+}
+
+// Anh thu nho cho hoa don (PDF lay trang dau), tao san luc upload
+const receiptThumbnailTransformation = "c_limit,w_320,h_320,f_jpg"
+
+// File da luu private tren cloudinary. URL co chu ky, chi server dung de tai ve
+type StoredFile struct {
+	Key          string
+	URL          string
+	ThumbnailURL string
+}
+
+// Upload file private (type authenticated), khong truy cap duoc neu khong co url co chu ky
+func (s *UploadService) UploadPrivateFile(ctx context.Context, file io.Reader, folder string, publicID string) (StoredFile, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	resp, err := s.cld.Upload.Upload(ctx, file, uploader.UploadParams{
+		Folder:       folder,
+		PublicID:     publicID,
+		ResourceType: "image",
+		Type:         api.Authenticated,
+		Eager:        receiptThumbnailTransformation,
+	})
+	if err != nil {
+		return StoredFile{}, err
+	}
+	if resp.Error.Message != "" {
+		return StoredFile{}, errors.New(resp.Error.Message)
+	}
+	stored := StoredFile{Key: resp.PublicID, URL: resp.SecureURL}
+	if len(resp.Eager) > 0 {
+		stored.ThumbnailURL = resp.Eager[0].SecureURL
+	}
+	return stored, nil
+}
+
+// Mo stream file private tu url co chu ky, nguoi goi phai Close
+func (s *UploadService) OpenPrivateFile(ctx context.Context, url string) (io.ReadCloser, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("storage responded %s", resp.Status)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// Xoa han file private (ca anh thu nho)
+func (s *UploadService) DeletePrivateFile(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	resp, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     key,
+		Type:         api.Authenticated,
+		ResourceType: "image",
+		Invalidate:   api.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error.Message != "" {
+		return errors.New(resp.Error.Message)
+	}
+	return nil
+}
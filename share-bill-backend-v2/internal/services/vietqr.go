@@ -0,0 +1,24 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+
+	"BACKEND/internal/money"
+)
+
+const vietQRTemplate = "compact2"
+
+// Noi dung chuyen khoan mac dinh cho cac khoan thanh toan trong event
+func vietQRContent(eventName string) string {
+	return fmt.Sprintf("Thanh toan Event %s", eventName)
+}
+
+// Helper: tao URL anh VietQR (quicklink)
+// Format: https://img.vietqr.io/image/<BANK_ID>-<ACCOUNT_NO>-<TEMPLATE>.png?amount=<AMOUNT>&addInfo=<CONTENT>&accountName=<NAME>
+// Bank va so tai khoan do user nhap nen phai escape, tranh "/", "?", "#" lam hong URL
+func buildVietQRURL(bankName, accountNo, accountName string, amount money.Amount, content string) string {
+	return fmt.Sprintf("https://img.vietqr.io/image/%s-%s-%s.png?amount=%s&addInfo=%s&accountName=%s",
+		url.PathEscape(bankName), url.PathEscape(accountNo), vietQRTemplate, amount, url.QueryEscape(content), url.QueryEscape(accountName),
+	)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"net/url"
+	"testing"
+
+	"BACKEND/internal/money"
+)
+
+func TestBuildVietQRURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		bank      string
+		account   string
+		wantPath  string
+		wantQuery url.Values
+	}{
+		{
+			name:     "plain",
+			bank:     "VCB",
+			account:  "0123456789",
+			wantPath: "/image/VCB-0123456789-compact2.png",
+		},
+		{
+			name:     "reserved characters stay in the path",
+			bank:     "VCB/../x",
+			account:  "12?amount=1#3",
+			wantPath: "/image/VCB%2F..%2Fx-12%3Famount=1%233-compact2.png",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := buildVietQRURL(tt.bank, tt.account, "Nguyen Van A", money.FromUnits(50000), "Thanh toan Event Da Lat")
+			u, err := url.Parse(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Host != "img.vietqr.io" || u.EscapedPath() != tt.wantPath || u.Fragment != "" {
+				t.Fatalf("got host %q path %q fragment %q", u.Host, u.EscapedPath(), u.Fragment)
+			}
+			q := u.Query()
+			if q.Get("amount") != "50000" || q.Get("accountName") != "Nguyen Van A" || q.Get("addInfo") != "Thanh toan Event Da Lat" {
+				t.Fatalf("unexpected query %v", q)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package settlement
+
+import (
+	"math/big"
+	"sort"
+
+	"BACKEND/internal/money"
+)
+
+// Expense la 1 khoan chi: ai da tra bao nhieu va ai phai chiu bao nhieu
+type Expense struct {
+	Payers []Balance
+	Shares []Balance
+}
+
+type pair struct {
+	from string
+	to   string
+}
+
+// PairwiseDebts tinh ma tran no truc tiep: moi nguoi huong loi no tung payer theo ti le so tien payer da tra,
+// tru di cac settlement da tra giua 2 nguoi. Tra thua cho ai thi nguoi do no nguoc lai phan thua.
+// net = true thi gop 2 chieu cua moi cap thanh 1 dong.
+func PairwiseDebts(expenses []Expense, settled []Transfer, net bool, currency string) []Transfer {
+	owed := make(map[pair]money.Amount)
+	for _, e := range expenses {
+		weights := make([]*big.Rat, len(e.Payers))
+		for i, p := range e.Payers {
+			weights[i] = big.NewRat(p.Amount.Cents(), 1)
+		}
+		for _, share := range e.Shares {
+			parts, err := money.AllocateRat(share.Amount, weights, currency)
+			if err != nil {
+				continue // Expense khong co payer hop le
+			}
+			for i, p := range e.Payers {
+				if p.ID != share.ID && parts[i] != 0 {
+					owed[pair{share.ID, p.ID}] += parts[i]
+				}
+			}
+		}
+	}
+	for _, t := range settled {
+		if t.From != t.To {
+			owed[pair{t.From, t.To}] -= t.Amount
+		}
+	}
+
+	// Gom theo cap (a < b) de xu ly phan tra thua va netting
+	seen := make(map[pair]bool)
+	var transfers []Transfer
+	for k := range owed {
+		a, b := k.from, k.to
+		if a > b {
+			a, b = b, a
+		}
+		if seen[pair{a, b}] {
+			continue
+		}
+		seen[pair{a, b}] = true
+
+		ab, ba := owed[pair{a, b}], owed[pair{b, a}]
+		// Tra thua o chieu nay thanh no o chieu nguoc lai
+		if ab < 0 {
+			ba, ab = ba-ab, 0
+		}
+		if ba < 0 {
+			ab, ba = ab-ba, 0
+		}
+		if net {
+			ab, ba = ab-money.Min(ab, ba), ba-money.Min(ab, ba)
+		}
+		if ab > 0 {
+			transfers = append(transfers, Transfer{From: a, To: b, Amount: ab})
+		}
+		if ba > 0 {
+			transfers = append(transfers, Transfer{From: b, To: a, Amount: ba})
+		}
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].From != transfers[j].From {
+			return transfers[i].From < transfers[j].From
+		}
+		return transfers[i].To < transfers[j].To
+	})
+	return transfers
+}
@@ -0,0 +1,194 @@
+// Package settlement lap ke hoach tra no (ai chuyen cho ai bao nhieu) tu balance cua cac thanh vien.
+// Moi planner deu deterministic: cung input luon cho ra cung ke hoach.
+package settlement
+
+import (
+	"sort"
+
+	"BACKEND/internal/money"
+)
+
+// ExactLimit la so nguoi con no/duoc nhan toi da de tim loi giai toi uu chinh xac (2^n trang thai)
+const ExactLimit = 15
+
+// Balance cua 1 thanh vien: duong la duoc nhan lai, am la phai tra
+type Balance struct {
+	ID     string
+	Amount money.Amount
+}
+
+// Transfer la 1 giao dich trong ke hoach
+type Transfer struct {
+	From   string
+	To     string
+	Amount money.Amount
+}
+
+// Greedy ghep nguoi no nhieu nhat voi nguoi duoc nhan nhieu nhat cho den khi het.
+// Hoa thi uu tien ID nho hon.
+func Greedy(balances []Balance) []Transfer {
+	return greedy(normalize(balances))
+}
+
+// MinTransfers tim ke hoach it giao dich nhat.
+// Toi da ExactLimit nguoi thi tim chinh xac, nhieu hon thi dung heuristic.
+func MinTransfers(balances []Balance) []Transfer {
+	bs := normalize(balances)
+	if len(bs) <= ExactLimit {
+		return exact(bs)
+	}
+	return heuristic(bs)
+}
+
+// Bo nguoi da can bang va sap xep theo ID de ket qua khong phu thuoc thu tu input
+func normalize(balances []Balance) []Balance {
+	bs := make([]Balance, 0, len(balances))
+	for _, b := range balances {
+		if b.Amount != 0 {
+			bs = append(bs, b)
+		}
+	}
+	sort.SliceStable(bs, func(i, j int) bool { return bs[i].ID < bs[j].ID })
+	return bs
+}
+
+func greedy(bs []Balance) []Transfer {
+	var debtors, creditors []Balance
+	for _, b := range bs {
+		if b.Amount < 0 {
+			debtors = append(debtors, Balance{ID: b.ID, Amount: -b.Amount})
+		} else {
+			creditors = append(creditors, b)
+		}
+	}
+
+	var transfers []Transfer
+	for len(debtors) > 0 && len(creditors) > 0 {
+		d := largest(debtors)
+		c := largest(creditors)
+		amount := money.Min(debtors[d].Amount, creditors[c].Amount)
+		transfers = append(transfers, Transfer{From: debtors[d].ID, To: creditors[c].ID, Amount: amount})
+		debtors[d].Amount -= amount
+		creditors[c].Amount -= amount
+		if debtors[d].Amount <= 0 {
+			debtors = append(debtors[:d], debtors[d+1:]...)
+		}
+		if creditors[c].Amount <= 0 {
+			creditors = append(creditors[:c], creditors[c+1:]...)
+		}
+	}
+	return transfers
+}
+
+// Vi tri phan tu lon nhat; list da sap xep theo ID nen hoa thi lay phan tu dung truoc
+func largest(bs []Balance) int {
+	best := 0
+	for i := 1; i < len(bs); i++ {
+		if bs[i].Amount > bs[best].Amount {
+			best = i
+		}
+	}
+	return best
+}
+
+// So giao dich toi thieu = n - so nhom tong bang 0 nhieu nhat co the chia ra.
+// dp[mask] = so nhom tong 0 nhieu nhat khi xep lan luot cac phan tu trong mask.
+// Moi nhom k nguoi tra bang greedy voi toi da k-1 giao dich.
+func exact(bs []Balance) []Transfer {
+	n := len(bs)
+	if n == 0 {
+		return nil
+	}
+	full := 1<<n - 1
+	sum := make([]money.Amount, full+1)
+	dp := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		low := lowestBit(mask)
+		sum[mask] = sum[mask&(mask-1)] + bs[low].Amount
+		best := -1
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && dp[mask^(1<<i)] > best {
+				best = dp[mask^(1<<i)]
+			}
+		}
+		dp[mask] = best
+		if sum[mask] == 0 {
+			dp[mask]++
+		}
+	}
+
+	// Lan nguoc de tach cac nhom: moi lan gap mask con co tong 0 la ket thuc 1 nhom
+	var transfers []Transfer
+	var group []Balance
+	mask := full
+	for mask != 0 {
+		target := dp[mask]
+		if sum[mask] == 0 {
+			target--
+		}
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && dp[mask^(1<<i)] == target {
+				group = append(group, bs[i])
+				mask ^= 1 << i
+				break
+			}
+		}
+		if sum[mask] == 0 {
+			sort.SliceStable(group, func(a, b int) bool { return group[a].ID < group[b].ID })
+			transfers = append(transfers, greedy(group)...)
+			group = nil
+		}
+	}
+	return transfers
+}
+
+func lowestBit(mask int) int {
+	i := 0
+	for mask&1 == 0 {
+		mask >>= 1
+		i++
+	}
+	return i
+}
+
+// Heuristic cho nhom lon: tra truoc cac cap no/nhan bang nhau (1 giao dich xoa 2 nguoi), phan con lai dung greedy
+func heuristic(bs []Balance) []Transfer {
+	var transfers []Transfer
+	used := make([]bool, len(bs))
+	for i := range bs {
+		if used[i] || bs[i].Amount >= 0 {
+			continue
+		}
+		for j := range bs {
+			if !used[j] && bs[j].Amount == -bs[i].Amount {
+				transfers = append(transfers, Transfer{From: bs[i].ID, To: bs[j].ID, Amount: bs[j].Amount})
+				used[i], used[j] = true, true
+				break
+			}
+		}
+	}
+	var rest []Balance
+	for i, b := range bs {
+		if !used[i] {
+			rest = append(rest, b)
+		}
+	}
+	return append(transfers, greedy(rest)...)
+}
+
+// Hub: moi nguoi no chuyen cho hub (collector), hub hoan tien lai cho tung nguoi duoc nhan.
+// Thu tu: cac khoan thu vao hub truoc, sau do cac khoan hoan tien, moi nhom sap xep theo ID.
+func Hub(balances []Balance, hubID string) []Transfer {
+	var incoming, outgoing []Transfer
+	for _, b := range normalize(balances) {
+		if b.ID == hubID {
+			continue
+		}
+		if b.Amount < 0 {
+			incoming = append(incoming, Transfer{From: b.ID, To: hubID, Amount: -b.Amount})
+		} else {
+			outgoing = append(outgoing, Transfer{From: hubID, To: b.ID, Amount: b.Amount})
+		}
+	}
+	return append(incoming, outgoing...)
+}
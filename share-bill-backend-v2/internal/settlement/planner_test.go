@@ -0,0 +1,227 @@
+package settlement
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"BACKEND/internal/money"
+)
+
+// Helper: tao balance theo thu tu ID A, B, C... tu so cent
+func balances(amounts ...int64) []Balance {
+	bs := make([]Balance, len(amounts))
+	for i, a := range amounts {
+		bs[i] = Balance{ID: string(rune('A' + i)), Amount: money.FromCents(a)}
+	}
+	return bs
+}
+
+// Helper: thuc hien ke hoach thi moi balance phai ve 0, khong co giao dich <= 0 hay tu chuyen cho minh
+func checkSettles(t *testing.T, bs []Balance, transfers []Transfer) {
+	t.Helper()
+	left := make(map[string]money.Amount)
+	for _, b := range bs {
+		left[b.ID] += b.Amount
+	}
+	for _, tr := range transfers {
+		if tr.Amount <= 0 || tr.From == tr.To {
+			t.Fatalf("invalid transfer %+v", tr)
+		}
+		left[tr.From] += tr.Amount
+		left[tr.To] -= tr.Amount
+	}
+	for id, a := range left {
+		if a != 0 {
+			t.Fatalf("%s left with %d after %v", id, a, transfers)
+		}
+	}
+}
+
+// Helper: so giao dich toi thieu tinh bang vet can (n - so nhom tong 0 nhieu nhat), doc lap voi exact()
+func bruteForceMin(bs []Balance) int {
+	var amounts []money.Amount
+	for _, b := range bs {
+		if b.Amount != 0 {
+			amounts = append(amounts, b.Amount)
+		}
+	}
+	var best func(rest []money.Amount) int
+	best = func(rest []money.Amount) int {
+		if len(rest) == 0 {
+			return 0
+		}
+		// Nhom chua phan tu dau tien: thu moi tap con cua phan con lai
+		first, others := rest[0], rest[1:]
+		result := 0
+		for mask := 0; mask < 1<<len(others); mask++ {
+			sum := first
+			var remain []money.Amount
+			for i, a := range others {
+				if mask&(1<<i) != 0 {
+					sum += a
+				} else {
+					remain = append(remain, a)
+				}
+			}
+			if sum == 0 {
+				if groups := 1 + best(remain); groups > result {
+					result = groups
+				}
+			}
+		}
+		return result
+	}
+	return len(amounts) - best(amounts)
+}
+
+func TestMinTransfers(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances []Balance
+		want     int
+	}{
+		{"empty", nil, 0},
+		{"all settled", balances(0, 0, 0), 0},
+		{"one pair", balances(-1000, 1000), 1},
+		{"one creditor", balances(-1000, -500, 1500), 2},
+		{"two independent pairs", balances(-600, -400, 400, 600), 2},
+		// Greedy can 4 giao dich, toi uu chi 3 (D->A, E->B, E->C)
+		{"beats greedy", balances(9, 3, 7, -9, -10), 3},
+		{"chain", balances(-300, 100, -100, 300), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MinTransfers(tt.balances)
+			checkSettles(t, tt.balances, got)
+			if len(got) != tt.want {
+				t.Fatalf("MinTransfers = %d transfers %v, want %d", len(got), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinTransfersMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for k := 0; k < 300; k++ {
+		bs := randomBalances(r, 2+r.Intn(7), 20)
+		got := MinTransfers(bs)
+		checkSettles(t, bs, got)
+		if want := bruteForceMin(bs); len(got) != want {
+			t.Fatalf("%v: MinTransfers = %d transfers, brute force = %d", bs, len(got), want)
+		}
+		if greedy := Greedy(bs); len(got) > len(greedy) {
+			t.Fatalf("%v: MinTransfers (%d) worse than Greedy (%d)", bs, len(got), len(greedy))
+		}
+	}
+}
+
+func TestPlannersDeterministic(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for _, n := range []int{6, ExactLimit, ExactLimit + 5} {
+		bs := randomBalances(r, n, 50)
+		want := MinTransfers(bs)
+		wantGreedy := Greedy(bs)
+		wantHub := Hub(bs, bs[0].ID)
+		for i := 0; i < 10; i++ {
+			shuffled := append([]Balance(nil), bs...)
+			r.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+			if got := MinTransfers(shuffled); !reflect.DeepEqual(got, want) {
+				t.Fatalf("n=%d: MinTransfers depends on input order: %v vs %v", n, got, want)
+			}
+			if got := Greedy(shuffled); !reflect.DeepEqual(got, wantGreedy) {
+				t.Fatalf("n=%d: Greedy depends on input order", n)
+			}
+			if got := Hub(shuffled, bs[0].ID); !reflect.DeepEqual(got, wantHub) {
+				t.Fatalf("n=%d: Hub depends on input order", n)
+			}
+		}
+	}
+}
+
+func TestMinTransfersHeuristic(t *testing.T) {
+	// Tren ExactLimit: cac cap no/nhan bang nhau phai duoc ghep truc tiep
+	var amounts []int64
+	for i := int64(1); i <= 9; i++ {
+		amounts = append(amounts, -i*100, i*100)
+	}
+	bs := balances(amounts...)
+	if len(bs) <= ExactLimit {
+		t.Fatalf("test needs more than %d balances", ExactLimit)
+	}
+	got := MinTransfers(bs)
+	checkSettles(t, bs, got)
+	if len(got) != 9 {
+		t.Fatalf("heuristic = %d transfers %v, want 9", len(got), got)
+	}
+
+	r := rand.New(rand.NewSource(3))
+	for k := 0; k < 50; k++ {
+		bs := randomBalances(r, ExactLimit+1+r.Intn(10), 1000)
+		got := MinTransfers(bs)
+		checkSettles(t, bs, got)
+		if nonZero := countNonZero(bs); len(got) > nonZero-1 {
+			t.Fatalf("heuristic used %d transfers for %d balances", len(got), nonZero)
+		}
+	}
+}
+
+func TestHub(t *testing.T) {
+	bs := balances(-500, 300, -200, 400)
+	got := Hub(bs, "B")
+	want := []Transfer{
+		{From: "A", To: "B", Amount: 500},
+		{From: "C", To: "B", Amount: 200},
+		{From: "B", To: "D", Amount: 400},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Hub = %v, want %v", got, want)
+	}
+	checkSettles(t, bs, got)
+
+	// Hub khong co balance (collector da can bang) van phai thu/chi dung
+	bs = balances(-500, 0, -200, 700)
+	got = Hub(bs, "B")
+	checkSettles(t, bs, got)
+	if len(got) != 3 {
+		t.Fatalf("Hub = %v, want 3 transfers", got)
+	}
+}
+
+func TestGreedy(t *testing.T) {
+	bs := balances(-700, -300, 600, 400)
+	got := Greedy(bs)
+	want := []Transfer{
+		{From: "A", To: "C", Amount: 600},
+		{From: "B", To: "D", Amount: 300},
+		{From: "A", To: "D", Amount: 100},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Greedy = %v, want %v", got, want)
+	}
+	checkSettles(t, bs, got)
+}
+
+// Helper: n balance ngau nhien tong bang 0
+func randomBalances(r *rand.Rand, n int, max int) []Balance {
+	bs := make([]Balance, n)
+	var sum money.Amount
+	for i := 0; i < n-1; i++ {
+		a := money.FromCents(int64(r.Intn(2*max+1) - max))
+		bs[i] = Balance{ID: fmt.Sprintf("p%02d", i), Amount: a}
+		sum += a
+	}
+	bs[n-1] = Balance{ID: fmt.Sprintf("p%02d", n-1), Amount: -sum}
+	return bs
+}
+
+func countNonZero(bs []Balance) int {
+	n := 0
+	for _, b := range bs {
+		if b.Amount != 0 {
+			n++
+		}
+	}
+	return n
+}
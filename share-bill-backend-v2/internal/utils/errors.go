@@ -19,10 +19,18 @@ var (
 	ErrAlreadyExists  = errors.New("resource already exists")
 	ErrBalanceNotZero = errors.New("cannot leave event: you have unsettled balance")
 	ErrEventClosed    = errors.New("event is closed")
+	// Client sua tren ban cu (If-Match khong khop version hien tai)
+	ErrVersionConflict = errors.New("resource has been modified by someone else")
+
+	// 410 Gone
+	ErrInviteInvalid = errors.New("invite link is invalid, expired or revoked")
 
 	// Rate limit / client errors
 	ErrTooManyRequests = errors.New("too many requests")
 
 	// 500 Internal Server Error
 	ErrInternalDB = errors.New("internal database error")
+
+	// 503 Service Unavailable: chua cau hinh storage (CLOUDINARY_URL)
+	ErrStorageUnavailable = errors.New("file storage is not configured")
 )
\ No newline at end of file
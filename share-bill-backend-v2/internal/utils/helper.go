@@ -84,6 +84,22 @@ func MapError(c *fiber.Ctx, err error) error {
 	case errors.Is(err, ErrBalanceNotZero):
 		statusCode = fiber.StatusConflict
 		errorCode = "BALANCE_NOT_ZERO"
+	case errors.Is(err, ErrEventClosed):
+		statusCode = fiber.StatusConflict
+		errorCode = "EVENT_CLOSED"
+	case errors.Is(err, ErrVersionConflict):
+		statusCode = fiber.StatusConflict
+		errorCode = "VERSION_CONFLICT"
+
+	// 410 Gone
+	case errors.Is(err, ErrInviteInvalid):
+		statusCode = fiber.StatusGone
+		errorCode = "INVITE_INVALID"
+
+	// 503 Service Unavailable
+	case errors.Is(err, ErrStorageUnavailable):
+		statusCode = fiber.StatusServiceUnavailable
+		errorCode = "STORAGE_UNAVAILABLE"
 
 	// 500 Internal Server Error (Default)
 	default:
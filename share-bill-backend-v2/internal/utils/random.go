@@ -1,6 +1,8 @@
 package utils
 
 import (
+	crand "crypto/rand"
+	"encoding/base64"
 	"math/rand"
 	"time"
 )
@@ -14,4 +16,13 @@ func GenerateOTP() string {
 		otp[i] = charset[r.Intn(len(charset))]
 	}
 	return string(otp)
-}
\ No newline at end of file
+}
+
+// Token ngau nhien an toan (crypto/rand), dang base64 URL-safe
+func GenerateToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := crand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}